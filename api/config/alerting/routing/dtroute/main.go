@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/alerting/routing"
+	"github.com/dtcookie/dynatrace/apis/problems"
+	"github.com/dtcookie/dynatrace/notification"
+	"github.com/dtcookie/dynatrace/rest"
+	"github.com/dtcookie/dynatrace/rest/credentials"
+)
+
+type values []string
+
+func (me *values) String() string {
+	return strings.Join(*me, ",")
+}
+
+func (me *values) Set(value string) error {
+	*me = append(*me, value)
+	return nil
+}
+
+func main() {
+	var environmentURL, apiToken string
+	var problemID string
+	var managementZones values
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&environmentURL, "environment-url", os.Getenv("DT_ENVIRONMENT_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.StringVar(&problemID, "problem", "", "")
+	flagSet.Var(&managementZones, "mz", "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if environmentURL == "" || apiToken == "" || (problemID == "") == (flagSet.NArg() == 0) || flagSet.NArg() > 1 {
+		usage()
+		os.Exit(2)
+	}
+	environmentURL = strings.TrimSuffix(environmentURL, "/")
+
+	var problem *routing.Problem
+	if problemID != "" {
+		details, err := problems.NewAPI(&rest.Config{}, environmentURL, credentials.New(apiToken)).Get(problemID)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		problem = routing.FromProblem(details, managementZones...)
+	} else {
+		var err error
+		if problem, err = readPayload(flagSet.Arg(0), managementZones); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	simulator, err := routing.Fetch(environmentURL+"/api/config/v1", apiToken)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(simulator.Simulate(problem).String())
+}
+
+// readPayload accepts either the payload of a default problem notification or the details of a problem
+func readPayload(file string, managementZones []string) (*routing.Problem, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var payload notification.Default
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	if payload.PID != "" || payload.ProblemID != "" {
+		return routing.FromNotification(&payload, managementZones...), nil
+	}
+	var details problems.Problem
+	if err := json.Unmarshal(data, &details); err != nil {
+		return nil, err
+	}
+	return routing.FromProblem(&details, managementZones...), nil
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtroute [-environment-url <environment-url>] [-api-token <api-token>] [-mz <management-zone-id>]... (-problem <problem-id> | <payload-file>)")
+	fmt.Println("  Evaluates the alerting profiles and notifications of the environment against a problem")
+	fmt.Println("  and reports which notifications fire and when.")
+	fmt.Println("  -problem  fetches the details of the problem with the given ID")
+	fmt.Println("  <payload-file>  contains the JSON payload of a default problem notification or the details of a problem")
+	fmt.Println("  -mz       the ID of a management zone the problem belongs to, can be specified multiple times")
+	fmt.Println("  Hint: you can also define the environment variables DT_ENVIRONMENT_URL and DT_API_TOKEN")
+}
@@ -0,0 +1,191 @@
+package routing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+	"github.com/dtcookie/dynatrace/api/config/common"
+	"github.com/dtcookie/opt"
+)
+
+// Evaluate determines whether the given alerting profile applies to the problem.
+// The returned Match explains why if it doesn't.
+func Evaluate(profile *alerting.Profile, problem *Problem) *Match {
+	match := &Match{Profile: profile}
+	if mzID := opt.String(profile.MzID); mzID != "" && !contains(problem.ManagementZones, mzID) {
+		match.Reason = fmt.Sprintf("problem is not part of management zone %s", mzID)
+		return match
+	}
+	for idx, rule := range profile.Rules {
+		if rule.SeverityLevel != problem.SeverityLevel {
+			continue
+		}
+		if !TagsMatch(rule.TagFilter, problem.Tags) {
+			continue
+		}
+		match.Rule = rule
+		match.RuleIndex = idx
+		break
+	}
+	if match.Rule == nil {
+		match.Reason = fmt.Sprintf("no severity rule matches severity %s and the tags of the problem", problem.SeverityLevel)
+		return match
+	}
+	matches, err := EventsMatch(profile.EventTypeFilters, problem)
+	if err != nil {
+		match.Reason = err.Error()
+		return match
+	}
+	if !matches {
+		match.Reason = "event filters don't match"
+		return match
+	}
+	match.At = problem.Start.Add(delay(match.Rule))
+	if !problem.End.IsZero() && problem.End.Before(match.At) {
+		match.Reason = fmt.Sprintf("problem closed before the delay of %d minutes passed", match.Rule.DelayInMinutes)
+		return match
+	}
+	match.Matches = true
+	return match
+}
+
+// TagsMatch evaluates the tag filter of a severity rule against the tags of the affected entities
+func TagsMatch(filter *alerting.ProfileTagFilter, tags []*common.TagFilter) bool {
+	if filter == nil || filter.IncludeMode == alerting.IncludeModes.None || len(filter.TagFilters) == 0 {
+		return true
+	}
+	for _, required := range filter.TagFilters {
+		found := false
+		for _, tag := range tags {
+			if tagMatches(required, tag) {
+				found = true
+				break
+			}
+		}
+		if found && filter.IncludeMode == alerting.IncludeModes.IncludeAny {
+			return true
+		}
+		if !found && filter.IncludeMode == alerting.IncludeModes.IncludeAll {
+			return false
+		}
+	}
+	return filter.IncludeMode == alerting.IncludeModes.IncludeAll
+}
+
+func tagMatches(required *common.TagFilter, tag *common.TagFilter) bool {
+	context := required.Context
+	if context == "" {
+		context = common.Contexts.Contextless
+	}
+	tagContext := tag.Context
+	if tagContext == "" {
+		tagContext = common.Contexts.Contextless
+	}
+	if context != tagContext || required.Key != tag.Key {
+		return false
+	}
+	return required.Value == nil || opt.String(required.Value) == opt.String(tag.Value)
+}
+
+// EventsMatch evaluates the event filters of an alerting profile.
+// Negated filters are combined with AND, the other filters with OR and both groups with AND.
+// An error is returned for custom filters with an invalid regular expression.
+func EventsMatch(filters []*alerting.EventTypeFilter, problem *Problem) (bool, error) {
+	anyPositive := false
+	positive := false
+	for _, filter := range filters {
+		var negated, matches bool
+		if filter.PredefinedEventFilter != nil {
+			negated = filter.PredefinedEventFilter.Negate
+			matches = containsEventType(problem.EventTypes, filter.PredefinedEventFilter.EventType) != negated
+		} else if filter.CustomEventFilter != nil {
+			var err error
+			if negated, matches, err = customMatches(filter.CustomEventFilter, problem); err != nil {
+				return false, err
+			}
+		} else {
+			continue
+		}
+		if negated {
+			if !matches {
+				return false, nil
+			}
+			continue
+		}
+		anyPositive = true
+		positive = positive || matches
+	}
+	return !anyPositive || positive, nil
+}
+
+func customMatches(filter *alerting.CustomEventFilter, problem *Problem) (bool, bool, error) {
+	negated := false
+	matches := true
+	for _, check := range []struct {
+		filter *alerting.CustomTextFilter
+		text   string
+	}{{filter.Title, problem.Title}, {filter.Description, problem.Description}} {
+		if check.filter == nil || !check.filter.Enabled {
+			continue
+		}
+		m, err := TextMatches(check.filter, check.text)
+		if err != nil {
+			return false, false, err
+		}
+		negated = negated || check.filter.Negate
+		matches = matches && m
+	}
+	return negated, matches, nil
+}
+
+// TextMatches evaluates a custom title or description filter against the given text
+func TextMatches(filter *alerting.CustomTextFilter, text string) (bool, error) {
+	value := filter.Value
+	if filter.CaseInsensitive && filter.Operator != alerting.Operators.ContainsRegex {
+		value = strings.ToLower(value)
+		text = strings.ToLower(text)
+	}
+	var matches bool
+	switch filter.Operator {
+	case alerting.Operators.BeginsWith:
+		matches = strings.HasPrefix(text, value)
+	case alerting.Operators.EndsWith:
+		matches = strings.HasSuffix(text, value)
+	case alerting.Operators.Contains:
+		matches = strings.Contains(text, value)
+	case alerting.Operators.Equals:
+		matches = text == value
+	case alerting.Operators.ContainsRegex:
+		if filter.CaseInsensitive {
+			value = "(?i)" + value
+		}
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression '%s': %s", filter.Value, err.Error())
+		}
+		matches = re.MatchString(text)
+	default:
+		return false, fmt.Errorf("unsupported operator '%s'", filter.Operator)
+	}
+	return matches != filter.Negate, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEventType(eventTypes []alerting.EventType, eventType alerting.EventType) bool {
+	for _, v := range eventTypes {
+		if v == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+module github.com/dtcookie/dynatrace/api/config/alerting/routing
+
+go 1.15
+
+require (
+	github.com/dtcookie/dynatrace/api/config/alerting v1.0.0
+	github.com/dtcookie/dynatrace/api/config/common v1.0.4
+	github.com/dtcookie/dynatrace/api/config/notifications v1.0.0
+	github.com/dtcookie/dynatrace/apis/problems v1.0.0
+	github.com/dtcookie/dynatrace/notification v1.0.0
+	github.com/dtcookie/dynatrace/rest v1.0.15
+	github.com/dtcookie/opt v1.0.0
+)
+
+replace (
+	github.com/dtcookie/dynatrace/api/config => ../..
+	github.com/dtcookie/dynatrace/api/config/alerting => ..
+	github.com/dtcookie/dynatrace/api/config/common => ../../common
+	github.com/dtcookie/dynatrace/api/config/notifications => ../../notifications
+	github.com/dtcookie/dynatrace/apis/cluster => ../../../../apis/cluster
+	github.com/dtcookie/dynatrace/apis/errors => ../../../../apis/errors
+	github.com/dtcookie/dynatrace/apis/problems => ../../../../apis/problems
+	github.com/dtcookie/dynatrace/log => ../../../../log
+	github.com/dtcookie/dynatrace/notification => ../../../../notification
+	github.com/dtcookie/dynatrace/rest => ../../../../rest
+)
@@ -0,0 +1,109 @@
+package routing
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+	"github.com/dtcookie/dynatrace/api/config/common"
+	"github.com/dtcookie/dynatrace/apis/problems"
+	"github.com/dtcookie/dynatrace/notification"
+	"github.com/dtcookie/opt"
+)
+
+// Problem contains the properties of a problem alerting profiles get evaluated against
+type Problem struct {
+	ID              string                 `json:"id,omitempty"`
+	Title           string                 `json:"title,omitempty"`           // matched by custom title filters
+	Description     string                 `json:"description,omitempty"`     // matched by custom description filters
+	SeverityLevel   alerting.SeverityLevel `json:"severityLevel"`             // matched by the severity rules
+	Start           time.Time              `json:"start"`                     // the delay of a severity rule is counted from here
+	End             time.Time              `json:"end,omitempty"`             // zero for problems that are still open
+	Tags            []*common.TagFilter    `json:"tags,omitempty"`            // tags of the affected entities
+	EventTypes      []alerting.EventType   `json:"eventTypes,omitempty"`      // types of the events related to the problem
+	ManagementZones []string               `json:"managementZones,omitempty"` // IDs of the management zones the problem belongs to
+}
+
+// FromProblem converts a problem fetched via the Problems API.
+// The Problems API doesn't report management zones, they need to get specified explicitly.
+func FromProblem(problem *problems.Problem, managementZones ...string) *Problem {
+	result := &Problem{
+		ID:              problem.ID,
+		Title:           problem.DisplayName,
+		SeverityLevel:   alerting.SeverityLevel(problem.SeverityLevel),
+		Start:           time.Unix(0, problem.StartTime*int64(time.Millisecond)).UTC(),
+		Tags:            []*common.TagFilter{},
+		EventTypes:      []alerting.EventType{},
+		ManagementZones: managementZones,
+	}
+	if problem.EndTime > 0 {
+		result.End = time.Unix(0, problem.EndTime*int64(time.Millisecond)).UTC()
+	}
+	for _, tag := range problem.TagsOfAffectedEntities {
+		filter := &common.TagFilter{Context: common.Context(tag.Context.String()), Key: tag.Key}
+		if tag.Value != "" {
+			filter.Value = opt.NewString(tag.Value)
+		}
+		result.Tags = append(result.Tags, filter)
+	}
+	for _, event := range problem.RankedEvents {
+		result.EventTypes = append(result.EventTypes, alerting.EventType(event.EventType.String()))
+		if result.Description == "" && event.AnnotationDescription != "" {
+			result.Description = event.AnnotationDescription
+		}
+	}
+	return result
+}
+
+// FromNotification converts the payload of a default problem notification.
+// In case the payload contains the problem details as JSON these are getting used,
+// otherwise title, severity and tags are taken from the placeholders.
+func FromNotification(payload *notification.Default, managementZones ...string) *Problem {
+	if payload.ProblemDetailsJSON != nil {
+		result := FromProblem(payload.ProblemDetailsJSON, managementZones...)
+		if result.Description == "" {
+			result.Description = payload.ProblemDetailsText
+		}
+		return result
+	}
+	result := &Problem{
+		ID:              payload.PID,
+		Title:           payload.Title,
+		Description:     payload.ProblemDetailsText,
+		SeverityLevel:   alerting.SeverityLevel(strings.ToUpper(strings.ReplaceAll(payload.Severity, " ", "_"))),
+		Start:           time.Now().UTC(),
+		Tags:            ParseTags(payload.Tags),
+		EventTypes:      []alerting.EventType{},
+		ManagementZones: managementZones,
+	}
+	if result.ID == "" {
+		result.ID = payload.ProblemID
+	}
+	return result
+}
+
+// ParseTags parses the comma separated list of tags of the `{Tags}` placeholder.
+// Tags look like `key`, `key:value` or `[context]key:value`.
+func ParseTags(s string) []*common.TagFilter {
+	tags := []*common.TagFilter{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		tag := &common.TagFilter{Context: common.Contexts.Contextless}
+		if strings.HasPrefix(part, "[") {
+			if idx := strings.Index(part, "]"); idx > 0 {
+				tag.Context = common.Context(strings.ToUpper(part[1:idx]))
+				part = part[idx+1:]
+			}
+		}
+		if idx := strings.Index(part, ":"); idx >= 0 {
+			tag.Value = opt.NewString(part[idx+1:])
+			part = part[:idx]
+		}
+		tag.Key = part
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,71 @@
+package routing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+	"github.com/dtcookie/dynatrace/api/config/alerting/routing"
+	"github.com/dtcookie/dynatrace/api/config/common"
+	"github.com/dtcookie/opt"
+)
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	problem := &routing.Problem{
+		ID:              "-123",
+		Title:           "Checkout slowdown",
+		SeverityLevel:   alerting.SeverityLevels.Performance,
+		Start:           start,
+		Tags:            routing.ParseTags("team:shop, [KUBERNETES]app:checkout"),
+		EventTypes:      []alerting.EventType{"SERVICE_SLOWDOWN"},
+		ManagementZones: []string{"mz-1"},
+	}
+	profile := &alerting.Profile{
+		ID:          opt.NewString("p-1"),
+		DisplayName: "shop",
+		Rules: []*alerting.ProfileSeverityRule{
+			{SeverityLevel: alerting.SeverityLevels.Availability},
+			{
+				SeverityLevel:  alerting.SeverityLevels.Performance,
+				DelayInMinutes: 15,
+				TagFilter: &alerting.ProfileTagFilter{
+					IncludeMode: alerting.IncludeModes.IncludeAll,
+					TagFilters: []*common.TagFilter{
+						{Context: common.Contexts.Contextless, Key: "team", Value: opt.NewString("shop")},
+						{Context: common.Contexts.Kubernetes, Key: "app"},
+					},
+				},
+			},
+		},
+		EventTypeFilters: []*alerting.EventTypeFilter{
+			{PredefinedEventFilter: &alerting.PredefinedEventFilter{EventType: "SERVICE_SLOWDOWN"}},
+			{CustomEventFilter: &alerting.CustomEventFilter{Title: &alerting.CustomTextFilter{Enabled: true, Negate: true, Operator: alerting.Operators.Contains, Value: "test", CaseInsensitive: true}}},
+		},
+	}
+
+	match := routing.Evaluate(profile, problem)
+	if !match.Matches {
+		t.Fatalf("expected profile to match: %s", match.Reason)
+	}
+	if match.RuleIndex != 1 || !match.At.Equal(start.Add(15*time.Minute)) {
+		t.Errorf("expected second rule to trigger at %s, got rule %d at %s", start.Add(15*time.Minute), match.RuleIndex, match.At)
+	}
+
+	problem.Title = "TEST checkout slowdown"
+	if match = routing.Evaluate(profile, problem); match.Matches {
+		t.Error("expected negated title filter to exclude the problem")
+	}
+	problem.Title = "Checkout slowdown"
+
+	problem.End = start.Add(10 * time.Minute)
+	if match = routing.Evaluate(profile, problem); match.Matches {
+		t.Error("expected problem closed before the delay not to trigger")
+	}
+	problem.End = time.Time{}
+
+	profile.MzID = opt.NewString("mz-2")
+	if match = routing.Evaluate(profile, problem); match.Matches {
+		t.Error("expected profile of another management zone not to match")
+	}
+}
@@ -0,0 +1,159 @@
+package routing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+	"github.com/dtcookie/dynatrace/api/config/notifications"
+	"github.com/dtcookie/opt"
+)
+
+// Match is the outcome of evaluating a single alerting profile
+type Match struct {
+	Profile   *alerting.Profile
+	Matches   bool
+	Rule      *alerting.ProfileSeverityRule // the first severity rule that matched
+	RuleIndex int
+	At        time.Time // when the profile triggers, problem start plus the delay of the rule
+	Reason    string    // why the profile doesn't trigger
+}
+
+// Channel is a notification configuration attached to a matching alerting profile
+type Channel struct {
+	Notification notifications.NotificationConfig
+	Profile      *alerting.Profile
+	At           time.Time
+	Fires        bool
+	Reason       string // why the notification doesn't fire
+}
+
+// channelConfig is implemented by the notification configurations based on notifications.BaseNotificationConfig
+type channelConfig interface {
+	GetAlertingProfile() string
+	IsActive() bool
+}
+
+// Result is the outcome of a simulation
+type Result struct {
+	Problem  *Problem
+	Profiles []*Match
+	Channels []*Channel
+}
+
+// Fired returns the channels that fire, ordered by the time they do
+func (me *Result) Fired() []*Channel {
+	fired := []*Channel{}
+	for _, channel := range me.Channels {
+		if channel.Fires {
+			fired = append(fired, channel)
+		}
+	}
+	sort.SliceStable(fired, func(i, j int) bool { return fired[i].At.Before(fired[j].At) })
+	return fired
+}
+
+func (me *Result) String() string {
+	lines := []string{}
+	lines = append(lines, fmt.Sprintf("problem %s '%s' (%s) started %s", me.Problem.ID, me.Problem.Title, me.Problem.SeverityLevel, me.Problem.Start.Format(time.RFC3339)))
+	lines = append(lines, "", "alerting profiles:")
+	for _, match := range me.Profiles {
+		if match.Matches {
+			lines = append(lines, fmt.Sprintf("  + %s: rule #%d (%s, delay %dm) triggers at %s", match.Profile.DisplayName, match.RuleIndex+1, match.Rule.SeverityLevel, match.Rule.DelayInMinutes, match.At.Format(time.RFC3339)))
+		} else {
+			lines = append(lines, fmt.Sprintf("  - %s: %s", match.Profile.DisplayName, match.Reason))
+		}
+	}
+	lines = append(lines, "", "notifications:")
+	if len(me.Channels) == 0 {
+		lines = append(lines, "  none attached to a matching alerting profile")
+	}
+	for _, channel := range me.Fired() {
+		lines = append(lines, fmt.Sprintf("  + %s (%s) fires at %s via '%s'", channel.Notification.GetName(), channel.Notification.GetType(), channel.At.Format(time.RFC3339), channel.Profile.DisplayName))
+	}
+	for _, channel := range me.Channels {
+		if !channel.Fires {
+			lines = append(lines, fmt.Sprintf("  - %s (%s): %s", channel.Notification.GetName(), channel.Notification.GetType(), channel.Reason))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Simulator evaluates alerting profiles and notification configurations offline
+type Simulator struct {
+	Profiles      []*alerting.Profile
+	Notifications []*notifications.NotificationRecord
+}
+
+// New creates a Simulator for the given alerting profiles and notification configurations
+func New(profiles []*alerting.Profile, notifications []*notifications.NotificationRecord) *Simulator {
+	return &Simulator{Profiles: profiles, Notifications: notifications}
+}
+
+// Fetch creates a Simulator for the alerting profiles and notification configurations currently configured
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/config/v1"
+// token is an API Token
+func Fetch(baseURL string, token string) (*Simulator, error) {
+	profileService := alerting.NewService(baseURL, token)
+	stubList, err := profileService.List()
+	if err != nil {
+		return nil, err
+	}
+	profiles := []*alerting.Profile{}
+	for _, stub := range stubList.Values {
+		profile, err := profileService.Get(stub.ID)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	notificationService := notifications.NewService(baseURL, token)
+	notificationStubs, err := notificationService.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	records := []*notifications.NotificationRecord{}
+	for _, stub := range notificationStubs.Values {
+		record, err := notificationService.Get(stub.ID)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return New(profiles, records), nil
+}
+
+// Simulate evaluates every alerting profile against the given problem
+// and determines which notifications fire and when
+func (me *Simulator) Simulate(problem *Problem) *Result {
+	result := &Result{Problem: problem, Profiles: []*Match{}, Channels: []*Channel{}}
+	matches := map[string]*Match{}
+	for _, profile := range me.Profiles {
+		match := Evaluate(profile, problem)
+		result.Profiles = append(result.Profiles, match)
+		matches[opt.String(profile.ID)] = match
+	}
+	for _, record := range me.Notifications {
+		config, ok := record.NotificationConfig.(channelConfig)
+		if !ok {
+			continue
+		}
+		match, found := matches[config.GetAlertingProfile()]
+		if !found || !match.Matches {
+			continue
+		}
+		channel := &Channel{Notification: record.NotificationConfig, Profile: match.Profile, At: match.At, Fires: config.IsActive()}
+		if !channel.Fires {
+			channel.Reason = "notification is disabled"
+		}
+		result.Channels = append(result.Channels, channel)
+	}
+	return result
+}
+
+func delay(rule *alerting.ProfileSeverityRule) time.Duration {
+	return time.Duration(rule.DelayInMinutes) * time.Minute
+}
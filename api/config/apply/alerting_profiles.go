@@ -0,0 +1,80 @@
+package apply
+
+import (
+	"encoding/json"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+)
+
+// AlertingProfiles is the Kind for alerting profiles
+type AlertingProfiles struct {
+	service *alerting.Service
+}
+
+// NewAlertingProfiles creates a Kind for alerting profiles
+func NewAlertingProfiles(baseURL string, token string) *AlertingProfiles {
+	return &AlertingProfiles{service: alerting.NewService(baseURL, token)}
+}
+
+// Name returns "alertingprofiles"
+func (me *AlertingProfiles) Name() string {
+	return "alertingprofiles"
+}
+
+// DependsOn returns "managementzones", alerting profiles may be restricted to a management zone
+func (me *AlertingProfiles) DependsOn() []string {
+	return []string{"managementzones"}
+}
+
+// Decode unmarshals an alerting profile
+func (me *AlertingProfiles) Decode(data []byte) (interface{}, error) {
+	var profile alerting.Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, err
+	}
+	profile.ID = nil
+	return &profile, nil
+}
+
+// NameOf returns the display name of the alerting profile
+func (me *AlertingProfiles) NameOf(v interface{}) string {
+	return v.(*alerting.Profile).DisplayName
+}
+
+// List returns all alerting profiles
+func (me *AlertingProfiles) List() ([]*api.EntityShortRepresentation, error) {
+	stubList, err := me.service.List()
+	if err != nil {
+		return nil, err
+	}
+	return stubList.Values, nil
+}
+
+// Get fetches the alerting profile with the given ID
+func (me *AlertingProfiles) Get(id string) (interface{}, error) {
+	return me.service.Get(id)
+}
+
+// Create creates the given alerting profile
+func (me *AlertingProfiles) Create(v interface{}) (string, error) {
+	profile := v.(*alerting.Profile)
+	profile.ID = nil
+	stub, err := me.service.Create(profile)
+	if err != nil {
+		return "", err
+	}
+	return stub.ID, nil
+}
+
+// Update replaces the alerting profile with the given ID
+func (me *AlertingProfiles) Update(id string, v interface{}) error {
+	profile := v.(*alerting.Profile)
+	profile.ID = &id
+	return me.service.Update(profile)
+}
+
+// Delete deletes the alerting profile with the given ID
+func (me *AlertingProfiles) Delete(id string) error {
+	return me.service.Delete(id)
+}
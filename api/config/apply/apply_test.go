@@ -0,0 +1,167 @@
+package apply_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/apply"
+)
+
+// fakeKind keeps its configurations in memory and records the calls modifying them
+type fakeKind struct {
+	name      string
+	dependsOn []string
+	configs   map[string]map[string]interface{}
+	nextID    int
+	calls     *[]string
+}
+
+func newFakeKind(name string, calls *[]string, dependsOn ...string) *fakeKind {
+	return &fakeKind{name: name, dependsOn: dependsOn, configs: map[string]map[string]interface{}{}, calls: calls}
+}
+
+func (me *fakeKind) add(name string, value string) {
+	me.nextID++
+	me.configs[fmt.Sprintf("%s-%d", me.name, me.nextID)] = map[string]interface{}{"name": name, "value": value}
+}
+
+func (me *fakeKind) Name() string        { return me.name }
+func (me *fakeKind) DependsOn() []string { return me.dependsOn }
+
+func (me *fakeKind) Decode(data []byte) (interface{}, error) {
+	var v map[string]interface{}
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+func (me *fakeKind) NameOf(v interface{}) string {
+	return v.(map[string]interface{})["name"].(string)
+}
+
+func (me *fakeKind) List() ([]*api.EntityShortRepresentation, error) {
+	stubs := []*api.EntityShortRepresentation{}
+	for id, config := range me.configs {
+		stubs = append(stubs, &api.EntityShortRepresentation{ID: id, Name: config["name"].(string)})
+	}
+	return stubs, nil
+}
+
+func (me *fakeKind) Get(id string) (interface{}, error) {
+	return me.configs[id], nil
+}
+
+func (me *fakeKind) Create(v interface{}) (string, error) {
+	me.nextID++
+	id := fmt.Sprintf("%s-%d", me.name, me.nextID)
+	me.configs[id] = v.(map[string]interface{})
+	*me.calls = append(*me.calls, "create "+me.name+" "+me.NameOf(v))
+	return id, nil
+}
+
+func (me *fakeKind) Update(id string, v interface{}) error {
+	me.configs[id] = v.(map[string]interface{})
+	*me.calls = append(*me.calls, "update "+me.name+" "+me.NameOf(v))
+	return nil
+}
+
+func (me *fakeKind) Delete(id string) error {
+	*me.calls = append(*me.calls, "delete "+me.name+" "+me.configs[id]["name"].(string))
+	delete(me.configs, id)
+	return nil
+}
+
+func names(kinds []apply.Kind) string {
+	parts := []string{}
+	for _, kind := range kinds {
+		parts = append(parts, kind.Name())
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestOrder(t *testing.T) {
+	calls := []string{}
+	dashboards := newFakeKind("dashboards", &calls, "managementzones", "alertingprofiles")
+	notifications := newFakeKind("notifications", &calls, "alertingprofiles", "unknown")
+	profiles := newFakeKind("alertingprofiles", &calls, "managementzones")
+	zones := newFakeKind("managementzones", &calls)
+
+	sorted, err := apply.Order([]apply.Kind{dashboards, notifications, profiles, zones})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "managementzones,alertingprofiles,dashboards,notifications"; names(sorted) != expected {
+		t.Errorf("expected order %s, got %s", expected, names(sorted))
+	}
+
+	zones.dependsOn = []string{"dashboards"}
+	if _, err := apply.Order([]apply.Kind{dashboards, notifications, profiles, zones}); err == nil || !strings.Contains(err.Error(), "cyclic dependency") {
+		t.Errorf("expected a cyclic dependency error, got %v", err)
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	calls := []string{}
+	zones := newFakeKind("managementzones", &calls)
+	profiles := newFakeKind("alertingprofiles", &calls, "managementzones")
+	unmanaged := newFakeKind("dashboards", &calls)
+	zones.add("production", "a")
+	zones.add("staging", "b")
+	zones.add("obsolete", "c")
+	profiles.add("legacy", "d")
+	unmanaged.add("overview", "e")
+
+	desired := &apply.Desired{Configs: map[string][]*apply.Config{
+		"managementzones": {
+			{Name: "production", Value: map[string]interface{}{"name": "production", "value": "a"}},
+			{Name: "staging", Value: map[string]interface{}{"name": "staging", "value": "changed"}},
+			{Name: "development", Value: map[string]interface{}{"name": "development", "value": "f"}},
+		},
+		"alertingprofiles": {},
+	}}
+	reconciler := apply.New(profiles, zones, unmanaged)
+	reconciler.Prune = true
+	plan, err := reconciler.Plan(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := []string{}
+	for _, change := range plan.Changes {
+		actions = append(actions, string(change.Action)+" "+change.Kind.Name()+" "+change.Name)
+	}
+	expected := strings.Join([]string{
+		"NOOP managementzones production",
+		"UPDATE managementzones staging",
+		"CREATE managementzones development",
+		"DELETE alertingprofiles legacy",
+		"DELETE managementzones obsolete",
+	}, ",")
+	if strings.Join(actions, ",") != expected {
+		t.Errorf("expected plan %s, got %s", expected, strings.Join(actions, ","))
+	}
+	if plan.Empty() || plan.Count(apply.Actions.Delete) != 2 {
+		t.Errorf("unexpected plan summary\n%s", plan.String())
+	}
+
+	report := reconciler.Apply(plan)
+	if len(report.Failed()) != 0 {
+		t.Fatalf("unexpected failures\n%s", report.String())
+	}
+	expected = "update managementzones staging,create managementzones development,delete alertingprofiles legacy,delete managementzones obsolete"
+	if strings.Join(calls, ",") != expected {
+		t.Errorf("expected calls %s, got %s", expected, strings.Join(calls, ","))
+	}
+	if len(unmanaged.configs) != 1 {
+		t.Error("expected the unmanaged kind to remain untouched")
+	}
+
+	plan, err = reconciler.Plan(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("expected no further changes\n%s", plan.String())
+	}
+}
@@ -0,0 +1,80 @@
+package apply
+
+import (
+	"encoding/json"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+)
+
+// AutoTags is the Kind for automatically applied tags
+type AutoTags struct {
+	service *autotags.ServiceClient
+}
+
+// NewAutoTags creates a Kind for automatically applied tags
+func NewAutoTags(baseURL string, token string) *AutoTags {
+	return &AutoTags{service: autotags.NewService(baseURL, token)}
+}
+
+// Name returns "autotags"
+func (me *AutoTags) Name() string {
+	return "autotags"
+}
+
+// DependsOn returns no dependencies, auto tags don't refer to other configurations
+func (me *AutoTags) DependsOn() []string {
+	return []string{}
+}
+
+// Decode unmarshals an auto tag
+func (me *AutoTags) Decode(data []byte) (interface{}, error) {
+	var autoTag autotags.AutoTag
+	if err := json.Unmarshal(data, &autoTag); err != nil {
+		return nil, err
+	}
+	autoTag.ID = nil
+	return &autoTag, nil
+}
+
+// NameOf returns the name of the auto tag
+func (me *AutoTags) NameOf(v interface{}) string {
+	return v.(*autotags.AutoTag).Name
+}
+
+// List returns all auto tags
+func (me *AutoTags) List() ([]*api.EntityShortRepresentation, error) {
+	stubList, err := me.service.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	return stubList.Values, nil
+}
+
+// Get fetches the auto tag with the given ID
+func (me *AutoTags) Get(id string) (interface{}, error) {
+	return me.service.Get(id)
+}
+
+// Create creates the given auto tag
+func (me *AutoTags) Create(v interface{}) (string, error) {
+	autoTag := v.(*autotags.AutoTag)
+	autoTag.ID = nil
+	stub, err := me.service.Create(autoTag)
+	if err != nil {
+		return "", err
+	}
+	return stub.ID, nil
+}
+
+// Update replaces the auto tag with the given ID
+func (me *AutoTags) Update(id string, v interface{}) error {
+	autoTag := v.(*autotags.AutoTag)
+	autoTag.ID = &id
+	return me.service.Update(autoTag)
+}
+
+// Delete deletes the auto tag with the given ID
+func (me *AutoTags) Delete(id string) error {
+	return me.service.Delete(id)
+}
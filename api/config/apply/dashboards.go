@@ -0,0 +1,89 @@
+package apply
+
+import (
+	"encoding/json"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/dashboards"
+	"github.com/dtcookie/opt"
+)
+
+// Dashboards is the Kind for dashboards
+type Dashboards struct {
+	service *dashboards.ServiceClient
+}
+
+// NewDashboards creates a Kind for dashboards
+func NewDashboards(baseURL string, token string) *Dashboards {
+	return &Dashboards{service: dashboards.NewService(baseURL, token)}
+}
+
+// Name returns "dashboards"
+func (me *Dashboards) Name() string {
+	return "dashboards"
+}
+
+// DependsOn returns "managementzones", dashboards and tiles may be filtered by management zone
+func (me *Dashboards) DependsOn() []string {
+	return []string{"managementzones"}
+}
+
+// Decode unmarshals a dashboard
+func (me *Dashboards) Decode(data []byte) (interface{}, error) {
+	var dashboard dashboards.Dashboard
+	if err := json.Unmarshal(data, &dashboard); err != nil {
+		return nil, err
+	}
+	dashboard.ID = nil
+	return &dashboard, nil
+}
+
+// NameOf returns the name of the dashboard
+func (me *Dashboards) NameOf(v interface{}) string {
+	dashboard := v.(*dashboards.Dashboard)
+	if dashboard.Metadata == nil {
+		return ""
+	}
+	return dashboard.Metadata.Name
+}
+
+// List returns all dashboards
+func (me *Dashboards) List() ([]*api.EntityShortRepresentation, error) {
+	dashboardList, err := me.service.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	stubs := []*api.EntityShortRepresentation{}
+	for _, stub := range dashboardList.Dashboards {
+		stubs = append(stubs, &api.EntityShortRepresentation{ID: stub.ID, Name: opt.String(stub.Name)})
+	}
+	return stubs, nil
+}
+
+// Get fetches the dashboard with the given ID
+func (me *Dashboards) Get(id string) (interface{}, error) {
+	return me.service.Get(id)
+}
+
+// Create creates the given dashboard
+func (me *Dashboards) Create(v interface{}) (string, error) {
+	dashboard := v.(*dashboards.Dashboard)
+	dashboard.ID = nil
+	stub, err := me.service.Create(dashboard)
+	if err != nil {
+		return "", err
+	}
+	return stub.ID, nil
+}
+
+// Update replaces the dashboard with the given ID
+func (me *Dashboards) Update(id string, v interface{}) error {
+	dashboard := v.(*dashboards.Dashboard)
+	dashboard.ID = &id
+	return me.service.Update(dashboard)
+}
+
+// Delete deletes the dashboard with the given ID
+func (me *Dashboards) Delete(id string) error {
+	return me.service.Delete(id)
+}
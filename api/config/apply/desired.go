@@ -0,0 +1,94 @@
+package apply
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Config is a single configuration read from a file
+type Config struct {
+	File  string      // the file the configuration has been read from
+	Name  string      // the name the configuration gets matched by
+	Value interface{} // the decoded configuration, e.g. a *dashboards.Dashboard
+}
+
+// Desired holds the configurations read from a directory, grouped by the name of their kind.
+// Kinds without a subdirectory are not contained and therefore remain unmanaged.
+type Desired struct {
+	Configs map[string][]*Config
+}
+
+// Managed tells whether the directory contained a subdirectory for the given kind
+func (me *Desired) Managed(kind string) bool {
+	_, found := me.Configs[kind]
+	return found
+}
+
+// Load reads the configurations of the given kinds from the subdirectories of dir.
+// Every kind is expected in a subdirectory named after it, holding one JSON file per configuration, e.g.
+//
+//	<dir>/managementzones/production.json
+//	<dir>/dashboards/overview.json
+func Load(dir string, kinds []Kind) (*Desired, error) {
+	desired := &Desired{Configs: map[string][]*Config{}}
+	for _, kind := range kinds {
+		kindDir := filepath.Join(dir, kind.Name())
+		info, err := os.Stat(kindDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", kindDir)
+		}
+		configs, err := loadKind(kindDir, kind)
+		if err != nil {
+			return nil, err
+		}
+		desired.Configs[kind.Name()] = configs
+	}
+	return desired, nil
+}
+
+func loadKind(dir string, kind Kind) ([]*Config, error) {
+	var err error
+	var entries []os.FileInfo
+
+	if entries, err = ioutil.ReadDir(dir); err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	configs := []*Config{}
+	files := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".json") {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name())
+		var data []byte
+		if data, err = ioutil.ReadFile(file); err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if value, err = kind.Decode(data); err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err.Error())
+		}
+		name := kind.NameOf(value)
+		if len(name) == 0 {
+			return nil, fmt.Errorf("%s: the configuration doesn't contain a name", file)
+		}
+		if other, found := files[name]; found {
+			return nil, fmt.Errorf("%s: the %s '%s' is already defined in %s", file, kind.Name(), name, other)
+		}
+		files[name] = file
+		configs = append(configs, &Config{File: file, Name: name, Value: value})
+	}
+	return configs, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dtcookie/dynatrace/api/config/apply"
+)
+
+func main() {
+	var apiBaseURL string
+	var apiToken string
+	var prune bool
+	var dryRun bool
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&apiBaseURL, "api-base-url", os.Getenv("DT_API_BASE_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.BoolVar(&prune, "prune", false, "")
+	flagSet.BoolVar(&dryRun, "dry-run", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if apiBaseURL == "" || apiToken == "" || flagSet.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	reconciler := apply.New(apply.Kinds(apiBaseURL, apiToken)...)
+	reconciler.Prune = prune
+
+	desired, err := reconciler.Load(flagSet.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	plan, err := reconciler.Plan(desired)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(plan.String())
+	if dryRun || plan.Empty() {
+		return
+	}
+
+	report := reconciler.Apply(plan)
+	fmt.Println()
+	fmt.Println(report.String())
+	if len(report.Failed()) > 0 {
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtapply [-api-base-url <api-base-url>] [-api-token <api-token>] [-prune] [-dry-run] <config-dir>")
+	fmt.Println("  <config-dir> contains one subdirectory per kind (managementzones, alertingprofiles, notifications, autotags, maintenancewindows, dashboards)")
+	fmt.Println("  holding one JSON file per configuration. Configurations are matched by name.")
+	fmt.Println("  -prune    deletes configurations of the kinds present in <config-dir> which don't have a file")
+	fmt.Println("  -dry-run  only prints the plan")
+	fmt.Println("  Hint: you can also define the environment variables DT_API_BASE_URL and DT_API_TOKEN")
+}
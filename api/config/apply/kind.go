@@ -0,0 +1,42 @@
+package apply
+
+import (
+	api "github.com/dtcookie/dynatrace/api/config"
+)
+
+// Kind provides uniform access to one type of configuration, backed by the according ServiceClient
+type Kind interface {
+	// Name is the name of the directory the configuration files of this kind are expected in
+	Name() string
+	// DependsOn lists the names of the kinds configurations of this kind may refer to.
+	// These kinds get created and updated before this one and deleted after it.
+	DependsOn() []string
+	// Decode unmarshals the contents of a configuration file
+	Decode(data []byte) (interface{}, error)
+	// NameOf returns the name a configuration gets matched by
+	NameOf(v interface{}) string
+	// List returns the short representations of all configurations of this kind currently present
+	List() ([]*api.EntityShortRepresentation, error)
+	// Get fetches the full configuration with the given ID
+	Get(id string) (interface{}, error)
+	// Create creates the given configuration and returns the ID it got assigned
+	Create(v interface{}) (string, error)
+	// Update replaces the configuration with the given ID
+	Update(id string, v interface{}) error
+	// Delete deletes the configuration with the given ID
+	Delete(id string) error
+}
+
+// Kinds returns a Kind for every configuration type supported by the apply engine
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/config/v1"
+// token is an API Token
+func Kinds(baseURL string, token string) []Kind {
+	return []Kind{
+		NewManagementZones(baseURL, token),
+		NewAlertingProfiles(baseURL, token),
+		NewNotifications(baseURL, token),
+		NewAutoTags(baseURL, token),
+		NewMaintenanceWindows(baseURL, token),
+		NewDashboards(baseURL, token),
+	}
+}
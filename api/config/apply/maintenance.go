@@ -0,0 +1,80 @@
+package apply
+
+import (
+	"encoding/json"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+)
+
+// MaintenanceWindows is the Kind for maintenance windows
+type MaintenanceWindows struct {
+	service *maintenance.ServiceClient
+}
+
+// NewMaintenanceWindows creates a Kind for maintenance windows
+func NewMaintenanceWindows(baseURL string, token string) *MaintenanceWindows {
+	return &MaintenanceWindows{service: maintenance.NewService(baseURL, token)}
+}
+
+// Name returns "maintenancewindows"
+func (me *MaintenanceWindows) Name() string {
+	return "maintenancewindows"
+}
+
+// DependsOn returns "managementzones", the scope of a maintenance window may be restricted to management zones
+func (me *MaintenanceWindows) DependsOn() []string {
+	return []string{"managementzones"}
+}
+
+// Decode unmarshals a maintenance window
+func (me *MaintenanceWindows) Decode(data []byte) (interface{}, error) {
+	var window maintenance.Window
+	if err := json.Unmarshal(data, &window); err != nil {
+		return nil, err
+	}
+	window.ID = nil
+	return &window, nil
+}
+
+// NameOf returns the name of the maintenance window
+func (me *MaintenanceWindows) NameOf(v interface{}) string {
+	return v.(*maintenance.Window).Name
+}
+
+// List returns all maintenance windows
+func (me *MaintenanceWindows) List() ([]*api.EntityShortRepresentation, error) {
+	stubList, err := me.service.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	return stubList.Values, nil
+}
+
+// Get fetches the maintenance window with the given ID
+func (me *MaintenanceWindows) Get(id string) (interface{}, error) {
+	return me.service.Get(id)
+}
+
+// Create creates the given maintenance window
+func (me *MaintenanceWindows) Create(v interface{}) (string, error) {
+	window := v.(*maintenance.Window)
+	window.ID = nil
+	stub, err := me.service.Create(window)
+	if err != nil {
+		return "", err
+	}
+	return stub.ID, nil
+}
+
+// Update replaces the maintenance window with the given ID
+func (me *MaintenanceWindows) Update(id string, v interface{}) error {
+	window := v.(*maintenance.Window)
+	window.ID = &id
+	return me.service.Update(window)
+}
+
+// Delete deletes the maintenance window with the given ID
+func (me *MaintenanceWindows) Delete(id string) error {
+	return me.service.Delete(id)
+}
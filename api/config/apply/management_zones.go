@@ -0,0 +1,76 @@
+package apply
+
+import (
+	"encoding/json"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+)
+
+// ManagementZones is the Kind for management zones
+type ManagementZones struct {
+	service *managementzones.ServiceClient
+}
+
+// NewManagementZones creates a Kind for management zones
+func NewManagementZones(baseURL string, token string) *ManagementZones {
+	return &ManagementZones{service: managementzones.NewService(baseURL, token)}
+}
+
+// Name returns "managementzones"
+func (me *ManagementZones) Name() string {
+	return "managementzones"
+}
+
+// DependsOn returns no dependencies, management zones don't refer to other configurations
+func (me *ManagementZones) DependsOn() []string {
+	return []string{}
+}
+
+// Decode unmarshals a management zone
+func (me *ManagementZones) Decode(data []byte) (interface{}, error) {
+	var managementZone managementzones.ManagementZone
+	if err := json.Unmarshal(data, &managementZone); err != nil {
+		return nil, err
+	}
+	managementZone.ID = nil
+	return &managementZone, nil
+}
+
+// NameOf returns the name of the management zone
+func (me *ManagementZones) NameOf(v interface{}) string {
+	return v.(*managementzones.ManagementZone).Name
+}
+
+// List returns all management zones
+func (me *ManagementZones) List() ([]*api.EntityShortRepresentation, error) {
+	return me.service.ListAll()
+}
+
+// Get fetches the management zone with the given ID
+func (me *ManagementZones) Get(id string) (interface{}, error) {
+	return me.service.Get(id, false)
+}
+
+// Create creates the given management zone
+func (me *ManagementZones) Create(v interface{}) (string, error) {
+	managementZone := v.(*managementzones.ManagementZone)
+	managementZone.ID = nil
+	stub, err := me.service.Create(managementZone)
+	if err != nil {
+		return "", err
+	}
+	return stub.ID, nil
+}
+
+// Update replaces the management zone with the given ID
+func (me *ManagementZones) Update(id string, v interface{}) error {
+	managementZone := v.(*managementzones.ManagementZone)
+	managementZone.ID = &id
+	return me.service.Update(managementZone)
+}
+
+// Delete deletes the management zone with the given ID
+func (me *ManagementZones) Delete(id string) error {
+	return me.service.Delete(id)
+}
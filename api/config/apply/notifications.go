@@ -0,0 +1,85 @@
+package apply
+
+import (
+	"encoding/json"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/notifications"
+	"github.com/dtcookie/opt"
+)
+
+// Notifications is the Kind for problem notification integrations
+type Notifications struct {
+	service *notifications.ServiceClient
+}
+
+// NewNotifications creates a Kind for problem notification integrations
+func NewNotifications(baseURL string, token string) *Notifications {
+	return &Notifications{service: notifications.NewService(baseURL, token)}
+}
+
+// Name returns "notifications"
+func (me *Notifications) Name() string {
+	return "notifications"
+}
+
+// DependsOn returns "alertingprofiles", every notification refers to an alerting profile
+func (me *Notifications) DependsOn() []string {
+	return []string{"alertingprofiles"}
+}
+
+// Decode unmarshals a notification configuration of any supported type
+func (me *Notifications) Decode(data []byte) (interface{}, error) {
+	var record notifications.NotificationRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	record.NotificationConfig.SetID(nil)
+	return &record, nil
+}
+
+// NameOf returns the name of the notification configuration
+func (me *Notifications) NameOf(v interface{}) string {
+	return v.(*notifications.NotificationRecord).NotificationConfig.GetName()
+}
+
+// List returns all notification configurations
+func (me *Notifications) List() ([]*api.EntityShortRepresentation, error) {
+	stubList, err := me.service.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	stubs := []*api.EntityShortRepresentation{}
+	for _, stub := range stubList.Values {
+		stubs = append(stubs, &api.EntityShortRepresentation{ID: stub.ID, Name: opt.String(stub.Name), Description: opt.String(stub.Description)})
+	}
+	return stubs, nil
+}
+
+// Get fetches the notification configuration with the given ID
+func (me *Notifications) Get(id string) (interface{}, error) {
+	return me.service.Get(id)
+}
+
+// Create creates the given notification configuration
+func (me *Notifications) Create(v interface{}) (string, error) {
+	record := v.(*notifications.NotificationRecord)
+	record.NotificationConfig.SetID(nil)
+	stub, err := me.service.Create(record)
+	if err != nil {
+		return "", err
+	}
+	return stub.ID, nil
+}
+
+// Update replaces the notification configuration with the given ID
+func (me *Notifications) Update(id string, v interface{}) error {
+	record := v.(*notifications.NotificationRecord)
+	record.NotificationConfig.SetID(&id)
+	return me.service.Update(record)
+}
+
+// Delete deletes the notification configuration with the given ID
+func (me *Notifications) Delete(id string) error {
+	return me.service.Delete(id)
+}
@@ -0,0 +1,46 @@
+package apply
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Order sorts the given kinds so that every kind comes after the kinds it depends on.
+// Dependencies on kinds that are not part of the given list are ignored.
+func Order(kinds []Kind) ([]Kind, error) {
+	byName := map[string]Kind{}
+	for _, kind := range kinds {
+		byName[kind.Name()] = kind
+	}
+	sorted := []Kind{}
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+	var visit func(kind Kind, path []string) error
+	visit = func(kind Kind, path []string) error {
+		name := kind.Name()
+		if visited[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("cyclic dependency between kinds: %s", strings.Join(append(path, name), " -> "))
+		}
+		visiting[name] = true
+		for _, dependency := range kind.DependsOn() {
+			if dep, found := byName[dependency]; found {
+				if err := visit(dep, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		sorted = append(sorted, kind)
+		return nil
+	}
+	for _, kind := range kinds {
+		if err := visit(kind, []string{}); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
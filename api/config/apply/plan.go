@@ -0,0 +1,90 @@
+package apply
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/diff"
+)
+
+// Action defines what needs to happen in order to reach the desired state of a configuration
+type Action string
+
+// Actions offers the known enum values
+var Actions = struct {
+	Create Action
+	Update Action
+	Delete Action
+	NoOp   Action
+}{
+	"CREATE",
+	"UPDATE",
+	"DELETE",
+	"NOOP",
+}
+
+var symbols = map[Action]string{
+	Actions.Create: "+",
+	Actions.Update: "~",
+	Actions.Delete: "-",
+	Actions.NoOp:   " ",
+}
+
+// Change is the planned action for a single configuration
+type Change struct {
+	Kind    Kind
+	Name    string
+	ID      string // the ID of the existing configuration; empty for Create
+	File    string // the file the desired state has been read from; empty for Delete
+	Action  Action
+	Desired interface{}  // the desired configuration; nil for Delete
+	Current interface{}  // the existing configuration for Update and NoOp
+	Diff    diff.Changes // the differences between the current and the desired configuration for Update
+}
+
+func (me *Change) String() string {
+	if len(me.ID) == 0 {
+		return fmt.Sprintf("%s %s '%s'", symbols[me.Action], me.Kind.Name(), me.Name)
+	}
+	return fmt.Sprintf("%s %s '%s' (%s)", symbols[me.Action], me.Kind.Name(), me.Name, me.ID)
+}
+
+// Plan is the ordered list of changes required to reach the desired state.
+// Creations and updates are ordered so that dependencies come first, deletions so that dependents come first.
+type Plan struct {
+	Changes []*Change
+}
+
+// Count returns the number of changes with the given action
+func (me *Plan) Count(action Action) int {
+	count := 0
+	for _, change := range me.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Empty tells whether the plan contains no changes except for NoOps
+func (me *Plan) Empty() bool {
+	return me.Count(Actions.NoOp) == len(me.Changes)
+}
+
+// String renders the plan in a human readable form. NoOps are only counted.
+func (me *Plan) String() string {
+	lines := []string{}
+	for _, change := range me.Changes {
+		if change.Action != Actions.NoOp {
+			lines = append(lines, "  "+change.String())
+		}
+		if change.Action == Actions.Update {
+			for _, difference := range change.Diff {
+				lines = append(lines, "      "+difference.String())
+			}
+		}
+	}
+	lines = append(lines, fmt.Sprintf("Plan: %d to create, %d to update, %d to delete, %d unchanged.",
+		me.Count(Actions.Create), me.Count(Actions.Update), me.Count(Actions.Delete), me.Count(Actions.NoOp)))
+	return strings.Join(lines, "\n")
+}
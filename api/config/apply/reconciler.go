@@ -0,0 +1,122 @@
+package apply
+
+import (
+	"fmt"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/diff"
+)
+
+// Reconciler computes and applies the changes required to bring the configuration of an environment into the desired state
+type Reconciler struct {
+	kinds []Kind
+	// Prune enables deleting configurations which are not part of the desired state.
+	// Only kinds the desired state contains a directory for are getting pruned.
+	Prune bool
+}
+
+// New creates a new Reconciler for the given kinds
+func New(kinds ...Kind) *Reconciler {
+	return &Reconciler{kinds: kinds}
+}
+
+// Load reads the desired state from the given directory
+func (me *Reconciler) Load(dir string) (*Desired, error) {
+	return Load(dir, me.kinds)
+}
+
+// Plan matches the desired configurations by name against the existing ones and determines the necessary changes
+func (me *Reconciler) Plan(desired *Desired) (*Plan, error) {
+	var err error
+	var kinds []Kind
+
+	if kinds, err = Order(me.kinds); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Changes: []*Change{}}
+	deletions := [][]*Change{}
+	for _, kind := range kinds {
+		if !desired.Managed(kind.Name()) {
+			continue
+		}
+		var changes, deleted []*Change
+		if changes, deleted, err = me.plan(kind, desired.Configs[kind.Name()]); err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, changes...)
+		deletions = append(deletions, deleted)
+	}
+	for i := len(deletions) - 1; i >= 0; i-- {
+		plan.Changes = append(plan.Changes, deletions[i]...)
+	}
+	return plan, nil
+}
+
+func (me *Reconciler) plan(kind Kind, configs []*Config) ([]*Change, []*Change, error) {
+	var err error
+	var stubs []*api.EntityShortRepresentation
+
+	if stubs, err = kind.List(); err != nil {
+		return nil, nil, fmt.Errorf("listing %s failed: %s", kind.Name(), err.Error())
+	}
+	existing := map[string][]string{}
+	for _, stub := range stubs {
+		existing[stub.Name] = append(existing[stub.Name], stub.ID)
+	}
+
+	changes := []*Change{}
+	for _, config := range configs {
+		ids := existing[config.Name]
+		delete(existing, config.Name)
+		switch len(ids) {
+		case 0:
+			changes = append(changes, &Change{Kind: kind, Name: config.Name, File: config.File, Action: Actions.Create, Desired: config.Value})
+		case 1:
+			var current interface{}
+			if current, err = kind.Get(ids[0]); err != nil {
+				return nil, nil, fmt.Errorf("fetching %s '%s' (%s) failed: %s", kind.Name(), config.Name, ids[0], err.Error())
+			}
+			var differences diff.Changes
+			if differences, err = diff.Compare(current, config.Value); err != nil {
+				return nil, nil, err
+			}
+			action := Actions.Update
+			if differences.Empty() {
+				action = Actions.NoOp
+			}
+			changes = append(changes, &Change{Kind: kind, Name: config.Name, ID: ids[0], File: config.File, Action: action, Desired: config.Value, Current: current, Diff: differences})
+		default:
+			return nil, nil, fmt.Errorf("%s: the name '%s' is ambiguous, %d %s with that name exist: %v", config.File, config.Name, len(ids), kind.Name(), ids)
+		}
+	}
+
+	deletions := []*Change{}
+	if me.Prune {
+		for _, stub := range stubs {
+			if _, found := existing[stub.Name]; found {
+				deletions = append(deletions, &Change{Kind: kind, Name: stub.Name, ID: stub.ID, Action: Actions.Delete})
+			}
+		}
+	}
+	return changes, deletions, nil
+}
+
+// Apply executes the changes of the given plan in order.
+// A failing change doesn't stop the remaining ones from getting applied, the outcome of each change is contained in the report.
+func (me *Reconciler) Apply(plan *Plan) *Report {
+	report := &Report{Results: []*Result{}}
+	for _, change := range plan.Changes {
+		result := &Result{Change: change, ID: change.ID}
+		switch change.Action {
+		case Actions.Create:
+			result.ID, result.Error = change.Kind.Create(change.Desired)
+		case Actions.Update:
+			result.Error = change.Kind.Update(change.ID, change.Desired)
+		case Actions.Delete:
+			result.Error = change.Kind.Delete(change.ID)
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
@@ -0,0 +1,48 @@
+package apply
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Result is the outcome of applying a single change
+type Result struct {
+	Change *Change
+	ID     string // the ID of the affected configuration, for Create the ID it got assigned
+	Error  error
+}
+
+func (me *Result) String() string {
+	if me.Error != nil {
+		return fmt.Sprintf("%s %s '%s' failed: %s", strings.ToLower(string(me.Change.Action)), me.Change.Kind.Name(), me.Change.Name, me.Error.Error())
+	}
+	return fmt.Sprintf("%s %s '%s' (%s)", strings.ToLower(string(me.Change.Action)), me.Change.Kind.Name(), me.Change.Name, me.ID)
+}
+
+// Report contains the results of all changes of an applied plan
+type Report struct {
+	Results []*Result
+}
+
+// Failed returns the results of the changes that couldn't get applied
+func (me *Report) Failed() []*Result {
+	failed := []*Result{}
+	for _, result := range me.Results {
+		if result.Error != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// String renders one line per applied change, skipping NoOps
+func (me *Report) String() string {
+	lines := []string{}
+	for _, result := range me.Results {
+		if result.Change.Action != Actions.NoOp {
+			lines = append(lines, result.String())
+		}
+	}
+	lines = append(lines, fmt.Sprintf("Apply complete: %d succeeded, %d failed.", len(me.Results)-len(me.Failed()), len(me.Failed())))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,91 @@
+package backup
+
+import (
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/anomalies/applications"
+	"github.com/dtcookie/dynatrace/api/config/anomalies/databaseservices"
+	"github.com/dtcookie/dynatrace/api/config/anomalies/diskevents"
+	"github.com/dtcookie/dynatrace/api/config/anomalies/hosts"
+	"github.com/dtcookie/dynatrace/api/config/anomalies/metricevents"
+	"github.com/dtcookie/dynatrace/api/config/anomalies/services"
+)
+
+func applicationAnomalies(baseURL string, token string) *kind {
+	service := applications.NewService(baseURL, token)
+	return singleton("applicationanomalies",
+		func() interface{} { return new(applications.AnomalyDetection) },
+		func() (interface{}, error) { return service.Get() },
+		func(v interface{}) error { return service.Update(v.(*applications.AnomalyDetection)) },
+	)
+}
+
+func databaseAnomalies(baseURL string, token string) *kind {
+	service := databaseservices.NewService(baseURL, token)
+	return singleton("databaseanomalies",
+		func() interface{} { return new(databaseservices.AnomalyDetection) },
+		func() (interface{}, error) { return service.Get() },
+		func(v interface{}) error { return service.Update(v.(*databaseservices.AnomalyDetection)) },
+	)
+}
+
+func hostAnomalies(baseURL string, token string) *kind {
+	service := hosts.NewService(baseURL, token)
+	return singleton("hostanomalies",
+		func() interface{} { return new(hosts.AnomalyDetection) },
+		func() (interface{}, error) { return service.Get() },
+		func(v interface{}) error { return service.Update(v.(*hosts.AnomalyDetection)) },
+	)
+}
+
+func serviceAnomalies(baseURL string, token string) *kind {
+	service := services.NewService(baseURL, token)
+	return singleton("serviceanomalies",
+		func() interface{} { return new(services.AnomalyDetection) },
+		func() (interface{}, error) { return service.Get() },
+		func(v interface{}) error { return service.Update(v.(*services.AnomalyDetection)) },
+	)
+}
+
+func diskEvents(baseURL string, token string) *kind {
+	service := diskevents.NewService(baseURL, token)
+	return &kind{
+		name:   "diskevents",
+		new:    func() interface{} { return new(diskevents.AnomalyDetection) },
+		nameOf: func(v interface{}) string { return v.(*diskevents.AnomalyDetection).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return refs(service.List()) },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*diskevents.AnomalyDetection)
+			config.ID = nil
+			return ref(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*diskevents.AnomalyDetection)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func metricEvents(baseURL string, token string) *kind {
+	service := metricevents.NewService(baseURL, token)
+	return &kind{
+		name:   "metricevents",
+		new:    func() interface{} { return new(metricevents.MetricEvent) },
+		nameOf: func(v interface{}) string { return v.(*metricevents.MetricEvent).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return refs(service.List()) },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*metricevents.MetricEvent)
+			config.ID = nil
+			return ref(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*metricevents.MetricEvent)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
@@ -0,0 +1,51 @@
+package backup
+
+import (
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/applications/mobile"
+	"github.com/dtcookie/dynatrace/api/config/applications/web"
+)
+
+func webApplications(baseURL string, token string) *kind {
+	service := web.NewService(baseURL, token)
+	return &kind{
+		name:   "webapplications",
+		new:    func() interface{} { return new(web.ApplicationConfig) },
+		nameOf: func(v interface{}) string { return v.(*web.ApplicationConfig).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return stubs(service.List()) },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*web.ApplicationConfig)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*web.ApplicationConfig)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func mobileApplications(baseURL string, token string) *kind {
+	service := mobile.NewService(baseURL, token)
+	return &kind{
+		name:   "mobileapplications",
+		new:    func() interface{} { return new(mobile.NewAppConfig) },
+		nameOf: func(v interface{}) string { return v.(*mobile.NewAppConfig).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return stubs(service.List()) },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*mobile.NewAppConfig)
+			config.ID = ""
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*mobile.NewAppConfig)
+			config.ID = id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
@@ -0,0 +1,210 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// FormatVersion is the version of the archive layout written by this package.
+// Archives with a higher version are rejected when reading them.
+const FormatVersion = 1
+
+// ManifestFile is the name of the file within an archive describing its contents
+const ManifestFile = "manifest.json"
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	FormatVersion int        `json:"formatVersion"`
+	Created       time.Time  `json:"created"`
+	Source        string     `json:"source,omitempty"` // the URL of the environment or cluster the backup has been taken from
+	Kinds         []*Summary `json:"kinds"`
+	Objects       []*Object  `json:"objects"`
+}
+
+// Summary records the outcome of backing up the configurations of a single kind
+type Summary struct {
+	Kind   string   `json:"kind"`
+	Count  int      `json:"count"`            // the number of configurations contained in the archive
+	Errors []string `json:"errors,omitempty"` // listing or fetching configurations failed
+}
+
+// Object is a single configuration within an archive
+type Object struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"` // the ID within the environment the backup has been taken from
+	Name   string `json:"name"`
+	File   string `json:"file"`   // the location of the canonical JSON representation within the archive
+	SHA256 string `json:"sha256"` // the hex encoded checksum of the file
+}
+
+// Archive is a backup of the configurations of an environment
+type Archive struct {
+	Manifest *Manifest
+	files    map[string][]byte
+}
+
+// NewArchive creates an empty archive
+func NewArchive(source string) *Archive {
+	return &Archive{
+		Manifest: &Manifest{FormatVersion: FormatVersion, Created: time.Now().UTC(), Source: source, Kinds: []*Summary{}, Objects: []*Object{}},
+		files:    map[string][]byte{},
+	}
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// Add stores the canonical JSON representation of the given configuration
+func (me *Archive) Add(kind string, id string, name string, v interface{}) (*Object, error) {
+	data, err := canonical(v)
+	if err != nil {
+		return nil, err
+	}
+	base := kind + "/" + unsafeChars.ReplaceAllString(id, "_")
+	file := base + ".json"
+	for i := 2; me.files[file] != nil; i++ {
+		file = fmt.Sprintf("%s-%d.json", base, i)
+	}
+	checksum := sha256.Sum256(data)
+	object := &Object{Kind: kind, ID: id, Name: name, File: file, SHA256: hex.EncodeToString(checksum[:])}
+	me.files[file] = data
+	me.Manifest.Objects = append(me.Manifest.Objects, object)
+	return object, nil
+}
+
+// Data returns the JSON representation of the given object
+func (me *Archive) Data(object *Object) []byte {
+	return me.files[object.File]
+}
+
+// Objects returns the objects of the given kind
+func (me *Archive) Objects(kind string) []*Object {
+	objects := []*Object{}
+	for _, object := range me.Manifest.Objects {
+		if object.Kind == kind {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// Write stores the archive as gzip compressed tar, the manifest being the first entry
+func (me *Archive) Write(w io.Writer) error {
+	var err error
+	var manifest []byte
+
+	if manifest, err = json.MarshalIndent(me.Manifest, "", "  "); err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	files := []string{}
+	for file := range me.files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	if err = writeEntry(tw, ManifestFile, manifest, me.Manifest.Created); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err = writeEntry(tw, file, me.files[file], me.Manifest.Created); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// WriteFile stores the archive in the given file
+func (me *Archive) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = me.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Read loads an archive and verifies its format version and the checksums of all contained files
+func Read(r io.Reader) (*Archive, error) {
+	var err error
+	var gz *gzip.Reader
+
+	if gz, err = gzip.NewReader(r); err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	archive := &Archive{files: map[string][]byte{}}
+	var manifest []byte
+	for {
+		var header *tar.Header
+		if header, err = tr.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		var data []byte
+		if data, err = ioutil.ReadAll(tr); err != nil {
+			return nil, err
+		}
+		if header.Name == ManifestFile {
+			manifest = data
+		} else {
+			archive.files[header.Name] = data
+		}
+	}
+	if manifest == nil {
+		return nil, errors.New("the archive doesn't contain a " + ManifestFile)
+	}
+	if err = json.Unmarshal(manifest, &archive.Manifest); err != nil {
+		return nil, fmt.Errorf("%s: %s", ManifestFile, err.Error())
+	}
+	if archive.Manifest.FormatVersion < 1 || archive.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("unsupported archive format version %d", archive.Manifest.FormatVersion)
+	}
+	for _, object := range archive.Manifest.Objects {
+		data, found := archive.files[object.File]
+		if !found {
+			return nil, fmt.Errorf("%s is missing in the archive", object.File)
+		}
+		checksum := sha256.Sum256(data)
+		if hex.EncodeToString(checksum[:]) != object.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", object.File)
+		}
+	}
+	return archive, nil
+}
+
+// ReadFile loads an archive from the given file
+func ReadFile(path string) (*Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file)
+}
@@ -0,0 +1,74 @@
+package backup_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/config/backup"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	archive := backup.NewArchive("https://siz65484.live.dynatrace.com")
+	config := map[string]interface{}{"name": "my-zone", "rules": []interface{}{}, "description": "a"}
+	if _, err := archive.Add("managementzones", "-123:456", "my-zone", config); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := archive.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := backup.Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := restored.Objects("managementzones")
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 object, got %d", len(objects))
+	}
+	if objects[0].File != "managementzones/-123_456.json" {
+		t.Errorf("unexpected file name %s", objects[0].File)
+	}
+	data := string(restored.Data(objects[0]))
+	if strings.Index(data, `"description"`) > strings.Index(data, `"name"`) {
+		t.Errorf("keys are not sorted:\n%s", data)
+	}
+}
+
+func TestArchiveChecksumMismatch(t *testing.T) {
+	archive := backup.NewArchive("")
+	if _, err := archive.Add("dashboards", "abc", "my-dashboard", map[string]string{"name": "my-dashboard"}); err != nil {
+		t.Fatal(err)
+	}
+	archive.Manifest.Objects[0].SHA256 = strings.Repeat("0", 64)
+	var buf bytes.Buffer
+	if err := archive.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backup.Read(&buf); err == nil {
+		t.Error("expected a checksum mismatch")
+	}
+}
+
+func TestRestrictRestore(t *testing.T) {
+	archive := backup.NewArchive("")
+	if _, err := archive.Add("managementzones", "mz", "my-zone", map[string]string{"name": "my-zone"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := archive.Add("awscredentials", "aws", "my-account", map[string]string{"label": "my-account"}); err != nil {
+		t.Fatal(err)
+	}
+	restore := backup.NewRestore(archive, backup.Kinds("https://siz65484.live.dynatrace.com", "token"))
+	if err := backup.Restrict(restore, []string{"managementzones", "awscredentials"}); err == nil || !strings.Contains(err.Error(), "awscredentials can't get restored") {
+		t.Errorf("expected awscredentials to be rejected, got %v", err)
+	}
+	if err := backup.Restrict(restore, []string{"dashboards"}); err == nil {
+		t.Error("expected a kind not contained in the archive to be rejected")
+	}
+	if err := backup.Restrict(restore, []string{"managementzones"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(restore.Only) != 1 || restore.Only[0] != "managementzones" {
+		t.Errorf("expected only managementzones to get restored, got %v", restore.Only)
+	}
+}
@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/apply"
+	"github.com/dtcookie/dynatrace/api/config/migrate"
+)
+
+// Backup fetches every configuration of the given kinds into a new archive.
+// Kinds which can't get listed, e.g. because the token lacks the permission or the API
+// isn't available for the environment, are recorded in the manifest instead of failing the backup.
+func Backup(source string, kinds []apply.Kind) *Archive {
+	archive := NewArchive(source)
+	for _, kind := range kinds {
+		summary := &Summary{Kind: kind.Name()}
+		archive.Manifest.Kinds = append(archive.Manifest.Kinds, summary)
+		stubs, err := kind.List()
+		if err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("listing failed: %s", err.Error()))
+			continue
+		}
+		for _, stub := range stubs {
+			config, err := kind.Get(stub.ID)
+			if err != nil {
+				summary.Errors = append(summary.Errors, fmt.Sprintf("fetching '%s' (%s) failed: %s", stub.Name, stub.ID, err.Error()))
+				continue
+			}
+			if _, err = archive.Add(kind.Name(), stub.ID, kind.NameOf(config), config); err != nil {
+				summary.Errors = append(summary.Errors, fmt.Sprintf("storing '%s' (%s) failed: %s", stub.Name, stub.ID, err.Error()))
+				continue
+			}
+			summary.Count++
+		}
+	}
+	return archive
+}
+
+// NewRestore prepares replaying the given archive into the environment the target kinds belong to.
+// It's a migration with the archive as source: configurations are matched by name and references
+// between them get rewritten to the IDs in the target environment. IDs of monitored entities are kept,
+// unless they're mapped via the Entities of the returned Migrator.
+// Kinds which aren't Restorable are excluded.
+func NewRestore(archive *Archive, target []apply.Kind) *migrate.Migrator {
+	source := archive.Kinds(target)
+	migrator := migrate.New(source, target)
+	migrator.KeepUnmappedEntities = true
+	migrator.Only = []string{}
+	for _, kind := range source {
+		if Restorable(kind) {
+			migrator.Only = append(migrator.Only, kind.Name())
+		}
+	}
+	return migrator
+}
+
+// Restrict limits a restore prepared by NewRestore to the kinds with the given names.
+// Naming a kind which isn't Restorable or isn't contained in the archive is an error.
+func Restrict(restore *migrate.Migrator, names []string) error {
+	restorable := map[string]bool{}
+	for _, name := range restore.Only {
+		restorable[name] = true
+	}
+	only := []string{}
+	invalid := []string{}
+	for _, name := range names {
+		if restorable[name] {
+			only = append(only, name)
+		} else {
+			invalid = append(invalid, name)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%s can't get restored, the archive contains restorable configurations of %s", strings.Join(invalid, ", "), strings.Join(restore.Only, ", "))
+	}
+	restore.Only = only
+	return nil
+}
+
+// Kinds returns read-only kinds serving the configurations in the archive.
+// Decoding is delegated to the kinds with the same name in the given list,
+// kinds without counterpart are omitted.
+func (me *Archive) Kinds(kinds []apply.Kind) []apply.Kind {
+	contained := map[string]bool{}
+	for _, summary := range me.Manifest.Kinds {
+		contained[summary.Kind] = true
+	}
+	for _, object := range me.Manifest.Objects {
+		contained[object.Kind] = true
+	}
+	result := []apply.Kind{}
+	for _, kind := range kinds {
+		if contained[kind.Name()] {
+			result = append(result, &archived{kind: kind, archive: me})
+		}
+	}
+	return result
+}
+
+// archived is a Kind serving the configurations within an archive
+type archived struct {
+	kind    apply.Kind
+	archive *Archive
+}
+
+func (me *archived) Name() string {
+	return me.kind.Name()
+}
+
+func (me *archived) DependsOn() []string {
+	return me.kind.DependsOn()
+}
+
+func (me *archived) Decode(data []byte) (interface{}, error) {
+	return me.kind.Decode(data)
+}
+
+func (me *archived) NameOf(v interface{}) string {
+	return me.kind.NameOf(v)
+}
+
+func (me *archived) List() ([]*api.EntityShortRepresentation, error) {
+	stubs := []*api.EntityShortRepresentation{}
+	for _, object := range me.archive.Objects(me.kind.Name()) {
+		stubs = append(stubs, &api.EntityShortRepresentation{ID: object.ID, Name: object.Name})
+	}
+	return stubs, nil
+}
+
+func (me *archived) Get(id string) (interface{}, error) {
+	for _, object := range me.archive.Objects(me.kind.Name()) {
+		if object.ID == id {
+			return me.kind.Decode(me.archive.Data(object))
+		}
+	}
+	return nil, fmt.Errorf("%s %s is not contained in the archive", me.kind.Name(), id)
+}
+
+func (me *archived) Create(v interface{}) (string, error) {
+	return "", errReadOnly
+}
+
+func (me *archived) Update(id string, v interface{}) error {
+	return errReadOnly
+}
+
+func (me *archived) Delete(id string) error {
+	return errReadOnly
+}
+
+var errReadOnly = errors.New("archives are read-only")
@@ -0,0 +1,27 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// canonical renders the given configuration as indented JSON with the keys of every object sorted,
+// so that backups of unchanged configurations are byte-wise identical
+func canonical(v interface{}) ([]byte, error) {
+	var err error
+	var data []byte
+	var generic interface{}
+
+	if data, err = json.Marshal(v); err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	if data, err = json.MarshalIndent(generic, "", "  "); err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
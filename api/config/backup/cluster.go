@@ -0,0 +1,113 @@
+package backup
+
+import (
+	"github.com/dtcookie/dynatrace/api/cluster/v1/groups"
+	"github.com/dtcookie/dynatrace/api/cluster/v1/users"
+	"github.com/dtcookie/dynatrace/api/cluster/v2/envs"
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/opt"
+)
+
+func environments(baseURL string, token string) *kind {
+	service := envs.NewService(baseURL, token)
+	return &kind{
+		name:   "environments",
+		new:    func() interface{} { return new(envs.Environment) },
+		nameOf: func(v interface{}) string { return v.(*envs.Environment).Name },
+		list: func() ([]*api.EntityShortRepresentation, error) {
+			environmentList, err := service.ListAll()
+			if err != nil {
+				return nil, err
+			}
+			stubs := []*api.EntityShortRepresentation{}
+			for _, environment := range environmentList.Environments {
+				stubs = append(stubs, &api.EntityShortRepresentation{ID: opt.String(environment.ID), Name: environment.Name})
+			}
+			return stubs, nil
+		},
+		get: func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*envs.Environment)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*envs.Environment)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func userGroups(baseURL string, token string) *kind {
+	service := groups.NewService(baseURL, token)
+	return &kind{
+		name:   "groups",
+		new:    func() interface{} { return new(groups.GroupConfig) },
+		nameOf: func(v interface{}) string { return v.(*groups.GroupConfig).Name },
+		list: func() ([]*api.EntityShortRepresentation, error) {
+			groupConfigs, err := service.ListAll()
+			if err != nil {
+				return nil, err
+			}
+			stubs := []*api.EntityShortRepresentation{}
+			for _, group := range groupConfigs {
+				stubs = append(stubs, &api.EntityShortRepresentation{ID: opt.String(group.ID), Name: group.Name})
+			}
+			return stubs, nil
+		},
+		get: func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*groups.GroupConfig)
+			config.ID = nil
+			created, err := service.Create(config)
+			if err != nil {
+				return "", err
+			}
+			return opt.String(created.ID), nil
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*groups.GroupConfig)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+// users are identified by their user name
+func clusterUsers(baseURL string, token string) *kind {
+	service := users.NewService(baseURL, token)
+	return &kind{
+		name:      "users",
+		dependsOn: []string{"groups"},
+		new:       func() interface{} { return new(users.UserConfig) },
+		nameOf:    func(v interface{}) string { return v.(*users.UserConfig).UserName },
+		list: func() ([]*api.EntityShortRepresentation, error) {
+			userConfigs, err := service.ListAll()
+			if err != nil {
+				return nil, err
+			}
+			stubs := []*api.EntityShortRepresentation{}
+			for _, user := range userConfigs {
+				stubs = append(stubs, &api.EntityShortRepresentation{ID: user.UserName, Name: user.UserName})
+			}
+			return stubs, nil
+		},
+		get: func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			created, err := service.Create(v.(*users.UserConfig))
+			if err != nil {
+				return "", err
+			}
+			return created.UserName, nil
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*users.UserConfig)
+			config.UserName = id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
@@ -0,0 +1,135 @@
+package backup
+
+import (
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/credentials/aws"
+	"github.com/dtcookie/dynatrace/api/config/credentials/azure"
+	"github.com/dtcookie/dynatrace/api/config/credentials/cloudfoundry"
+	"github.com/dtcookie/dynatrace/api/config/credentials/kubernetes"
+	"github.com/dtcookie/dynatrace/api/config/credentials/vault"
+	"github.com/dtcookie/opt"
+)
+
+// The API doesn't reveal the secrets of credentials.
+// They're part of the backup for reference only and won't get restored.
+
+func awsCredentials(baseURL string, token string) *kind {
+	service := aws.NewService(baseURL, token)
+	return &kind{
+		name:       "awscredentials",
+		backupOnly: true,
+		new:        func() interface{} { return new(aws.AWSCredentialsConfig) },
+		nameOf:     func(v interface{}) string { return v.(*aws.AWSCredentialsConfig).Label },
+		list:       func() ([]*api.EntityShortRepresentation, error) { return stubs(service.ListAll()) },
+		get:        func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*aws.AWSCredentialsConfig)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*aws.AWSCredentialsConfig)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func azureCredentials(baseURL string, token string) *kind {
+	service := azure.NewService(baseURL, token)
+	return &kind{
+		name:       "azurecredentials",
+		backupOnly: true,
+		new:        func() interface{} { return new(azure.AzureCredentials) },
+		nameOf:     func(v interface{}) string { return v.(*azure.AzureCredentials).Label },
+		list:       func() ([]*api.EntityShortRepresentation, error) { return stubs(service.ListAll()) },
+		get:        func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*azure.AzureCredentials)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*azure.AzureCredentials)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func cloudFoundryCredentials(baseURL string, token string) *kind {
+	service := cloudfoundry.NewService(baseURL, token)
+	return &kind{
+		name:       "cloudfoundrycredentials",
+		backupOnly: true,
+		new:        func() interface{} { return new(cloudfoundry.CloudFoundryCredentials) },
+		nameOf:     func(v interface{}) string { return v.(*cloudfoundry.CloudFoundryCredentials).Name },
+		list:       func() ([]*api.EntityShortRepresentation, error) { return stubs(service.ListAll()) },
+		get:        func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			return stub(service.Create(v.(*cloudfoundry.CloudFoundryCredentials)))
+		},
+		update: func(id string, v interface{}) error {
+			return service.Update(id, v.(*cloudfoundry.CloudFoundryCredentials))
+		},
+		delete: service.Delete,
+	}
+}
+
+func kubernetesCredentials(baseURL string, token string) *kind {
+	service := kubernetes.NewService(baseURL, token)
+	return &kind{
+		name:       "kubernetescredentials",
+		backupOnly: true,
+		new:        func() interface{} { return new(kubernetes.KubernetesCredentials) },
+		nameOf:     func(v interface{}) string { return v.(*kubernetes.KubernetesCredentials).Label },
+		list:       func() ([]*api.EntityShortRepresentation, error) { return stubs(service.ListAll()) },
+		get:        func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*kubernetes.KubernetesCredentials)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*kubernetes.KubernetesCredentials)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func vaultCredentials(baseURL string, token string) *kind {
+	service := vault.NewService(baseURL, token)
+	return &kind{
+		name:       "credentials",
+		backupOnly: true,
+		new:        func() interface{} { return new(vault.Credentials) },
+		nameOf:     func(v interface{}) string { return v.(*vault.Credentials).Name },
+		list: func() ([]*api.EntityShortRepresentation, error) {
+			credentialsList, err := service.ListAll()
+			if err != nil {
+				return nil, err
+			}
+			stubs := []*api.EntityShortRepresentation{}
+			for _, credentials := range credentialsList.Credentials {
+				stubs = append(stubs, &api.EntityShortRepresentation{ID: opt.String(credentials.ID), Name: credentials.Name})
+			}
+			return stubs, nil
+		},
+		get: func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*vault.Credentials)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*vault.Credentials)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
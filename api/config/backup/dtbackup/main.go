@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/apply"
+	"github.com/dtcookie/dynatrace/api/config/backup"
+	"github.com/dtcookie/dynatrace/api/config/migrate"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var environmentURL, apiToken string
+	var clusterURL, clusterToken string
+	var conflicts string
+	var entityMap string
+	var only string
+	var dryRun bool
+
+	command := os.Args[1]
+	flagSet := flag.NewFlagSet(os.Args[0]+" "+command, flag.ContinueOnError)
+	flagSet.StringVar(&environmentURL, "environment-url", os.Getenv("DT_ENVIRONMENT_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.StringVar(&clusterURL, "cluster-url", os.Getenv("DT_CLUSTER_URL"), "")
+	flagSet.StringVar(&clusterToken, "cluster-token", os.Getenv("DT_CLUSTER_TOKEN"), "")
+	if command == "restore" {
+		flagSet.StringVar(&conflicts, "conflicts", string(migrate.ConflictPolicies.Skip), "")
+		flagSet.StringVar(&entityMap, "entity-map", "", "")
+		flagSet.StringVar(&only, "only", "", "")
+		flagSet.BoolVar(&dryRun, "dry-run", false, "")
+	}
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[2:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if flagSet.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	archiveFile := flagSet.Arg(0)
+
+	switch command {
+	case "verify":
+		archive, err := backup.ReadFile(archiveFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		printSummary(archive)
+		return
+	case "backup", "restore":
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	kinds := []apply.Kind{}
+	sources := []string{}
+	if environmentURL != "" && apiToken != "" {
+		kinds = append(kinds, backup.Kinds(environmentURL, apiToken)...)
+		sources = append(sources, environmentURL)
+	}
+	if clusterURL != "" && clusterToken != "" {
+		kinds = append(kinds, backup.ClusterKinds(clusterURL, clusterToken)...)
+		sources = append(sources, clusterURL)
+	}
+	if len(kinds) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if command == "backup" {
+		archive := backup.Backup(strings.Join(sources, ","), kinds)
+		if err := archive.WriteFile(archiveFile); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		printSummary(archive)
+		return
+	}
+
+	archive, err := backup.ReadFile(archiveFile)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	restore := backup.NewRestore(archive, kinds)
+	restore.Conflicts = migrate.ConflictPolicy(conflicts)
+	restore.DryRun = dryRun
+	if only != "" {
+		if err := backup.Restrict(restore, strings.Split(only, ",")); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(2)
+		}
+	}
+	if entityMap != "" {
+		data, err := ioutil.ReadFile(entityMap)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if err = json.Unmarshal(data, &restore.Entities); err != nil {
+			fmt.Println(entityMap + ": " + err.Error())
+			os.Exit(1)
+		}
+	}
+	report, err := restore.Run()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(report.String())
+	if len(report.Failed()) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printSummary(archive *backup.Archive) {
+	fmt.Printf("format version %d, created %s from %s\n", archive.Manifest.FormatVersion, archive.Manifest.Created.Format("2006-01-02 15:04:05 MST"), archive.Manifest.Source)
+	for _, summary := range archive.Manifest.Kinds {
+		fmt.Printf("  %-30s %5d\n", summary.Kind, summary.Count)
+		for _, message := range summary.Errors {
+			fmt.Println("    ! " + message)
+		}
+	}
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtbackup backup [-environment-url <url>] [-api-token <api-token>] [-cluster-url <url>] [-cluster-token <cluster-token>] <archive-file>")
+	fmt.Println("       dtbackup restore [-environment-url <url>] [-api-token <api-token>] [-cluster-url <url>] [-cluster-token <cluster-token>] [-conflicts skip|overwrite|fail] [-only <kind>,...] [-entity-map <json-file>] [-dry-run] <archive-file>")
+	fmt.Println("       dtbackup verify <archive-file>")
+	fmt.Println("  <url> is the base URL of the environment, e.g. https://siz65484.live.dynatrace.com, or of the Managed cluster")
+	fmt.Println("  -conflicts   what to do with configurations already existing in the target environment (default: skip)")
+	fmt.Println("  -entity-map  JSON file mapping IDs of monitored entities in the archive to their IDs in the target environment")
+	fmt.Println("  -dry-run     only reports what would get restored")
+	fmt.Println("  Credentials are part of the archive without their secrets and therefore don't get restored.")
+	fmt.Println("  Hint: you can also define the environment variables DT_ENVIRONMENT_URL, DT_API_TOKEN, DT_CLUSTER_URL and DT_CLUSTER_TOKEN")
+}
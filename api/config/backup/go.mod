@@ -0,0 +1,88 @@
+module github.com/dtcookie/dynatrace/api/config/backup
+
+go 1.15
+
+require (
+	github.com/dtcookie/dynatrace/api/cluster/v1/groups v1.0.0
+	github.com/dtcookie/dynatrace/api/cluster/v1/users v1.0.0
+	github.com/dtcookie/dynatrace/api/cluster/v2/envs v1.0.0
+	github.com/dtcookie/dynatrace/api/config v1.0.10
+	github.com/dtcookie/dynatrace/api/config/anomalies/applications v1.0.0
+	github.com/dtcookie/dynatrace/api/config/anomalies/databaseservices v1.0.0
+	github.com/dtcookie/dynatrace/api/config/anomalies/diskevents v1.0.0
+	github.com/dtcookie/dynatrace/api/config/anomalies/hosts v1.0.0
+	github.com/dtcookie/dynatrace/api/config/anomalies/metricevents v1.0.0
+	github.com/dtcookie/dynatrace/api/config/anomalies/services v1.0.0
+	github.com/dtcookie/dynatrace/api/config/applications/mobile v1.0.0
+	github.com/dtcookie/dynatrace/api/config/applications/web v1.0.0
+	github.com/dtcookie/dynatrace/api/config/apply v1.0.0
+	github.com/dtcookie/dynatrace/api/config/credentials/aws v1.0.0
+	github.com/dtcookie/dynatrace/api/config/credentials/azure v1.0.0
+	github.com/dtcookie/dynatrace/api/config/credentials/cloudfoundry v1.0.0
+	github.com/dtcookie/dynatrace/api/config/credentials/kubernetes v1.0.0
+	github.com/dtcookie/dynatrace/api/config/credentials/vault v1.0.0
+	github.com/dtcookie/dynatrace/api/config/customservices v1.0.0
+	github.com/dtcookie/dynatrace/api/config/metrics/calculated/service v1.0.0
+	github.com/dtcookie/dynatrace/api/config/migrate v1.0.0
+	github.com/dtcookie/dynatrace/api/config/naming/hosts v1.0.0
+	github.com/dtcookie/dynatrace/api/config/naming/processgroups v1.0.0
+	github.com/dtcookie/dynatrace/api/config/naming/services v1.0.0
+	github.com/dtcookie/dynatrace/api/config/requestattributes v1.0.0
+	github.com/dtcookie/dynatrace/api/config/requestnaming v1.0.0
+	github.com/dtcookie/dynatrace/api/config/synthetic/monitors v1.0.0
+	github.com/dtcookie/dynatrace/api/config/v2/slo v1.0.0
+	github.com/dtcookie/dynatrace/api/config/v2/spans/attributes v1.0.0
+	github.com/dtcookie/dynatrace/api/config/v2/spans/capture v1.0.0
+	github.com/dtcookie/dynatrace/api/config/v2/spans/ctxprop v1.0.0
+	github.com/dtcookie/dynatrace/api/config/v2/spans/entrypoints v1.0.0
+	github.com/dtcookie/dynatrace/api/config/v2/spans/resattr v1.0.0
+	github.com/dtcookie/opt v1.0.0
+)
+
+replace (
+	github.com/dtcookie/dynatrace/api/cluster/v1/groups => ../../cluster/v1/groups
+	github.com/dtcookie/dynatrace/api/cluster/v1/users => ../../cluster/v1/users
+	github.com/dtcookie/dynatrace/api/cluster/v2/envs => ../../cluster/v2/envs
+	github.com/dtcookie/dynatrace/api/config => ..
+	github.com/dtcookie/dynatrace/api/config/alerting => ../alerting
+	github.com/dtcookie/dynatrace/api/config/anomalies/applications => ../anomalies/applications
+	github.com/dtcookie/dynatrace/api/config/anomalies/common => ../anomalies/common
+	github.com/dtcookie/dynatrace/api/config/anomalies/databaseservices => ../anomalies/databaseservices
+	github.com/dtcookie/dynatrace/api/config/anomalies/diskevents => ../anomalies/diskevents
+	github.com/dtcookie/dynatrace/api/config/anomalies/hosts => ../anomalies/hosts
+	github.com/dtcookie/dynatrace/api/config/anomalies/metricevents => ../anomalies/metricevents
+	github.com/dtcookie/dynatrace/api/config/anomalies/services => ../anomalies/services
+	github.com/dtcookie/dynatrace/api/config/applications/mobile => ../applications/mobile
+	github.com/dtcookie/dynatrace/api/config/applications/web => ../applications/web
+	github.com/dtcookie/dynatrace/api/config/apply => ../apply
+	github.com/dtcookie/dynatrace/api/config/autotags => ../autotags
+	github.com/dtcookie/dynatrace/api/config/common => ../common
+	github.com/dtcookie/dynatrace/api/config/credentials/aws => ../credentials/aws
+	github.com/dtcookie/dynatrace/api/config/credentials/azure => ../credentials/azure
+	github.com/dtcookie/dynatrace/api/config/credentials/cloudfoundry => ../credentials/cloudfoundry
+	github.com/dtcookie/dynatrace/api/config/credentials/kubernetes => ../credentials/kubernetes
+	github.com/dtcookie/dynatrace/api/config/credentials/vault => ../credentials/vault
+	github.com/dtcookie/dynatrace/api/config/customservices => ../customservices
+	github.com/dtcookie/dynatrace/api/config/dashboards => ../dashboards
+	github.com/dtcookie/dynatrace/api/config/diff => ../diff
+	github.com/dtcookie/dynatrace/api/config/entityruleengine => ../entityruleengine
+	github.com/dtcookie/dynatrace/api/config/maintenance => ../maintenance
+	github.com/dtcookie/dynatrace/api/config/managementzones => ../managementzones
+	github.com/dtcookie/dynatrace/api/config/metrics/calculated/service => ../metrics/calculated/service
+	github.com/dtcookie/dynatrace/api/config/migrate => ../migrate
+	github.com/dtcookie/dynatrace/api/config/naming/hosts => ../naming/hosts
+	github.com/dtcookie/dynatrace/api/config/naming/processgroups => ../naming/processgroups
+	github.com/dtcookie/dynatrace/api/config/naming/services => ../naming/services
+	github.com/dtcookie/dynatrace/api/config/notifications => ../notifications
+	github.com/dtcookie/dynatrace/api/config/requestattributes => ../requestattributes
+	github.com/dtcookie/dynatrace/api/config/requestnaming => ../requestnaming
+	github.com/dtcookie/dynatrace/api/config/synthetic/monitors => ../synthetic/monitors
+	github.com/dtcookie/dynatrace/api/config/v2/slo => ../v2/slo
+	github.com/dtcookie/dynatrace/api/config/v2/spans/attributes => ../v2/spans/attributes
+	github.com/dtcookie/dynatrace/api/config/v2/spans/capture => ../v2/spans/capture
+	github.com/dtcookie/dynatrace/api/config/v2/spans/ctxprop => ../v2/spans/ctxprop
+	github.com/dtcookie/dynatrace/api/config/v2/spans/entrypoints => ../v2/spans/entrypoints
+	github.com/dtcookie/dynatrace/api/config/v2/spans/match => ../v2/spans/match
+	github.com/dtcookie/dynatrace/api/config/v2/spans/resattr => ../v2/spans/resattr
+	github.com/dtcookie/dynatrace/rest => ../../../rest
+)
@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/apply"
+)
+
+// kind is an apply.Kind assembled from the functions of a ServiceClient
+type kind struct {
+	name       string
+	dependsOn  []string
+	backupOnly bool // the configurations can get backed up, but the archive lacks what's necessary to restore them
+	new        func() interface{}
+	nameOf     func(v interface{}) string
+	list       func() ([]*api.EntityShortRepresentation, error)
+	get        func(id string) (interface{}, error)
+	create     func(v interface{}) (string, error)
+	update     func(id string, v interface{}) error
+	delete     func(id string) error
+}
+
+func (me *kind) Name() string {
+	return me.name
+}
+
+func (me *kind) DependsOn() []string {
+	return me.dependsOn
+}
+
+func (me *kind) Decode(data []byte) (interface{}, error) {
+	v := me.new()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (me *kind) NameOf(v interface{}) string {
+	return me.nameOf(v)
+}
+
+func (me *kind) List() ([]*api.EntityShortRepresentation, error) {
+	return me.list()
+}
+
+func (me *kind) Get(id string) (interface{}, error) {
+	return me.get(id)
+}
+
+func (me *kind) Create(v interface{}) (string, error) {
+	return me.create(v)
+}
+
+func (me *kind) Update(id string, v interface{}) error {
+	return me.update(id, v)
+}
+
+func (me *kind) Delete(id string) error {
+	return me.delete(id)
+}
+
+// Restorable returns false for kinds whose configurations can't get restored from an archive,
+// like credentials, for which the API doesn't reveal the secrets
+func Restorable(k apply.Kind) bool {
+	switch k := k.(type) {
+	case *kind:
+		return !k.backupOnly
+	case *archived:
+		return Restorable(k.kind)
+	}
+	return true
+}
+
+// singleton creates a Kind for a configuration which exists exactly once per environment.
+// Its name serves as ID, creating it means updating it and it can't get deleted.
+func singleton(name string, new func() interface{}, get func() (interface{}, error), update func(v interface{}) error) *kind {
+	return &kind{
+		name:   name,
+		new:    new,
+		nameOf: func(v interface{}) string { return name },
+		list: func() ([]*api.EntityShortRepresentation, error) {
+			return []*api.EntityShortRepresentation{{ID: name, Name: name}}, nil
+		},
+		get: func(id string) (interface{}, error) { return get() },
+		create: func(v interface{}) (string, error) {
+			return name, update(v)
+		},
+		update: func(id string, v interface{}) error { return update(v) },
+		delete: func(id string) error { return fmt.Errorf("%s can't be deleted", name) },
+	}
+}
+
+// refs converts the given references into short representations
+func refs(entityRefs *api.EntityRefs, err error) ([]*api.EntityShortRepresentation, error) {
+	if err != nil {
+		return nil, err
+	}
+	stubs := []*api.EntityShortRepresentation{}
+	for _, ref := range entityRefs.Values {
+		name := ref.ID
+		if ref.Name != nil {
+			name = *ref.Name
+		}
+		stubs = append(stubs, &api.EntityShortRepresentation{ID: ref.ID, Name: name})
+	}
+	return stubs, nil
+}
+
+// stubs returns the values of the given list
+func stubs(stubList *api.StubList, err error) ([]*api.EntityShortRepresentation, error) {
+	if err != nil {
+		return nil, err
+	}
+	return stubList.Values, nil
+}
+
+// fetched creates short representations for APIs which only list IDs by fetching every configuration
+func fetched(ids []string, err error, get func(id string) (interface{}, error), nameOf func(v interface{}) string) ([]*api.EntityShortRepresentation, error) {
+	if err != nil {
+		return nil, err
+	}
+	stubs := []*api.EntityShortRepresentation{}
+	for _, id := range ids {
+		v, err := get(id)
+		if err != nil {
+			return nil, err
+		}
+		stubs = append(stubs, &api.EntityShortRepresentation{ID: id, Name: nameOf(v)})
+	}
+	return stubs, nil
+}
+
+// ref returns the ID of the given reference
+func ref(entityRef *api.EntityRef, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	return entityRef.ID, nil
+}
+
+// stub returns the ID of the given short representation
+func stub(stub *api.EntityShortRepresentation, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	return stub.ID, nil
+}
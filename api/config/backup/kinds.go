@@ -0,0 +1,63 @@
+package backup
+
+import (
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/apply"
+)
+
+// Kinds returns a Kind for every configuration type of an environment which is part of a backup.
+// environmentURL should look like this: "https://siz65484.live.dynatrace.com"
+// token is an API Token
+func Kinds(environmentURL string, token string) []apply.Kind {
+	environmentURL = strings.TrimSuffix(environmentURL, "/")
+	v1 := environmentURL + "/api/config/v1"
+	v2 := environmentURL + "/api/v2"
+
+	kinds := apply.Kinds(v1, token)
+	kinds = append(kinds,
+		applicationAnomalies(v1, token),
+		databaseAnomalies(v1, token),
+		hostAnomalies(v1, token),
+		serviceAnomalies(v1, token),
+		diskEvents(v1, token),
+		metricEvents(v1, token),
+		webApplications(v1, token),
+		mobileApplications(v1, token),
+		hostNamings(v1, token),
+		processGroupNamings(v1, token),
+		serviceNamings(v1, token),
+		calculatedServiceMetrics(v1, token),
+		requestAttributes(v1, token),
+		requestNamings(v1, token),
+		browserMonitors(v1, token),
+		httpMonitors(v1, token),
+		awsCredentials(v1, token),
+		azureCredentials(v1, token),
+		cloudFoundryCredentials(v1, token),
+		kubernetesCredentials(v1, token),
+		vaultCredentials(v1, token),
+		slos(v2, token),
+		spanAttributes(v2, token),
+		spanCaptureRules(v2, token),
+		spanContextPropagationRules(v2, token),
+		spanEntryPoints(v2, token),
+		resourceAttributes(v2, token),
+	)
+	for _, kind := range customServices(v1, token) {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// ClusterKinds returns a Kind for the environments, user groups and users of a Dynatrace Managed cluster
+// clusterURL should look like this: "https://mycluster.example.com"
+// token is a Cluster API Token
+func ClusterKinds(clusterURL string, token string) []apply.Kind {
+	clusterURL = strings.TrimSuffix(clusterURL, "/")
+	return []apply.Kind{
+		environments(clusterURL+"/api/cluster/v2", token),
+		userGroups(clusterURL+"/api/cluster/v1", token),
+		clusterUsers(clusterURL+"/api/cluster/v1", token),
+	}
+}
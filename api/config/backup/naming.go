@@ -0,0 +1,74 @@
+package backup
+
+import (
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/naming/hosts"
+	"github.com/dtcookie/dynatrace/api/config/naming/processgroups"
+	"github.com/dtcookie/dynatrace/api/config/naming/services"
+)
+
+func hostNamings(baseURL string, token string) *kind {
+	service := hosts.NewService(baseURL, token)
+	return &kind{
+		name:   "hostnamings",
+		new:    func() interface{} { return new(hosts.NamingRule) },
+		nameOf: func(v interface{}) string { return v.(*hosts.NamingRule).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return stubs(service.List()) },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*hosts.NamingRule)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*hosts.NamingRule)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func processGroupNamings(baseURL string, token string) *kind {
+	service := processgroups.NewService(baseURL, token)
+	return &kind{
+		name:   "processgroupnamings",
+		new:    func() interface{} { return new(processgroups.NamingRule) },
+		nameOf: func(v interface{}) string { return v.(*processgroups.NamingRule).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return stubs(service.List()) },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*processgroups.NamingRule)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*processgroups.NamingRule)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func serviceNamings(baseURL string, token string) *kind {
+	service := services.NewService(baseURL, token)
+	return &kind{
+		name:   "servicenamings",
+		new:    func() interface{} { return new(services.NamingRule) },
+		nameOf: func(v interface{}) string { return v.(*services.NamingRule).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return stubs(service.List()) },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*services.NamingRule)
+			config.ID = nil
+			return stub(service.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*services.NamingRule)
+			config.ID = &id
+			return service.Update(config)
+		},
+		delete: service.Delete,
+	}
+}
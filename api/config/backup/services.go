@@ -0,0 +1,106 @@
+package backup
+
+import (
+	"strings"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/customservices"
+	"github.com/dtcookie/dynatrace/api/config/metrics/calculated/service"
+	"github.com/dtcookie/dynatrace/api/config/requestattributes"
+	"github.com/dtcookie/dynatrace/api/config/requestnaming"
+)
+
+func customServices(baseURL string, token string) []*kind {
+	client := customservices.NewService(baseURL, token)
+	technologies := []customservices.Technology{
+		customservices.Technologies.DotNet,
+		customservices.Technologies.Go,
+		customservices.Technologies.Java,
+		customservices.Technologies.NodeJS,
+		customservices.Technologies.PHP,
+	}
+	kinds := []*kind{}
+	for _, technology := range technologies {
+		technology := technology
+		kinds = append(kinds, &kind{
+			name:   "customservices-" + strings.ToLower(string(technology)),
+			new:    func() interface{} { return new(customservices.CustomService) },
+			nameOf: func(v interface{}) string { return v.(*customservices.CustomService).Name },
+			list:   func() ([]*api.EntityShortRepresentation, error) { return stubs(client.List(technology)) },
+			get:    func(id string) (interface{}, error) { return client.Get(id, technology, false) },
+			create: func(v interface{}) (string, error) {
+				config := v.(*customservices.CustomService)
+				config.ID = nil
+				return stub(client.Create(config, technology))
+			},
+			update: func(id string, v interface{}) error {
+				config := v.(*customservices.CustomService)
+				config.ID = &id
+				return client.Update(config, technology)
+			},
+			delete: func(id string) error { return client.Delete(id, technology) },
+		})
+	}
+	return kinds
+}
+
+// calculatedServiceMetrics are identified by their metric key
+func calculatedServiceMetrics(baseURL string, token string) *kind {
+	client := service.NewService(baseURL, token)
+	return &kind{
+		name:   "calculatedservicemetrics",
+		new:    func() interface{} { return new(service.CalculatedServiceMetric) },
+		nameOf: func(v interface{}) string { return v.(*service.CalculatedServiceMetric).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return refs(client.ListAll()) },
+		get:    func(id string) (interface{}, error) { return client.Get(id) },
+		create: func(v interface{}) (string, error) {
+			return ref(client.Create(v.(*service.CalculatedServiceMetric)))
+		},
+		update: func(id string, v interface{}) error {
+			return client.Update(v.(*service.CalculatedServiceMetric))
+		},
+		delete: client.Delete,
+	}
+}
+
+func requestAttributes(baseURL string, token string) *kind {
+	client := requestattributes.NewService(baseURL, token)
+	return &kind{
+		name:   "requestattributes",
+		new:    func() interface{} { return new(requestattributes.RequestAttribute) },
+		nameOf: func(v interface{}) string { return v.(*requestattributes.RequestAttribute).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return stubs(client.ListAll()) },
+		get:    func(id string) (interface{}, error) { return client.Get(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*requestattributes.RequestAttribute)
+			config.ID = nil
+			return stub(client.Create(config))
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*requestattributes.RequestAttribute)
+			config.ID = &id
+			return client.Update(config)
+		},
+		delete: client.Delete,
+	}
+}
+
+// requestNamings are matched by their naming pattern
+func requestNamings(baseURL string, token string) *kind {
+	client := requestnaming.NewService(baseURL, token)
+	return &kind{
+		name:      "requestnamings",
+		dependsOn: []string{"requestattributes"},
+		new:       func() interface{} { return new(requestnaming.RequestNaming) },
+		nameOf:    func(v interface{}) string { return v.(*requestnaming.RequestNaming).NamingPattern },
+		list:      func() ([]*api.EntityShortRepresentation, error) { return stubs(client.ListAll()) },
+		get:       func(id string) (interface{}, error) { return client.Get(id) },
+		create: func(v interface{}) (string, error) {
+			return stub(client.Create(v.(*requestnaming.RequestNaming)))
+		},
+		update: func(id string, v interface{}) error {
+			return client.Update(id, v.(*requestnaming.RequestNaming))
+		},
+		delete: client.Delete,
+	}
+}
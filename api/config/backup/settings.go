@@ -0,0 +1,137 @@
+package backup
+
+import (
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/v2/slo"
+	"github.com/dtcookie/dynatrace/api/config/v2/spans/attributes"
+	"github.com/dtcookie/dynatrace/api/config/v2/spans/capture"
+	"github.com/dtcookie/dynatrace/api/config/v2/spans/ctxprop"
+	"github.com/dtcookie/dynatrace/api/config/v2/spans/entrypoints"
+	"github.com/dtcookie/dynatrace/api/config/v2/spans/resattr"
+)
+
+// The APIs below only list IDs, the names are determined by fetching every single configuration
+
+func slos(baseURL string, token string) *kind {
+	service := slo.NewService(baseURL, token)
+	k := &kind{
+		name:   "slos",
+		new:    func() interface{} { return new(slo.SLO) },
+		nameOf: func(v interface{}) string { return v.(*slo.SLO).Name },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) { return service.Create(v.(*slo.SLO)) },
+		update: func(id string, v interface{}) error { return service.Update(id, v.(*slo.SLO)) },
+		delete: service.Delete,
+	}
+	k.list = func() ([]*api.EntityShortRepresentation, error) {
+		ids, err := service.List()
+		return fetched(ids, err, k.get, k.nameOf)
+	}
+	return k
+}
+
+func spanAttributes(baseURL string, token string) *kind {
+	service := attributes.NewService(baseURL, token)
+	k := &kind{
+		name:   "spanattributes",
+		new:    func() interface{} { return new(attributes.SpanAttribute) },
+		nameOf: func(v interface{}) string { return v.(*attributes.SpanAttribute).Key },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) { return service.Create(v.(*attributes.SpanAttribute)) },
+		update: func(id string, v interface{}) error { return service.Update(id, v.(*attributes.SpanAttribute)) },
+		delete: service.Delete,
+	}
+	k.list = func() ([]*api.EntityShortRepresentation, error) {
+		ids, err := service.List()
+		return fetched(ids, err, k.get, k.nameOf)
+	}
+	return k
+}
+
+func spanCaptureRules(baseURL string, token string) *kind {
+	service := capture.NewService(baseURL, token)
+	k := &kind{
+		name: "spancapturerules",
+		new:  func() interface{} { return new(capture.SpanCaptureSetting) },
+		nameOf: func(v interface{}) string {
+			if setting := v.(*capture.SpanCaptureSetting); setting.SpanCaptureRule != nil {
+				return setting.SpanCaptureRule.Name
+			}
+			return ""
+		},
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) { return service.Create(v.(*capture.SpanCaptureSetting)) },
+		update: func(id string, v interface{}) error { return service.Update(id, v.(*capture.SpanCaptureSetting)) },
+		delete: service.Delete,
+	}
+	k.list = func() ([]*api.EntityShortRepresentation, error) {
+		ids, err := service.List()
+		return fetched(ids, err, k.get, k.nameOf)
+	}
+	return k
+}
+
+func spanContextPropagationRules(baseURL string, token string) *kind {
+	service := ctxprop.NewService(baseURL, token)
+	k := &kind{
+		name: "spancontextpropagationrules",
+		new:  func() interface{} { return new(ctxprop.PropagationSetting) },
+		nameOf: func(v interface{}) string {
+			if setting := v.(*ctxprop.PropagationSetting); setting.PropagationRule != nil {
+				return setting.PropagationRule.Name
+			}
+			return ""
+		},
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) { return service.Create(v.(*ctxprop.PropagationSetting)) },
+		update: func(id string, v interface{}) error { return service.Update(id, v.(*ctxprop.PropagationSetting)) },
+		delete: service.Delete,
+	}
+	k.list = func() ([]*api.EntityShortRepresentation, error) {
+		ids, err := service.List()
+		return fetched(ids, err, k.get, k.nameOf)
+	}
+	return k
+}
+
+func spanEntryPoints(baseURL string, token string) *kind {
+	service := entrypoints.NewService(baseURL, token)
+	k := &kind{
+		name: "spanentrypoints",
+		new:  func() interface{} { return new(entrypoints.SpanEntryPoint) },
+		nameOf: func(v interface{}) string {
+			if setting := v.(*entrypoints.SpanEntryPoint); setting.EntryPointRule != nil {
+				return setting.EntryPointRule.Name
+			}
+			return ""
+		},
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) { return service.Create(v.(*entrypoints.SpanEntryPoint)) },
+		update: func(id string, v interface{}) error { return service.Update(id, v.(*entrypoints.SpanEntryPoint)) },
+		delete: service.Delete,
+	}
+	k.list = func() ([]*api.EntityShortRepresentation, error) {
+		ids, err := service.List()
+		return fetched(ids, err, k.get, k.nameOf)
+	}
+	return k
+}
+
+// resourceAttributes exist once per environment, hence they're all named after the kind
+func resourceAttributes(baseURL string, token string) *kind {
+	service := resattr.NewService(baseURL, token)
+	k := &kind{
+		name:   "resourceattributes",
+		new:    func() interface{} { return new(resattr.ResourceAttributes) },
+		nameOf: func(v interface{}) string { return "resourceattributes" },
+		get:    func(id string) (interface{}, error) { return service.Get(id) },
+		create: func(v interface{}) (string, error) { return service.Create(v.(*resattr.ResourceAttributes)) },
+		update: func(id string, v interface{}) error { return service.Update(id, v.(*resattr.ResourceAttributes)) },
+		delete: service.Delete,
+	}
+	k.list = func() ([]*api.EntityShortRepresentation, error) {
+		ids, err := service.List()
+		return fetched(ids, err, k.get, k.nameOf)
+	}
+	return k
+}
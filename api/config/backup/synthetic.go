@@ -0,0 +1,66 @@
+package backup
+
+import (
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/synthetic/monitors"
+	"github.com/dtcookie/opt"
+)
+
+// monitorStubs converts the given synthetic monitors into short representations
+func monitorStubs(list *monitors.Monitors, err error) ([]*api.EntityShortRepresentation, error) {
+	if err != nil {
+		return nil, err
+	}
+	stubs := []*api.EntityShortRepresentation{}
+	for _, monitor := range list.Monitors {
+		stubs = append(stubs, &api.EntityShortRepresentation{ID: monitor.EntityID, Name: monitor.Name})
+	}
+	return stubs, nil
+}
+
+func browserMonitors(baseURL string, token string) *kind {
+	service := monitors.NewService(baseURL, token)
+	return &kind{
+		name:      "browsermonitors",
+		dependsOn: []string{"webapplications"},
+		new:       func() interface{} { return new(monitors.BrowserSyntheticMonitorUpdate) },
+		nameOf:    func(v interface{}) string { return v.(*monitors.BrowserSyntheticMonitorUpdate).Name },
+		list:      func() ([]*api.EntityShortRepresentation, error) { return monitorStubs(service.ListBrowser()) },
+		get:       func(id string) (interface{}, error) { return service.GetBrowser(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*monitors.BrowserSyntheticMonitorUpdate)
+			config.ID = nil
+			id, err := service.CreateBrowser(config)
+			return opt.String(id), err
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*monitors.BrowserSyntheticMonitorUpdate)
+			config.ID = &id
+			return service.UpdateBrowser(config)
+		},
+		delete: service.Delete,
+	}
+}
+
+func httpMonitors(baseURL string, token string) *kind {
+	service := monitors.NewService(baseURL, token)
+	return &kind{
+		name:   "httpmonitors",
+		new:    func() interface{} { return new(monitors.HTTPSyntheticMonitorUpdate) },
+		nameOf: func(v interface{}) string { return v.(*monitors.HTTPSyntheticMonitorUpdate).Name },
+		list:   func() ([]*api.EntityShortRepresentation, error) { return monitorStubs(service.ListHTTP()) },
+		get:    func(id string) (interface{}, error) { return service.GetHTTP(id) },
+		create: func(v interface{}) (string, error) {
+			config := v.(*monitors.HTTPSyntheticMonitorUpdate)
+			config.ID = nil
+			id, err := service.CreateHTTP(config)
+			return opt.String(id), err
+		},
+		update: func(id string, v interface{}) error {
+			config := v.(*monitors.HTTPSyntheticMonitorUpdate)
+			config.ID = &id
+			return service.UpdateHTTP(config)
+		},
+		delete: service.Delete,
+	}
+}
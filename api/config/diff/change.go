@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of a change, named after the according JSON Patch operation
+type Op string
+
+// Ops offers the known enum values
+var Ops = struct {
+	Add     Op
+	Remove  Op
+	Replace Op
+}{
+	"add",
+	"remove",
+	"replace",
+}
+
+// Change is a single difference between two configurations
+type Change struct {
+	Op    Op          `json:"op"`
+	Path  string      `json:"path"`            // JSON Pointer to the changed property
+	From  interface{} `json:"from,omitempty"`  // the previous value; nil for Add
+	Value interface{} `json:"value,omitempty"` // the new value; nil for Remove
+}
+
+func (me *Change) String() string {
+	switch me.Op {
+	case Ops.Add:
+		return fmt.Sprintf("+ %s: %s", me.Path, render(me.Value))
+	case Ops.Remove:
+		return fmt.Sprintf("- %s: %s", me.Path, render(me.From))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", me.Path, render(me.From), render(me.Value))
+	}
+}
+
+func render(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// Changes is the list of differences between two configurations
+type Changes []*Change
+
+// Empty tells whether there are no differences
+func (me Changes) Empty() bool {
+	return len(me) == 0
+}
+
+// String renders one line per change
+func (me Changes) String() string {
+	lines := []string{}
+	for _, change := range me {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Operation is a single JSON Patch (RFC 6902) operation
+type Operation struct {
+	Op    Op          `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patch returns the changes as JSON Patch operations.
+// Applied in order to the normalized form of the `from` configuration they produce the normalized `to` configuration.
+func (me Changes) Patch() []*Operation {
+	operations := []*Operation{}
+	for _, change := range me {
+		operation := &Operation{Op: change.Op, Path: change.Path}
+		if change.Op != Ops.Remove {
+			operation.Value = change.Value
+			if operation.Value == nil {
+				// JSON Patch requires a value for add and replace
+				operation.Value = json.RawMessage("null")
+			}
+		}
+		operations = append(operations, operation)
+	}
+	return operations
+}
+
+// JSONPatch returns the changes as JSON Patch document
+func (me Changes) JSONPatch() ([]byte, error) {
+	return json.MarshalIndent(me.Patch(), "", "  ")
+}
@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Compare determines the differences between two configurations of the same type,
+// using the profile registered for that type
+func Compare(from interface{}, to interface{}) (Changes, error) {
+	if to != nil {
+		return CompareWith(from, to, ProfileOf(to))
+	}
+	return CompareWith(from, to, ProfileOf(from))
+}
+
+// CompareWith determines the differences between two configurations using the given profile
+func CompareWith(from interface{}, to interface{}, profile *Profile) (Changes, error) {
+	var err error
+	var a, b interface{}
+
+	if a, err = Normalize(from, profile); err != nil {
+		return nil, err
+	}
+	if b, err = Normalize(to, profile); err != nil {
+		return nil, err
+	}
+	return compare(pointer{}, a, b, profile), nil
+}
+
+func compare(path pointer, a interface{}, b interface{}, profile *Profile) Changes {
+	switch ta := a.(type) {
+	case map[string]interface{}:
+		if tb, ok := b.(map[string]interface{}); ok {
+			return compareMaps(path, ta, tb, profile)
+		}
+	case []interface{}:
+		if tb, ok := b.([]interface{}); ok {
+			if matchesAny(path, profile.Unordered) {
+				return compareSets(path, ta, tb)
+			}
+			return compareSlices(path, ta, tb, profile)
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return Changes{}
+	}
+	return Changes{&Change{Op: Ops.Replace, Path: path.String(), From: a, Value: b}}
+}
+
+func compareMaps(path pointer, a map[string]interface{}, b map[string]interface{}, profile *Profile) Changes {
+	keys := []string{}
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, found := a[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := Changes{}
+	for _, key := range keys {
+		va, inA := a[key]
+		vb, inB := b[key]
+		switch {
+		case !inB:
+			changes = append(changes, &Change{Op: Ops.Remove, Path: path.append(key).String(), From: va})
+		case !inA:
+			changes = append(changes, &Change{Op: Ops.Add, Path: path.append(key).String(), Value: vb})
+		default:
+			changes = append(changes, compare(path.append(key), va, vb, profile)...)
+		}
+	}
+	return changes
+}
+
+// compareSlices compares element by element. Surplus elements get removed from the end, missing ones appended.
+func compareSlices(path pointer, a []interface{}, b []interface{}, profile *Profile) Changes {
+	changes := Changes{}
+	common := len(a)
+	if len(b) < common {
+		common = len(b)
+	}
+	for idx := 0; idx < common; idx++ {
+		changes = append(changes, compare(path.index(idx), a[idx], b[idx], profile)...)
+	}
+	for idx := len(a) - 1; idx >= common; idx-- {
+		changes = append(changes, &Change{Op: Ops.Remove, Path: path.index(idx).String(), From: a[idx]})
+	}
+	for idx := common; idx < len(b); idx++ {
+		changes = append(changes, &Change{Op: Ops.Add, Path: path.index(idx).String(), Value: b[idx]})
+	}
+	return changes
+}
+
+// compareSets matches the elements regardless of their position.
+// Elements without a counterpart get removed, highest index first, and the missing ones appended.
+func compareSets(path pointer, a []interface{}, b []interface{}) Changes {
+	matched := make([]bool, len(b))
+	removed := []int{}
+	for ia, va := range a {
+		found := false
+		for ib, vb := range b {
+			if !matched[ib] && reflect.DeepEqual(va, vb) {
+				matched[ib] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, ia)
+		}
+	}
+
+	changes := Changes{}
+	for i := len(removed) - 1; i >= 0; i-- {
+		changes = append(changes, &Change{Op: Ops.Remove, Path: path.index(removed[i]).String(), From: a[removed[i]]})
+	}
+	length := len(a) - len(removed)
+	for ib, vb := range b {
+		if !matched[ib] {
+			changes = append(changes, &Change{Op: Ops.Add, Path: path.index(length).String(), Value: vb})
+			length++
+		}
+	}
+	return changes
+}
@@ -0,0 +1,251 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/config/diff"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+)
+
+const current = `{
+	"id": "123",
+	"metadata": { "clusterVersion": "1.230" },
+	"name": "production",
+	"serverSideOnly": true,
+	"rules": [
+		{ "type": "HOST", "enabled": true, "propagationTypes": ["HOST_TO_PROCESS_GROUP_INSTANCE"], "conditions": [] },
+		{ "type": "SERVICE", "enabled": true, "propagationTypes": ["SERVICE_TO_HOST_LIKE", "SERVICE_TO_PROCESS_GROUP_LIKE"], "conditions": [] }
+	]
+}`
+
+const desired = `{
+	"name": "production",
+	"rules": [
+		{ "type": "SERVICE", "enabled": true, "propagationTypes": ["SERVICE_TO_PROCESS_GROUP_LIKE", "SERVICE_TO_HOST_LIKE"], "conditions": [] },
+		{ "type": "HOST", "enabled": true, "propagationTypes": ["HOST_TO_PROCESS_GROUP_INSTANCE"], "conditions": [] }
+	]
+}`
+
+func decode(t *testing.T, s string) *managementzones.ManagementZone {
+	var mz managementzones.ManagementZone
+	if err := json.Unmarshal([]byte(s), &mz); err != nil {
+		t.Fatal(err)
+	}
+	return &mz
+}
+
+func TestIgnoresServerManagedAndOrder(t *testing.T) {
+	changes, err := diff.Compare(decode(t, current), decode(t, desired))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Empty() {
+		t.Errorf("expected no changes, got\n%s", changes.String())
+	}
+}
+
+func TestDetectsChanges(t *testing.T) {
+	to := decode(t, desired)
+	to.Name = "prod"
+	to.Rules = to.Rules[:1]
+	changes, err := diff.Compare(decode(t, current), to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got\n%s", changes.String())
+	}
+	if changes[0].Op != diff.Ops.Replace || changes[0].Path != "/name" {
+		t.Errorf("expected name to be replaced, got %s", changes[0].String())
+	}
+	if changes[1].Op != diff.Ops.Remove || changes[1].Path != "/rules/0" {
+		t.Errorf("expected the HOST rule to be removed, got %s", changes[1].String())
+	}
+	if _, err := changes.JSONPatch(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnknowns(t *testing.T) {
+	from := decode(t, desired)
+	to := decode(t, desired)
+	to.Unknowns = map[string]json.RawMessage{"futureProperty": json.RawMessage(`"added later"`)}
+
+	changes, err := diff.Compare(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Empty() {
+		t.Errorf("expected unknown properties to be ignored, got\n%s", changes.String())
+	}
+
+	profile := *diff.ProfileOf(to)
+	profile.KeepUnknowns = true
+	if changes, err = diff.CompareWith(from, to, &profile); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Op != diff.Ops.Add || changes[0].Path != "/futureProperty" || changes[0].Value != "added later" {
+		t.Errorf("expected the unknown property to be added, got\n%s", changes.String())
+	}
+	if len(to.Unknowns) != 1 {
+		t.Error("expected the configuration itself to be left untouched")
+	}
+}
+
+func TestNestedUnordered(t *testing.T) {
+	from := map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{"type": "HOST", "conditions": []interface{}{"a", "b", "c"}},
+			map[string]interface{}{"type": "SERVICE", "conditions": []interface{}{"d"}},
+		},
+	}
+	to := map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{"type": "SERVICE", "conditions": []interface{}{"d"}},
+			map[string]interface{}{"type": "HOST", "conditions": []interface{}{"c", "a", "b"}},
+		},
+	}
+
+	changes, err := diff.CompareWith(from, to, &diff.Profile{Unordered: []string{"/rules", "/rules/*/conditions"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Empty() {
+		t.Errorf("expected no changes, got\n%s", changes.String())
+	}
+
+	if changes, err = diff.CompareWith(from, to, &diff.Profile{Unordered: []string{"/rules"}}); err != nil {
+		t.Fatal(err)
+	}
+	if changes.Empty() {
+		t.Error("expected the order of the conditions to matter if only the rules are unordered")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	from := map[string]interface{}{
+		"name":     "production",
+		"obsolete": true,
+		"list":     []interface{}{1, 2, 3},
+		"set":      []interface{}{"a", "b", "c"},
+	}
+	to := map[string]interface{}{
+		"name":  "prod",
+		"added": map[string]interface{}{"enabled": true},
+		"list":  []interface{}{1, 5},
+		"set":   []interface{}{"d", "c", "a"},
+	}
+	profile := &diff.Profile{Unordered: []string{"/set"}}
+
+	changes, err := diff.CompareWith(from, to, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		op   diff.Op
+		path string
+	}{
+		{diff.Ops.Add, "/added"},
+		{diff.Ops.Replace, "/list/1"},
+		{diff.Ops.Remove, "/list/2"},
+		{diff.Ops.Replace, "/name"},
+		{diff.Ops.Remove, "/obsolete"},
+		{diff.Ops.Remove, "/set/1"},
+		{diff.Ops.Add, "/set/2"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got\n%s", len(expected), changes.String())
+	}
+	for i, change := range changes {
+		if change.Op != expected[i].op || change.Path != expected[i].path {
+			t.Errorf("expected change #%d to be '%s %s', got %s", i, expected[i].op, expected[i].path, change.String())
+		}
+	}
+
+	doc, err := diff.Normalize(from, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := changes.JSONPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var operations []map[string]interface{}
+	if err = json.Unmarshal(data, &operations); err != nil {
+		t.Fatal(err)
+	}
+	for _, operation := range operations {
+		doc = apply(t, doc, parse(operation["path"].(string)), diff.Op(operation["op"].(string)), operation["value"])
+	}
+	if changes, err = diff.CompareWith(doc, to, profile); err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Empty() {
+		t.Errorf("expected the patched configuration to match, got\n%s", changes.String())
+	}
+}
+
+func TestPointerEscaping(t *testing.T) {
+	from := map[string]interface{}{"headers": map[string]interface{}{"a/b": "1", "m~n": "2", "ignored/x": "3"}}
+	to := map[string]interface{}{"headers": map[string]interface{}{"a/b": "3", "m~n": "4", "ignored/x": "5"}}
+
+	changes, err := diff.CompareWith(from, to, &diff.Profile{Ignore: []string{"/headers/ignored~1x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got\n%s", changes.String())
+	}
+	if changes[0].Path != "/headers/a~1b" {
+		t.Errorf("expected '/' to be escaped as '~1', got %s", changes[0].Path)
+	}
+	if changes[1].Path != "/headers/m~0n" {
+		t.Errorf("expected '~' to be escaped as '~0', got %s", changes[1].Path)
+	}
+}
+
+// parse splits a JSON Pointer into its unescaped segments
+func parse(path string) []string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	return segments
+}
+
+// apply performs a single JSON Patch operation on a generic JSON document and returns the modified document
+func apply(t *testing.T, doc interface{}, path []string, op diff.Op, value interface{}) interface{} {
+	t.Helper()
+	switch tv := doc.(type) {
+	case map[string]interface{}:
+		if len(path) > 1 {
+			tv[path[0]] = apply(t, tv[path[0]], path[1:], op, value)
+		} else if op == diff.Ops.Remove {
+			delete(tv, path[0])
+		} else {
+			tv[path[0]] = value
+		}
+		return tv
+	case []interface{}:
+		idx, err := strconv.Atoi(path[0])
+		if err != nil || idx > len(tv) {
+			t.Fatalf("invalid index '%s'", path[0])
+		}
+		switch {
+		case len(path) > 1:
+			tv[idx] = apply(t, tv[idx], path[1:], op, value)
+		case op == diff.Ops.Remove:
+			tv = append(tv[:idx], tv[idx+1:]...)
+		case op == diff.Ops.Add:
+			tv = append(tv[:idx], append([]interface{}{value}, tv[idx:]...)...)
+		default:
+			tv[idx] = value
+		}
+		return tv
+	}
+	t.Fatalf("cannot apply '%s' to %v", op, doc)
+	return nil
+}
@@ -0,0 +1,26 @@
+module github.com/dtcookie/dynatrace/api/config/diff
+
+go 1.15
+
+require (
+	github.com/dtcookie/dynatrace/api/config v1.0.10
+	github.com/dtcookie/dynatrace/api/config/alerting v1.0.0
+	github.com/dtcookie/dynatrace/api/config/autotags v1.0.0
+	github.com/dtcookie/dynatrace/api/config/dashboards v1.0.0
+	github.com/dtcookie/dynatrace/api/config/maintenance v1.0.0
+	github.com/dtcookie/dynatrace/api/config/managementzones v1.0.0
+	github.com/dtcookie/dynatrace/api/config/notifications v1.0.0
+)
+
+replace (
+	github.com/dtcookie/dynatrace/api/config => ..
+	github.com/dtcookie/dynatrace/api/config/alerting => ../alerting
+	github.com/dtcookie/dynatrace/api/config/autotags => ../autotags
+	github.com/dtcookie/dynatrace/api/config/common => ../common
+	github.com/dtcookie/dynatrace/api/config/dashboards => ../dashboards
+	github.com/dtcookie/dynatrace/api/config/entityruleengine => ../entityruleengine
+	github.com/dtcookie/dynatrace/api/config/maintenance => ../maintenance
+	github.com/dtcookie/dynatrace/api/config/managementzones => ../managementzones
+	github.com/dtcookie/dynatrace/api/config/notifications => ../notifications
+	github.com/dtcookie/dynatrace/rest => ../../../rest
+)
@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+)
+
+var serverManagedTypes = []reflect.Type{
+	reflect.TypeOf(new(api.ConfigMetadata)),
+	reflect.TypeOf(new(api.ConfigurationMetadata)),
+}
+
+// Normalize returns the generic JSON representation (maps, slices and primitives) of a configuration
+// with server managed and ignored properties removed and unordered arrays sorted according to the given profile.
+// The configuration itself doesn't get modified.
+func Normalize(v interface{}, profile *Profile) (interface{}, error) {
+	var err error
+	var data []byte
+
+	if v == nil {
+		return nil, nil
+	}
+	if data, err = json.Marshal(v); err != nil {
+		return nil, err
+	}
+	if t := reflect.TypeOf(v); t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		// work on a copy in order to be able to strip fields without touching the original
+		clone := reflect.New(t.Elem())
+		if err = json.Unmarshal(data, clone.Interface()); err != nil {
+			return nil, err
+		}
+		scrub(clone, profile)
+		if data, err = json.Marshal(clone.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	var result interface{}
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if props, ok := result.(map[string]interface{}); ok && !profile.KeepServerIDs {
+		delete(props, "id")
+	}
+	return canonicalize(pointer{}, result, profile), nil
+}
+
+// canonicalize removes the ignored properties and sorts the unordered arrays, innermost first,
+// so that equal sets end up with equal representations
+func canonicalize(path pointer, v interface{}, profile *Profile) interface{} {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for key, elem := range tv {
+			if matchesAny(path.append(key), profile.Ignore) {
+				delete(tv, key)
+				continue
+			}
+			tv[key] = canonicalize(path.append(key), elem, profile)
+		}
+	case []interface{}:
+		for idx, elem := range tv {
+			tv[idx] = canonicalize(path.index(idx), elem, profile)
+		}
+		if matchesAny(path, profile.Unordered) {
+			keys := make([]string, len(tv))
+			for idx, elem := range tv {
+				keys[idx] = render(elem)
+			}
+			sort.Sort(&byKey{keys: keys, values: tv})
+		}
+	}
+	return v
+}
+
+type byKey struct {
+	keys   []string
+	values []interface{}
+}
+
+func (me *byKey) Len() int           { return len(me.keys) }
+func (me *byKey) Less(i, j int) bool { return me.keys[i] < me.keys[j] }
+func (me *byKey) Swap(i, j int) {
+	me.keys[i], me.keys[j] = me.keys[j], me.keys[i]
+	me.values[i], me.values[j] = me.values[j], me.values[i]
+}
+
+// scrub clears the metadata and, unless the profile says otherwise, the unknown properties
+// of the given value and everything reachable from it
+func scrub(v reflect.Value, profile *Profile) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			scrub(v.Elem(), profile)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			scrub(v.Index(i), profile)
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() == reflect.Ptr || v.Type().Elem().Kind() == reflect.Interface {
+			for _, key := range v.MapKeys() {
+				scrub(v.MapIndex(key), profile)
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if t.Field(i).Name == "Unknowns" && field.Kind() == reflect.Map {
+				if !profile.KeepUnknowns {
+					field.Set(reflect.Zero(field.Type()))
+				}
+				continue
+			}
+			if isServerManaged(field.Type()) {
+				field.Set(reflect.Zero(field.Type()))
+				continue
+			}
+			scrub(field, profile)
+		}
+	}
+}
+
+func isServerManaged(t reflect.Type) bool {
+	for _, serverManaged := range serverManagedTypes {
+		if t == serverManaged {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+)
+
+// pointer is a parsed JSON Pointer (RFC 6901)
+type pointer []string
+
+func (p pointer) append(segment string) pointer {
+	result := make(pointer, len(p), len(p)+1)
+	copy(result, p)
+	return append(result, segment)
+}
+
+func (p pointer) index(idx int) pointer {
+	return p.append(strconv.Itoa(idx))
+}
+
+func (p pointer) String() string {
+	if len(p) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, segment := range p {
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// matches tells whether the pointer matches the given pattern, where a segment `*` matches any single segment
+func (p pointer) matches(pattern string) bool {
+	segments := parsePointer(pattern)
+	if len(segments) != len(p) {
+		return false
+	}
+	for i, segment := range segments {
+		if segment != "*" && segment != p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func parsePointer(s string) pointer {
+	if s == "" || s == "/" {
+		return pointer{}
+	}
+	segments := strings.Split(strings.TrimPrefix(s, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	return segments
+}
+
+func matchesAny(p pointer, patterns []string) bool {
+	for _, pattern := range patterns {
+		if p.matches(pattern) {
+			return true
+		}
+	}
+	return false
+}
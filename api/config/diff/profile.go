@@ -0,0 +1,97 @@
+package diff
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/dashboards"
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	"github.com/dtcookie/dynatrace/api/config/notifications"
+)
+
+// Profile tells the diff engine how to compare configurations of a specific type.
+// Paths are JSON Pointers (RFC 6901) into the JSON representation of the configuration.
+// A segment `*` matches any single property name or array index.
+type Profile struct {
+	Ignore        []string // paths of properties which are not taken into account
+	Unordered     []string // paths of arrays which are compared as sets, i.e. the order of their elements doesn't matter
+	KeepUnknowns  bool     // also compare properties the SDK doesn't know about (the `Unknowns` of a configuration)
+	KeepServerIDs bool     // also compare the top level `id`
+}
+
+// DefaultProfile is used for configuration types which haven't been registered
+var DefaultProfile = &Profile{}
+
+var lock sync.RWMutex
+var profiles = map[reflect.Type]*Profile{}
+
+// Register defines the profile to use when comparing configurations of the same type as sample
+func Register(sample interface{}, profile *Profile) {
+	lock.Lock()
+	defer lock.Unlock()
+	profiles[reflect.TypeOf(sample)] = profile
+}
+
+// ProfileOf returns the profile registered for the type of v or DefaultProfile
+func ProfileOf(v interface{}) *Profile {
+	lock.RLock()
+	defer lock.RUnlock()
+	if profile, found := profiles[reflect.TypeOf(v)]; found {
+		return profile
+	}
+	return DefaultProfile
+}
+
+func init() {
+	Register(new(dashboards.Dashboard), &Profile{
+		Unordered: []string{
+			"/dashboardMetadata/tags",
+			"/dashboardMetadata/validFilterKeys",
+			"/tiles",
+			"/tiles/*/assignedEntities",
+		},
+	})
+	Register(new(managementzones.ManagementZone), &Profile{
+		Unordered: []string{
+			"/rules",
+			"/rules/*/conditions",
+			"/rules/*/propagationTypes",
+			"/dimensionalRules",
+			"/dimensionalRules/*/conditions",
+			"/entitySelectorBasedRules",
+		},
+	})
+	Register(new(autotags.AutoTag), &Profile{
+		Unordered: []string{
+			"/rules",
+			"/rules/*/conditions",
+			"/rules/*/propagationTypes",
+			"/entitySelectorBasedRules",
+		},
+	})
+	// the severity rules of an alerting profile are evaluated top to bottom, hence their order matters
+	Register(new(alerting.Profile), &Profile{
+		Unordered: []string{
+			"/rules/*/tagFilter/tagFilters",
+			"/eventTypeFilters",
+		},
+	})
+	Register(new(maintenance.Window), &Profile{
+		Unordered: []string{
+			"/scope/entities",
+			"/scope/matches",
+			"/scope/matches/*/tags",
+		},
+	})
+	Register(new(notifications.NotificationRecord), &Profile{
+		Unordered: []string{
+			"/receivers",
+			"/ccReceivers",
+			"/bccReceivers",
+			"/headers",
+		},
+	})
+}
@@ -0,0 +1,137 @@
+package drift
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/apply"
+	"github.com/dtcookie/dynatrace/api/config/diff"
+)
+
+// Type classifies how a configuration deviates from its desired state
+type Type string
+
+// Types offers the known enum values
+var Types = struct {
+	Modified  Type // the configuration has been changed
+	Missing   Type // the configuration has been deleted
+	Unmanaged Type // the configuration has been created outside of the desired state
+}{
+	"MODIFIED",
+	"MISSING",
+	"UNMANAGED",
+}
+
+var types = map[apply.Action]Type{
+	apply.Actions.Update: Types.Modified,
+	apply.Actions.Create: Types.Missing,
+	apply.Actions.Delete: Types.Unmanaged,
+}
+
+// Drift is a configuration which doesn't match its desired state
+type Drift struct {
+	Kind  string       `json:"kind"`
+	Name  string       `json:"name"`
+	ID    string       `json:"id,omitempty"`   // empty for Missing
+	File  string       `json:"file,omitempty"` // the file holding the desired state; empty for Unmanaged
+	Type  Type         `json:"type"`
+	Diff  diff.Changes `json:"diff,omitempty"`
+	Since time.Time    `json:"since"` // when the drift has been detected first
+	// ConfigurationVersions and ClusterVersion are taken from the ConfigurationMetadata of the current configuration, where available.
+	// They tell which revision of the configuration has been edited and on which Dynatrace version.
+	ConfigurationVersions []int64 `json:"configurationVersions,omitempty"`
+	ClusterVersion        string  `json:"clusterVersion,omitempty"`
+}
+
+func (me *Drift) key() string {
+	return me.Kind + "/" + me.Name + "/" + string(me.Type)
+}
+
+func (me *Drift) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s '%s'", strings.ToLower(string(me.Type)), me.Kind, me.Name))
+	if len(me.ID) > 0 {
+		sb.WriteString(fmt.Sprintf(" (%s)", me.ID))
+	}
+	sb.WriteString(" since " + me.Since.Format(time.RFC3339))
+	if len(me.ConfigurationVersions) > 0 {
+		sb.WriteString(fmt.Sprintf(", configuration versions %v", me.ConfigurationVersions))
+	}
+	for _, change := range me.Diff {
+		sb.WriteString("\n    " + change.String())
+	}
+	return sb.String()
+}
+
+// Report is the outcome of a single check for drifts
+type Report struct {
+	Time        time.Time     `json:"time"`
+	Drifts      []*Drift      `json:"drifts"`
+	Kinds       []string      `json:"kinds"`                // the kinds that have been checked
+	Remediation *apply.Report `json:"-"`                    // the changes applied to revert the drifts; nil unless remediation is enabled
+	Remediated  []string      `json:"remediated,omitempty"` // the drifts reverted successfully
+	Errors      []string      `json:"errors,omitempty"`     // remediations which failed
+}
+
+// Count returns the number of drifts of the given kind and type
+func (me *Report) Count(kind string, t Type) int {
+	count := 0
+	for _, drift := range me.Drifts {
+		if drift.Kind == kind && drift.Type == t {
+			count++
+		}
+	}
+	return count
+}
+
+func (me *Report) String() string {
+	if len(me.Drifts) == 0 {
+		return "no drift detected"
+	}
+	lines := []string{fmt.Sprintf("%d drift(s) detected", len(me.Drifts))}
+	for _, drift := range me.Drifts {
+		lines = append(lines, "  "+drift.String())
+	}
+	for _, remediated := range me.Remediated {
+		lines = append(lines, "  remediated "+remediated)
+	}
+	for _, err := range me.Errors {
+		lines = append(lines, "  ! "+err)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// metadata returns the configuration metadata of the given configuration, if it has one.
+// Depending on the kind the metadata is either an api.ConfigurationMetadata or an api.ConfigMetadata.
+func metadata(v interface{}) *api.ConfigurationMetadata {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() || !field.CanInterface() {
+			continue
+		}
+		switch md := field.Interface().(type) {
+		case *api.ConfigurationMetadata:
+			return md
+		case *api.ConfigMetadata:
+			result := &api.ConfigurationMetadata{ConfigurationVersions: md.ConfigurationVersions, CurrentConfigurationVersions: md.CurrentConfigurationVersions}
+			if md.ClusterVersion != nil {
+				result.ClusterVersion = *md.ClusterVersion
+			}
+			return result
+		}
+	}
+	return nil
+}
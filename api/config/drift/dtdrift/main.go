@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/apply"
+	"github.com/dtcookie/dynatrace/api/config/drift"
+)
+
+func main() {
+	var apiBaseURL string
+	var apiToken string
+	var interval time.Duration
+	var webhook string
+	var metrics bool
+	var remediate bool
+	var prune bool
+	var once bool
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&apiBaseURL, "api-base-url", os.Getenv("DT_API_BASE_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.DurationVar(&interval, "interval", 5*time.Minute, "")
+	flagSet.StringVar(&webhook, "webhook", "", "")
+	flagSet.BoolVar(&metrics, "metrics", false, "")
+	flagSet.BoolVar(&remediate, "remediate", false, "")
+	flagSet.BoolVar(&prune, "prune", false, "")
+	flagSet.BoolVar(&once, "once", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if apiBaseURL == "" || apiToken == "" || flagSet.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	watcher := drift.New(flagSet.Arg(0), apply.Kinds(apiBaseURL, apiToken)...)
+	watcher.Interval = interval
+	watcher.Remediate = remediate
+	watcher.Prune = prune
+	watcher.Sinks = append(watcher.Sinks, &drift.LogSink{})
+	if webhook != "" {
+		watcher.Sinks = append(watcher.Sinks, drift.NewWebhookSink(webhook))
+	}
+	if metrics {
+		watcher.Sinks = append(watcher.Sinks, drift.NewMetricSink(strings.TrimSuffix(strings.TrimSuffix(apiBaseURL, "/"), "/config/v1")+"/v2", apiToken))
+	}
+
+	if once {
+		report, err := watcher.Check()
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if len(report.Drifts) > 0 {
+			os.Exit(3)
+		}
+		return
+	}
+
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+	watcher.Run(stop)
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtdrift [-api-base-url <api-base-url>] [-api-token <api-token>] [-interval <duration>] [-webhook <url>] [-metrics] [-remediate] [-prune] [-once] <config-dir>")
+	fmt.Println("  <config-dir> holds the desired state in the layout expected by dtapply")
+	fmt.Println("  -interval   time between two checks (default: 5m)")
+	fmt.Println("  -webhook    posts reports containing drifts as JSON to the given URL")
+	fmt.Println("  -metrics    reports the number of drifts as metric '" + drift.MetricKey + "' via the metrics ingest API of the environment")
+	fmt.Println("  -remediate  reverts drifts by applying the desired state")
+	fmt.Println("  -prune      reports configurations without a file as unmanaged (and deletes them with -remediate)")
+	fmt.Println("  -once       checks only once; exits with code 3 in case drifts have been detected")
+	fmt.Println("  Hint: you can also define the environment variables DT_API_BASE_URL and DT_API_TOKEN")
+}
@@ -0,0 +1,131 @@
+package drift
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/log"
+	"github.com/dtcookie/dynatrace/rest/credentials"
+)
+
+// Sink receives the report of every check
+type Sink interface {
+	Send(report *Report) error
+}
+
+// LogSink logs the detected drifts
+type LogSink struct{}
+
+// Send logs the drifts of the report as warnings, a check without drifts as info
+func (me *LogSink) Send(report *Report) error {
+	if len(report.Drifts) == 0 && len(report.Errors) == 0 {
+		log.Info(report.String())
+		return nil
+	}
+	log.Warn(report.String())
+	return nil
+}
+
+// WebhookSink posts reports as JSON to a URL
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string // additional headers, e.g. for authentication
+	// OnlyDrifts suppresses reports without drifts
+	OnlyDrifts bool
+	client     *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink for the given URL
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, Headers: map[string]string{}, OnlyDrifts: true, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Send posts the report
+func (me *WebhookSink) Send(report *Report) error {
+	if me.OnlyDrifts && len(report.Drifts) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, me.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range me.Headers {
+		request.Header.Set(k, v)
+	}
+	response, err := me.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s responded with %s", me.URL, response.Status)
+	}
+	return nil
+}
+
+// MetricKey is the key of the metric the MetricSink reports the number of drifts as
+const MetricKey = "config.drift"
+
+// MetricSink reports the number of drifts per kind and type via the metrics ingest API.
+// Every checked kind is reported for every type, so the metric drops to 0 once drifts got resolved.
+type MetricSink struct {
+	url         string
+	credentials credentials.Credentials
+	client      *http.Client
+}
+
+// NewMetricSink creates a MetricSink
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/v2"
+// token is an API Token with the permission to ingest metrics
+func NewMetricSink(baseURL string, token string) *MetricSink {
+	return &MetricSink{url: strings.TrimSuffix(baseURL, "/") + "/metrics/ingest", credentials: credentials.New(token), client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Lines renders the report in the metrics ingest line protocol
+func (me *MetricSink) Lines(report *Report) []string {
+	kinds := append([]string{}, report.Kinds...)
+	sort.Strings(kinds)
+	timestamp := report.Time.UnixNano() / int64(time.Millisecond)
+	lines := []string{}
+	for _, kind := range kinds {
+		for _, t := range []Type{Types.Modified, Types.Missing, Types.Unmanaged} {
+			lines = append(lines, fmt.Sprintf("%s,kind=%s,type=%s gauge,%d %d", MetricKey, kind, strings.ToLower(string(t)), report.Count(kind, t), timestamp))
+		}
+	}
+	return lines
+}
+
+// Send posts the number of drifts
+func (me *MetricSink) Send(report *Report) error {
+	lines := me.Lines(report)
+	if len(lines) == 0 {
+		return nil
+	}
+	request, err := http.NewRequest(http.MethodPost, me.url, strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if err = me.credentials.Authenticate(request); err != nil {
+		return err
+	}
+	response, err := me.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("ingesting metrics failed: %s", response.Status)
+	}
+	return nil
+}
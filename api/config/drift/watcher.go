@@ -0,0 +1,109 @@
+package drift
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/apply"
+	"github.com/dtcookie/dynatrace/log"
+)
+
+// Watcher periodically compares the configuration of an environment against the desired state
+// in a directory and reports the deviations to its sinks
+type Watcher struct {
+	dir        string
+	reconciler *apply.Reconciler
+	since      map[string]time.Time // when currently ongoing drifts have been detected first
+	// Interval is the time between two checks
+	Interval time.Duration
+	// Sinks receive the report of every check
+	Sinks []Sink
+	// Remediate reverts drifts by applying the desired state
+	Remediate bool
+	// Prune reports configurations of the managed kinds without a file as unmanaged,
+	// and deletes them in case Remediate is enabled
+	Prune bool
+}
+
+// New creates a Watcher for the desired state in the given directory.
+// The directory gets read anew for every check, changes to it, e.g. by a `git pull`, don't require a restart.
+func New(dir string, kinds ...apply.Kind) *Watcher {
+	return &Watcher{dir: dir, reconciler: apply.New(kinds...), since: map[string]time.Time{}, Interval: 5 * time.Minute, Sinks: []Sink{}}
+}
+
+// Check compares the configuration once and hands the report to the sinks
+func (me *Watcher) Check() (*Report, error) {
+	var err error
+	var desired *apply.Desired
+	var plan *apply.Plan
+
+	me.reconciler.Prune = me.Prune
+	if desired, err = me.reconciler.Load(me.dir); err != nil {
+		return nil, err
+	}
+	if plan, err = me.reconciler.Plan(desired); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	report := &Report{Time: now, Drifts: []*Drift{}, Kinds: []string{}}
+	for kind := range desired.Configs {
+		report.Kinds = append(report.Kinds, kind)
+	}
+	since := map[string]time.Time{}
+	remediation := &apply.Plan{Changes: []*apply.Change{}}
+	for _, change := range plan.Changes {
+		if change.Action == apply.Actions.NoOp {
+			continue
+		}
+		drift := &Drift{Kind: change.Kind.Name(), Name: change.Name, ID: change.ID, File: change.File, Type: types[change.Action], Diff: change.Diff}
+		if md := metadata(change.Current); md != nil {
+			drift.ConfigurationVersions = md.ConfigurationVersions
+			drift.ClusterVersion = md.ClusterVersion
+		}
+		var found bool
+		if drift.Since, found = me.since[drift.key()]; !found {
+			drift.Since = now
+		}
+		since[drift.key()] = drift.Since
+		report.Drifts = append(report.Drifts, drift)
+		remediation.Changes = append(remediation.Changes, change)
+	}
+	me.since = since
+
+	if me.Remediate && !remediation.Empty() {
+		report.Remediation = me.reconciler.Apply(remediation)
+		for _, result := range report.Remediation.Results {
+			if result.Error != nil {
+				report.Errors = append(report.Errors, result.String())
+			} else {
+				report.Remediated = append(report.Remediated, result.String())
+				delete(me.since, (&Drift{Kind: result.Change.Kind.Name(), Name: result.Change.Name, Type: types[result.Change.Action]}).key())
+			}
+		}
+	}
+
+	for _, sink := range me.Sinks {
+		if err := sink.Send(report); err != nil {
+			log.Error(fmt.Errorf("sending drift report failed: %s", err.Error()))
+		}
+	}
+	return report, nil
+}
+
+// Run checks periodically until the given channel gets closed.
+// Failing checks are logged and don't stop the Watcher.
+func (me *Watcher) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(me.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := me.Check(); err != nil {
+			log.Error(fmt.Errorf("checking for drift failed: %s", err.Error()))
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package drift_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/drift"
+	"github.com/dtcookie/opt"
+)
+
+type zone struct {
+	Metadata *api.ConfigMetadata `json:"metadata,omitempty"`
+	Name     string              `json:"name"`
+	Value    string              `json:"value"`
+}
+
+// zones is a read-only Kind holding a single modified configuration
+type zones struct{}
+
+func (me *zones) Name() string        { return "managementzones" }
+func (me *zones) DependsOn() []string { return []string{} }
+
+func (me *zones) Decode(data []byte) (interface{}, error) {
+	var z zone
+	err := json.Unmarshal(data, &z)
+	return &z, err
+}
+
+func (me *zones) NameOf(v interface{}) string { return v.(*zone).Name }
+
+func (me *zones) List() ([]*api.EntityShortRepresentation, error) {
+	return []*api.EntityShortRepresentation{{ID: "mz-1", Name: "production"}}, nil
+}
+
+func (me *zones) Get(id string) (interface{}, error) {
+	metadata := &api.ConfigMetadata{ClusterVersion: opt.NewString("1.230.0"), ConfigurationVersions: []int64{4, 7}}
+	return &zone{Metadata: metadata, Name: "production", Value: "edited"}, nil
+}
+
+func (me *zones) Create(v interface{}) (string, error)  { return "", nil }
+func (me *zones) Update(id string, v interface{}) error { return nil }
+func (me *zones) Delete(id string) error                { return nil }
+
+func TestWatcherReportsVersions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "managementzones"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "managementzones", "production.json"), []byte(`{"name":"production","value":"desired"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := drift.New(dir, &zones{}).Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Drifts) != 1 || report.Drifts[0].Type != drift.Types.Modified {
+		t.Fatalf("expected a single modification, got\n%s", report.String())
+	}
+	d := report.Drifts[0]
+	if d.ClusterVersion != "1.230.0" {
+		t.Errorf("expected cluster version 1.230.0, got '%s'", d.ClusterVersion)
+	}
+	if len(d.ConfigurationVersions) != 2 || d.ConfigurationVersions[1] != 7 {
+		t.Errorf("expected configuration versions [4 7], got %v", d.ConfigurationVersions)
+	}
+}
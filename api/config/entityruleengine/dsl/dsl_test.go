@@ -0,0 +1,104 @@
+package dsl_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/dsl"
+)
+
+func TestRoundTrip(t *testing.T) {
+	text := strings.Join([]string{
+		`PROCESS_GROUP_TAGS EQUALS [KUBERNETES]env:prod`,
+		`HOST_NAME NOT BEGINS_WITH "web- 01" CASE_SENSITIVE`,
+		`HOST_IP_ADDRESS IS_IP_IN_RANGE 10.0.0.0/16`,
+		`SERVICE_PORT GREATER_THAN 8000`,
+		`SERVICE_TYPE EQUALS WEB_SERVICE`,
+		`PROCESS_GROUP_TECHNOLOGY EQUALS MyTech`,
+		`PROCESS_GROUP_PREDEFINED_METADATA[KUBERNETES_NAMESPACE] EQUALS "AND"`,
+		`HOST_CUSTOM_METADATA[ENVIRONMENT:team \] name] EXISTS`,
+		`HOST_KUBERNETES_LABELS[app] EQUALS shop AS INDEXED_STRING`,
+	}, " AND ")
+
+	conditions, err := dsl.Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conditions) != 9 {
+		t.Fatalf("expected 9 conditions, got %d", len(conditions))
+	}
+	formatted, err := dsl.Format(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted != text {
+		t.Errorf("expected\n%s\ngot\n%s", text, formatted)
+	}
+
+	// the parsed conditions survive a JSON round trip unchanged
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*entityruleengine.Condition
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if formatted, err = dsl.Format(decoded); err != nil {
+		t.Fatal(err)
+	}
+	if formatted != text {
+		t.Errorf("expected after JSON round trip\n%s\ngot\n%s", text, formatted)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, test := range []struct {
+		text   string
+		line   int
+		column int
+	}{
+		{`HOST_NAME BEGINS_WITH web AND HOST_OS_TYPE EQUALS BEOS`, 1, 51},
+		{"HOST_NAME EXISTS AND\n  SERVICE_PORT CONTAINS 80", 2, 16},
+		{`NO_SUCH_ATTRIBUTE EXISTS`, 1, 1},
+		{`HOST_NAME EQUALS "unterminated`, 1, 18},
+		{`SERVICE_PORT EQUALS eighty`, 1, 21},
+		{`HOST_NAME EQUALS web OR HOST_NAME EQUALS db`, 1, 22},
+		{`HOST_NAME BEGINS_WITH`, 1, 22},
+		{`HOST_NAME EQUALS Ålesund OR HOST_NAME EQUALS db`, 1, 26},
+	} {
+		_, err := dsl.Parse(test.text)
+		if err == nil {
+			t.Errorf("expected '%s' to fail", test.text)
+			continue
+		}
+		e, ok := err.(*dsl.Error)
+		if !ok {
+			t.Errorf("expected *dsl.Error, got %T", err)
+			continue
+		}
+		if e.Line != test.line || e.Column != test.column {
+			t.Errorf("'%s': expected error at %d:%d, got %s", test.text, test.line, test.column, e.Error())
+		}
+	}
+}
+
+func TestNonASCII(t *testing.T) {
+	text := `HOST_NAME EQUALS voilà AND HOST_NAME EQUALS Ålesund AND HOST_GROUP_NAME CONTAINS "東京 α"`
+	conditions, err := dsl.Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conditions) != 3 {
+		t.Fatalf("expected 3 conditions, got %d", len(conditions))
+	}
+	formatted, err := dsl.Format(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted != text {
+		t.Errorf("expected\n%s\ngot\n%s", text, formatted)
+	}
+}
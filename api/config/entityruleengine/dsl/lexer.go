@@ -0,0 +1,144 @@
+package dsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Error is a syntax or validation error at a specific position of the parsed text
+type Error struct {
+	Offset int // byte offset within the text
+	Line   int // 1-based
+	Column int // 1-based, counted in characters
+	Msg    string
+}
+
+func (me *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", me.Line, me.Column, me.Msg)
+}
+
+type token struct {
+	text   string
+	quoted bool
+	offset int
+}
+
+// is reports whether the token is the given keyword, keywords are case insensitive and never quoted
+func (me *token) is(keyword string) bool {
+	return me != nil && !me.quoted && strings.EqualFold(me.text, keyword)
+}
+
+type lexer struct {
+	input  string
+	offset int
+}
+
+func (me *lexer) errorAt(offset int, format string, args ...interface{}) *Error {
+	line := 1 + strings.Count(me.input[:offset], "\n")
+	start := strings.LastIndex(me.input[:offset], "\n") + 1
+	return &Error{Offset: offset, Line: line, Column: 1 + len([]rune(me.input[start:offset])), Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokens splits the input into words and quoted strings.
+// Within a word everything between `[` and `]` belongs to the word, including whitespace.
+func (me *lexer) tokens() ([]*token, error) {
+	tokens := []*token{}
+	for {
+		for me.offset < len(me.input) {
+			space, width := me.space()
+			if !space {
+				break
+			}
+			me.offset += width
+		}
+		if me.offset >= len(me.input) {
+			return tokens, nil
+		}
+		start := me.offset
+		if me.input[start] == '"' {
+			text, err := me.quoted()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, &token{text: text, quoted: true, offset: start})
+			continue
+		}
+		for me.offset < len(me.input) {
+			if me.input[me.offset] == '[' {
+				if err := me.bracket(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			space, width := me.space()
+			if space {
+				break
+			}
+			me.offset += width
+		}
+		tokens = append(tokens, &token{text: me.input[start:me.offset], offset: start})
+	}
+}
+
+// space reports whether the character at the current offset is whitespace, along with its width in bytes
+func (me *lexer) space() (bool, int) {
+	r, width := utf8.DecodeRuneInString(me.input[me.offset:])
+	return unicode.IsSpace(r), width
+}
+
+func (me *lexer) quoted() (string, error) {
+	start := me.offset
+	me.offset++
+	for me.offset < len(me.input) {
+		switch me.input[me.offset] {
+		case '\\':
+			me.offset += 2
+		case '"':
+			me.offset++
+			text, err := strconv.Unquote(me.input[start:me.offset])
+			if err != nil {
+				return "", me.errorAt(start, "invalid quoted string %s", me.input[start:me.offset])
+			}
+			return text, nil
+		default:
+			me.offset++
+		}
+	}
+	return "", me.errorAt(start, "unterminated quoted string")
+}
+
+func (me *lexer) bracket() error {
+	start := me.offset
+	me.offset++
+	for me.offset < len(me.input) {
+		switch me.input[me.offset] {
+		case '\\':
+			me.offset += 2
+		case ']':
+			me.offset++
+			return nil
+		default:
+			me.offset++
+		}
+	}
+	return me.errorAt(start, "missing ']'")
+}
+
+// unescape removes the backslashes escaping `]` and `\` within brackets
+func unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,243 @@
+package dsl
+
+import (
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/condition"
+)
+
+// Keywords of the DSL. They are case insensitive and need to be quoted when used as values.
+const (
+	And             = "AND"
+	Not             = "NOT"
+	As              = "AS"
+	CaseSensitive   = "CASE_SENSITIVE"
+	CaseInsensitive = "CASE_INSENSITIVE"
+)
+
+const exists = "EXISTS"
+
+// Parse parses a list of conditions combined with AND, e.g.
+//
+//	PROCESS_GROUP_TAGS EQUALS env:prod AND HOST_NAME BEGINS_WITH "web-" CASE_INSENSITIVE
+//
+// Every condition consists of
+//
+//	<attribute>[<dynamic key>] [NOT] <operator> [<value>] [CASE_SENSITIVE | CASE_INSENSITIVE] [AS <comparison type>]
+//
+// The type of comparison is derived from the attribute (see TypeOf) unless specified with AS.
+// Values containing whitespace or matching a keyword need to be quoted.
+// Tags are written as `key`, `key:value` or `[CONTEXT]key:value`.
+// The dynamic key of custom metadata is written as `[SOURCE:key]`.
+// Errors returned are of type *Error.
+func Parse(text string) ([]*entityruleengine.Condition, error) {
+	lexer := &lexer{input: text}
+	tokens, err := lexer.tokens()
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{lexer: lexer, tokens: tokens}
+	conditions := []*entityruleengine.Condition{}
+	for {
+		c, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+		next := p.next()
+		if next == nil {
+			return conditions, nil
+		}
+		if !next.is(And) {
+			return nil, p.errorAt(next, "expected AND but found '%s'", next.text)
+		}
+	}
+}
+
+// ParseCondition parses a single condition
+func ParseCondition(text string) (*entityruleengine.Condition, error) {
+	conditions, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(conditions) != 1 {
+		return nil, &Error{Line: 1, Column: 1, Msg: "expected a single condition"}
+	}
+	return conditions[0], nil
+}
+
+type parser struct {
+	lexer  *lexer
+	tokens []*token
+	pos    int
+}
+
+func (me *parser) next() *token {
+	if me.pos >= len(me.tokens) {
+		return nil
+	}
+	t := me.tokens[me.pos]
+	me.pos++
+	return t
+}
+
+func (me *parser) peek() *token {
+	if me.pos >= len(me.tokens) {
+		return nil
+	}
+	return me.tokens[me.pos]
+}
+
+func (me *parser) errorAt(t *token, format string, args ...interface{}) *Error {
+	offset := len(me.lexer.input)
+	if t != nil {
+		offset = t.offset
+	}
+	return me.lexer.errorAt(offset, format, args...)
+}
+
+func (me *parser) condition() (*entityruleengine.Condition, error) {
+	keyToken := me.next()
+	if keyToken == nil || keyToken.quoted || isKeyword(keyToken.text) {
+		return nil, me.errorAt(keyToken, "expected an attribute")
+	}
+	key, err := me.key(keyToken)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &options{}
+	opToken := me.next()
+	if opToken.is(Not) {
+		opts.negate = true
+		opToken = me.next()
+	}
+	if opToken == nil || opToken.quoted {
+		return nil, me.errorAt(opToken, "expected an operator")
+	}
+	opts.operator = strings.ToUpper(opToken.text)
+
+	var valueToken *token
+	if opts.operator != exists {
+		valueToken = me.next()
+		if valueToken == nil || (!valueToken.quoted && isKeyword(valueToken.text)) {
+			return nil, me.errorAt(valueToken, "operator %s requires a value", opts.operator)
+		}
+		opts.value = &valueToken.text
+	}
+
+	var caseToken *token
+	cmpType := TypeOf(key.GetAttribute())
+	for next := me.peek(); next != nil; next = me.peek() {
+		if next.is(CaseSensitive) || next.is(CaseInsensitive) {
+			me.next()
+			caseToken = next
+			sensitive := next.is(CaseSensitive)
+			opts.caseSensitive = &sensitive
+		} else if next.is(As) {
+			me.next()
+			typeToken := me.next()
+			if typeToken == nil || typeToken.quoted {
+				return nil, me.errorAt(typeToken, "expected a comparison type")
+			}
+			cmpType = comparison.ComparisonBasicType(strings.ToUpper(typeToken.text))
+			if _, found := kinds[cmpType]; !found {
+				return nil, me.errorAt(typeToken, "unknown comparison type '%s'", typeToken.text)
+			}
+		} else {
+			break
+		}
+	}
+
+	kind := kinds[cmpType]
+	if operators := enumValues(kind.operators); !contains(operators, opts.operator) {
+		return nil, me.errorAt(opToken, "operator '%s' is not supported for %s (comparison type %s), expected one of %s", opToken.text, key.GetAttribute(), cmpType, strings.Join(operators, ", "))
+	}
+	if caseToken != nil && !kind.caseSensitivity {
+		return nil, me.errorAt(caseToken, "%s is not supported by comparisons of type %s", strings.ToUpper(caseToken.text), cmpType)
+	}
+	if opts.value != nil && kind.values != nil {
+		if values := enumValues(kind.values); !contains(values, *opts.value) {
+			return nil, me.errorAt(valueToken, "unknown value '%s' for comparison type %s, expected one of %s", *opts.value, cmpType, strings.Join(values, ", "))
+		}
+	}
+	cmp, err := kind.build(opts)
+	if err != nil {
+		return nil, me.errorAt(valueToken, "%s", err.Error())
+	}
+	return &entityruleengine.Condition{Key: key, ComparisonInfo: cmp}, nil
+}
+
+// key parses `ATTRIBUTE` or `ATTRIBUTE[dynamic key]`
+func (me *parser) key(t *token) (condition.Key, error) {
+	text := t.text
+	dynamicKey := ""
+	hasDynamicKey := false
+	if idx := strings.Index(text, "["); idx >= 0 {
+		if !strings.HasSuffix(text, "]") || idx == 0 {
+			return nil, me.errorAt(t, "invalid attribute '%s'", text)
+		}
+		dynamicKey = unescape(text[idx+1 : len(text)-1])
+		hasDynamicKey = true
+		text = text[:idx]
+	}
+	attribute := condition.Attribute(strings.ToUpper(text))
+	if !contains(enumValues(condition.Attributes), string(attribute)) {
+		return nil, me.errorAt(t, "unknown attribute '%s'", text)
+	}
+	base := condition.BaseConditionKey{Attribute: attribute}
+	switch attribute {
+	case condition.Attributes.HostCustomMetadata:
+		source, key, err := me.sourceAndKey(t, dynamicKey, hasDynamicKey, enumValues(condition.CustomHostMetadataKeySources))
+		if err != nil {
+			return nil, err
+		}
+		base.Type = condition.ConditionKeyTypes.HostCustomMetadataKey.Ref()
+		return &condition.CustomHostMetadata{BaseConditionKey: base, DynamicKey: &condition.CustomHostMetadataKey{Source: condition.CustomHostMetadataKeySource(source), Key: key}}, nil
+	case condition.Attributes.ProcessGroupCustomMetadata:
+		source, key, err := me.sourceAndKey(t, dynamicKey, hasDynamicKey, enumValues(condition.CustomProcessMetadataKeySources))
+		if err != nil {
+			return nil, err
+		}
+		base.Type = condition.ConditionKeyTypes.ProcessCustomMetadataKey.Ref()
+		return &condition.CustomProcessMetadata{BaseConditionKey: base, DynamicKey: &condition.CustomProcessMetadataKey{Source: condition.CustomProcessMetadataKeySource(source), Key: key}}, nil
+	case condition.Attributes.ProcessGroupPredefinedMetadata:
+		if !hasDynamicKey {
+			return nil, me.errorAt(t, "%s requires a dynamic key, e.g. %s[KUBERNETES_NAMESPACE]", attribute, attribute)
+		}
+		if values := enumValues(condition.DynamicKeys); !contains(values, dynamicKey) {
+			return nil, me.errorAt(t, "unknown dynamic key '%s' for %s, expected one of %s", dynamicKey, attribute, strings.Join(values, ", "))
+		}
+		base.Type = condition.ConditionKeyTypes.ProcessPredefinedMetadataKey.Ref()
+		dk := condition.DynamicKey(dynamicKey)
+		return &condition.ProcessMetadata{BaseConditionKey: base, DynamicKey: &dk}, nil
+	}
+	if hasDynamicKey {
+		base.Type = condition.ConditionKeyTypes.String.Ref()
+		return &condition.String{BaseConditionKey: base, DynamicKey: dynamicKey}, nil
+	}
+	return &base, nil
+}
+
+func (me *parser) sourceAndKey(t *token, dynamicKey string, hasDynamicKey bool, sources []string) (string, string, error) {
+	idx := strings.Index(dynamicKey, ":")
+	if !hasDynamicKey || idx < 0 {
+		return "", "", me.errorAt(t, "custom metadata requires a dynamic key of the form [SOURCE:key]")
+	}
+	source := dynamicKey[:idx]
+	if !contains(sources, source) {
+		return "", "", me.errorAt(t, "unknown metadata source '%s', expected one of %s", source, strings.Join(sources, ", "))
+	}
+	return source, dynamicKey[idx+1:], nil
+}
+
+func isKeyword(s string) bool {
+	for _, keyword := range []string{And, Not, As, CaseSensitive, CaseInsensitive} {
+		if strings.EqualFold(s, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package dsl
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/condition"
+)
+
+// Format emits the DSL for the given conditions, combined with AND.
+// Parsing the result produces conditions equal to the given ones.
+func Format(conditions []*entityruleengine.Condition) (string, error) {
+	parts := []string{}
+	for _, c := range conditions {
+		s, err := FormatCondition(c)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " "+And+" "), nil
+}
+
+// FormatCondition emits the DSL for a single condition
+func FormatCondition(c *entityruleengine.Condition) (string, error) {
+	if c.Key == nil || c.ComparisonInfo == nil {
+		return "", fmt.Errorf("condition lacks a key or a comparison")
+	}
+	parts := []string{formatKey(c.Key)}
+	if c.ComparisonInfo.IsNegated() {
+		parts = append(parts, Not)
+	}
+	operator, value, caseSensitive, err := describe(c.ComparisonInfo)
+	if err != nil {
+		return "", err
+	}
+	parts = append(parts, operator)
+	if value != nil {
+		parts = append(parts, quote(*value))
+	}
+	if caseSensitive != nil {
+		if *caseSensitive {
+			parts = append(parts, CaseSensitive)
+		} else {
+			parts = append(parts, CaseInsensitive)
+		}
+	}
+	if t := c.ComparisonInfo.GetType(); t != TypeOf(c.Key.GetAttribute()) {
+		parts = append(parts, As, string(t))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func formatKey(key condition.Key) string {
+	attribute := string(key.GetAttribute())
+	switch k := key.(type) {
+	case *condition.String:
+		if k.DynamicKey != "" {
+			return attribute + "[" + escape(k.DynamicKey) + "]"
+		}
+	case *condition.ProcessMetadata:
+		if k.DynamicKey != nil {
+			return attribute + "[" + escape(string(*k.DynamicKey)) + "]"
+		}
+	case *condition.CustomHostMetadata:
+		if k.DynamicKey != nil {
+			return attribute + "[" + escape(string(k.DynamicKey.Source)+":"+k.DynamicKey.Key) + "]"
+		}
+	case *condition.CustomProcessMetadata:
+		if k.DynamicKey != nil {
+			return attribute + "[" + escape(string(k.DynamicKey.Source)+":"+k.DynamicKey.Key) + "]"
+		}
+	}
+	return attribute
+}
+
+// describe returns operator, value and case sensitivity of a comparison.
+// The case sensitivity is only reported if the comparison is case sensitive, because that's not the default.
+func describe(cmp comparison.Comparison) (string, *string, *bool, error) {
+	switch c := cmp.(type) {
+	case *comparison.String:
+		var caseSensitive *bool
+		if c.CaseSensitive {
+			caseSensitive = &c.CaseSensitive
+		}
+		return string(c.Operator), c.Value, caseSensitive, nil
+	case *comparison.IPAddress:
+		var caseSensitive *bool
+		if c.CaseSensitive != nil && *c.CaseSensitive {
+			caseSensitive = c.CaseSensitive
+		}
+		return string(c.Operator), c.Value, caseSensitive, nil
+	case *comparison.IndexedName:
+		return string(c.Operator), c.Value, nil, nil
+	case *comparison.IndexedString:
+		return string(c.Operator), c.Value, nil, nil
+	case *comparison.EntityID:
+		return string(c.Operator), c.Value, nil, nil
+	case *comparison.Integer:
+		if c.Value == nil {
+			return string(c.Operator), nil, nil, nil
+		}
+		return string(c.Operator), ref(strconv.Itoa(int(*c.Value))), nil, nil
+	case *comparison.Tag:
+		if c.Value == nil {
+			return string(c.Operator), nil, nil, nil
+		}
+		return string(c.Operator), ref(formatTag(c.Value)), nil, nil
+	case *comparison.IndexedTag:
+		if c.Value == nil {
+			return string(c.Operator), nil, nil, nil
+		}
+		return string(c.Operator), ref(formatTag(c.Value)), nil, nil
+	case *comparison.SimpleTech:
+		if c.Value == nil {
+			return string(c.Operator), nil, nil, nil
+		}
+		if c.Value.VerbatimType != nil {
+			return string(c.Operator), c.Value.VerbatimType, nil, nil
+		}
+		return string(c.Operator), ref(c.Value.Type.String()), nil, nil
+	case *comparison.SimpleHostTech:
+		if c.Value == nil {
+			return string(c.Operator), nil, nil, nil
+		}
+		if c.Value.VerbatimType != nil {
+			return string(c.Operator), c.Value.VerbatimType, nil, nil
+		}
+		return string(c.Operator), ref(c.Value.Type.String()), nil, nil
+	case *comparison.ApplicationType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.AzureComputeMode:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.AzureSku:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.Bitness:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.CloudType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.CustomApplicationType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.DatabaseTopology:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.DCRumDecoder:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.HypervisorType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.MobilePlatform:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.OSArchitecture:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.OSType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.PaasType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.ServiceTopology:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.ServiceType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	case *comparison.SyntheticEngineType:
+		return string(c.Operator), stringOf(c.Value), nil, nil
+	}
+	return "", nil, nil, fmt.Errorf("comparisons of type '%s' are not supported", cmp.GetType())
+}
+
+// stringOf returns the string representation of an optional enum value
+func stringOf(v fmt.Stringer) *string {
+	if v == nil || reflect.ValueOf(v).IsNil() {
+		return nil
+	}
+	return ref(v.String())
+}
+
+func ref(s string) *string {
+	return &s
+}
+
+// quote quotes values which would otherwise not be read back as a single value
+func quote(s string) string {
+	if s == "" || isKeyword(s) {
+		return strconv.Quote(s)
+	}
+	lexer := &lexer{input: s}
+	if tokens, err := lexer.tokens(); err != nil || len(tokens) != 1 || tokens[0].quoted || tokens[0].text != s {
+		return strconv.Quote(s)
+	}
+	return s
+}
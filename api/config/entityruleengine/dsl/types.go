@@ -0,0 +1,369 @@
+package dsl
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/application_type"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/azure_compute_mode"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/azure_sku"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/bitness"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/cloud_type"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/custom_application_type"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/database_topology"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/dcrum_decoder"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/entity_id"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/hypervisor_type"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/indexed_name"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/indexed_string"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/indexed_tag"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/integer"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/ip_address"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/mobile_platform"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/osarch"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/ostype"
+	paastype "github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/paas_type"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/service_topology"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/service_type"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/stringc"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/synthetic_engine_type"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tag"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tech"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/condition"
+)
+
+var types = comparison.ComparisonBasicTypes
+
+// attributeTypes lists the attributes which aren't compared as STRING.
+// Attributes ending with `_TAGS` are compared as TAG.
+var attributeTypes = map[condition.Attribute]comparison.ComparisonBasicType{
+	"AWS_CLASSIC_LOAD_BALANCER_FRONTEND_PORTS": types.Integer,
+	"AWS_RELATIONAL_DATABASE_SERVICE_PORT":     types.Integer,
+	"CUSTOM_DEVICE_PORT":                       types.Integer,
+	"DATA_CENTER_SERVICE_PORT":                 types.Integer,
+	"ENTERPRISE_APPLICATION_PORT":              types.Integer,
+	"HOST_AIX_LOGICAL_CPU_COUNT":               types.Integer,
+	"HOST_AIX_SIMULTANEOUS_THREADS":            types.Integer,
+	"HOST_AIX_VIRTUAL_CPU_COUNT":               types.Integer,
+	"HOST_CPU_CORES":                           types.Integer,
+	"HOST_LOGICAL_CPU_CORES":                   types.Integer,
+	"HOST_PAAS_MEMORY_LIMIT":                   types.Integer,
+	"PROCESS_GROUP_LISTEN_PORT":                types.Integer,
+	"SERVICE_PORT":                             types.Integer,
+
+	"CUSTOM_DEVICE_IP_ADDRESS":                    types.IPAddress,
+	"DATA_CENTER_SERVICE_IP_ADDRESS":              types.IPAddress,
+	"ENTERPRISE_APPLICATION_IP_ADDRESS":           types.IPAddress,
+	"GOOGLE_COMPUTE_INSTANCE_PUBLIC_IP_ADDRESSES": types.IPAddress,
+	"HOST_IP_ADDRESS":                             types.IPAddress,
+
+	"HOST_GROUP_ID":              types.EntityID,
+	"PROCESS_GROUP_ID":           types.EntityID,
+	"SERVICE_WEB_APPLICATION_ID": types.EntityID,
+
+	"CUSTOM_APPLICATION_PLATFORM":         types.MobilePlatform,
+	"CUSTOM_APPLICATION_TYPE":             types.CustomApplicationType,
+	"CUSTOM_DEVICE_TECHNOLOGY":            types.SimpleTech,
+	"DATA_CENTER_SERVICE_DECODER_TYPE":    types.DCRumDecoderType,
+	"ENTERPRISE_APPLICATION_DECODER_TYPE": types.DCRumDecoderType,
+	"EXTERNAL_MONITOR_ENGINE_TYPE":        types.SyntheticEngineType,
+	"HOST_ARCHITECTURE":                   types.OSArchitecture,
+	"HOST_AZURE_COMPUTE_MODE":             types.AzureComputeMode,
+	"HOST_AZURE_SKU":                      types.AzureSku,
+	"HOST_BITNESS":                        types.Bitness,
+	"HOST_CLOUD_TYPE":                     types.CloudType,
+	"HOST_HYPERVISOR_TYPE":                types.HypervisorType,
+	"HOST_OS_TYPE":                        types.OSType,
+	"HOST_PAAS_TYPE":                      types.PaasType,
+	"HOST_TECHNOLOGY":                     types.SimpleHostTech,
+	"MOBILE_APPLICATION_PLATFORM":         types.MobilePlatform,
+	"PROCESS_GROUP_TECHNOLOGY":            types.SimpleTech,
+	"SERVICE_DATABASE_TOPOLOGY":           types.DatabaseTopology,
+	"SERVICE_TECHNOLOGY":                  types.SimpleTech,
+	"SERVICE_TOPOLOGY":                    types.ServiceTopology,
+	"SERVICE_TYPE":                        types.ServiceType,
+	"WEB_APPLICATION_TYPE":                types.ApplicationType,
+}
+
+// TypeOf returns the type of comparison the DSL uses for the given attribute unless specified explicitly with `AS <type>`
+func TypeOf(attribute condition.Attribute) comparison.ComparisonBasicType {
+	if t, found := attributeTypes[attribute]; found {
+		return t
+	}
+	if strings.HasSuffix(string(attribute), "_TAGS") {
+		return types.Tag
+	}
+	return types.String
+}
+
+// options are the parts of a condition a comparison gets built from
+type options struct {
+	operator      string
+	value         *string
+	negate        bool
+	caseSensitive *bool
+}
+
+// kind describes how the DSL deals with one type of comparison
+type kind struct {
+	operators       interface{} // the Operators of the comparison type
+	values          interface{} // the Values of enum comparisons
+	caseSensitivity bool        // whether CASE_SENSITIVE and CASE_INSENSITIVE are supported
+	build           func(opts *options) (comparison.Comparison, error)
+}
+
+var kinds = map[comparison.ComparisonBasicType]*kind{
+	types.String: {operators: stringc.Operators, caseSensitivity: true, build: func(opts *options) (comparison.Comparison, error) {
+		return &comparison.String{BaseComparison: base(types.String, opts), Operator: stringc.Operator(opts.operator), Value: opts.value, CaseSensitive: opts.caseSensitive != nil && *opts.caseSensitive}, nil
+	}},
+	types.IPAddress: {operators: ip_address.Operators, caseSensitivity: true, build: func(opts *options) (comparison.Comparison, error) {
+		return &comparison.IPAddress{BaseComparison: base(types.IPAddress, opts), Operator: ip_address.Operator(opts.operator), Value: opts.value, CaseSensitive: opts.caseSensitive}, nil
+	}},
+	types.IndexedName: {operators: indexed_name.Operators, build: func(opts *options) (comparison.Comparison, error) {
+		return &comparison.IndexedName{BaseComparison: base(types.IndexedName, opts), Operator: indexed_name.Operator(opts.operator), Value: opts.value}, nil
+	}},
+	types.IndexedString: {operators: indexed_string.Operators, build: func(opts *options) (comparison.Comparison, error) {
+		return &comparison.IndexedString{BaseComparison: base(types.IndexedString, opts), Operator: indexed_string.Operator(opts.operator), Value: opts.value}, nil
+	}},
+	types.EntityID: {operators: entity_id.Operators, build: func(opts *options) (comparison.Comparison, error) {
+		return &comparison.EntityID{BaseComparison: base(types.EntityID, opts), Operator: entity_id.Operator(opts.operator), Value: opts.value}, nil
+	}},
+	types.Integer: {operators: integer.Operators, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.Integer{BaseComparison: base(types.Integer, opts), Operator: integer.Operator(opts.operator)}
+		if opts.value != nil {
+			n, err := strconv.ParseInt(*opts.value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("'%s' is not a valid integer", *opts.value)
+			}
+			v := int32(n)
+			cmp.Value = &v
+		}
+		return cmp, nil
+	}},
+	types.Tag: {operators: tag.Operators, build: func(opts *options) (comparison.Comparison, error) {
+		info, err := parseTag(opts.value)
+		if err != nil {
+			return nil, err
+		}
+		return &comparison.Tag{BaseComparison: base(types.Tag, opts), Operator: tag.Operator(opts.operator), Value: info}, nil
+	}},
+	types.IndexedTag: {operators: indexed_tag.Operators, build: func(opts *options) (comparison.Comparison, error) {
+		info, err := parseTag(opts.value)
+		if err != nil {
+			return nil, err
+		}
+		return &comparison.IndexedTag{BaseComparison: base(types.IndexedTag, opts), Operator: indexed_tag.Operator(opts.operator), Value: info}, nil
+	}},
+	types.SimpleTech: {operators: tech.Operators, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.SimpleTech{BaseComparison: base(types.SimpleTech, opts), Operator: tech.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = &tech.Simple{}
+			if contains(enumValues(tech.SimpleTechTypes), *opts.value) {
+				cmp.Value.Type = tech.SimpleTechType(*opts.value).Ref()
+			} else {
+				cmp.Value.VerbatimType = opts.value
+			}
+		}
+		return cmp, nil
+	}},
+	types.SimpleHostTech: {operators: tech.HostOperators, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.SimpleHostTech{BaseComparison: base(types.SimpleHostTech, opts), Operator: tech.HostOperator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = &tech.Host{}
+			if contains(enumValues(tech.SimpleHostTechTypes), *opts.value) {
+				cmp.Value.Type = tech.SimpleHostTechType(*opts.value).Ref()
+			} else {
+				cmp.Value.VerbatimType = opts.value
+			}
+		}
+		return cmp, nil
+	}},
+	types.ApplicationType: {operators: application_type.Operators, values: application_type.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.ApplicationType{BaseComparison: base(types.ApplicationType, opts), Operator: application_type.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = application_type.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.AzureComputeMode: {operators: azure_compute_mode.Operators, values: azure_compute_mode.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.AzureComputeMode{BaseComparison: base(types.AzureComputeMode, opts), Operator: azure_compute_mode.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = azure_compute_mode.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.AzureSku: {operators: azure_sku.Operators, values: azure_sku.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.AzureSku{BaseComparison: base(types.AzureSku, opts), Operator: azure_sku.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = azure_sku.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.Bitness: {operators: bitness.Operators, values: bitness.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.Bitness{BaseComparison: base(types.Bitness, opts), Operator: bitness.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = bitness.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.CloudType: {operators: cloud_type.Operators, values: cloud_type.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.CloudType{BaseComparison: base(types.CloudType, opts), Operator: cloud_type.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = cloud_type.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.CustomApplicationType: {operators: custom_application_type.Operators, values: custom_application_type.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.CustomApplicationType{BaseComparison: base(types.CustomApplicationType, opts), Operator: custom_application_type.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = custom_application_type.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.DatabaseTopology: {operators: database_topology.Operators, values: database_topology.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.DatabaseTopology{BaseComparison: base(types.DatabaseTopology, opts), Operator: database_topology.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = database_topology.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.DCRumDecoderType: {operators: dcrum_decoder.Operators, values: dcrum_decoder.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.DCRumDecoder{BaseComparison: base(types.DCRumDecoderType, opts), Operator: dcrum_decoder.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = dcrum_decoder.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.HypervisorType: {operators: hypervisor_type.Operators, values: hypervisor_type.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.HypervisorType{BaseComparison: base(types.HypervisorType, opts), Operator: hypervisor_type.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = hypervisor_type.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.MobilePlatform: {operators: mobile_platform.Operators, values: mobile_platform.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.MobilePlatform{BaseComparison: base(types.MobilePlatform, opts), Operator: mobile_platform.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = mobile_platform.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.OSArchitecture: {operators: osarch.Operators, values: osarch.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.OSArchitecture{BaseComparison: base(types.OSArchitecture, opts), Operator: osarch.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = osarch.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.OSType: {operators: ostype.Operators, values: ostype.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.OSType{BaseComparison: base(types.OSType, opts), Operator: ostype.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = ostype.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.PaasType: {operators: paastype.Operators, values: paastype.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.PaasType{BaseComparison: base(types.PaasType, opts), Operator: paastype.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = paastype.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.ServiceTopology: {operators: service_topology.Operators, values: service_topology.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.ServiceTopology{BaseComparison: base(types.ServiceTopology, opts), Operator: service_topology.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = service_topology.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.ServiceType: {operators: service_type.Operators, values: service_type.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.ServiceType{BaseComparison: base(types.ServiceType, opts), Operator: service_type.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = service_type.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+	types.SyntheticEngineType: {operators: synthetic_engine_type.Operators, values: synthetic_engine_type.Values, build: func(opts *options) (comparison.Comparison, error) {
+		cmp := &comparison.SyntheticEngineType{BaseComparison: base(types.SyntheticEngineType, opts), Operator: synthetic_engine_type.Operator(opts.operator)}
+		if opts.value != nil {
+			cmp.Value = synthetic_engine_type.Value(*opts.value).Ref()
+		}
+		return cmp, nil
+	}},
+}
+
+func base(t comparison.ComparisonBasicType, opts *options) comparison.BaseComparison {
+	return comparison.BaseComparison{Type: t, Negate: opts.negate}
+}
+
+// parseTag parses tags like `key`, `key:value` or `[CONTEXT]key:value`
+func parseTag(s *string) (*tag.Info, error) {
+	if s == nil {
+		return nil, nil
+	}
+	text := *s
+	info := &tag.Info{Context: tag.Contexts.Contextless}
+	if strings.HasPrefix(text, "[") {
+		idx := strings.Index(text, "]")
+		if idx < 0 {
+			return nil, fmt.Errorf("missing ']' in tag '%s'", text)
+		}
+		info.Context = tag.Context(text[1:idx])
+		if !contains(enumValues(tag.Contexts), string(info.Context)) {
+			return nil, fmt.Errorf("unknown tag context '%s', expected one of %s", info.Context, strings.Join(enumValues(tag.Contexts), ", "))
+		}
+		text = text[idx+1:]
+	}
+	if idx := strings.Index(text, ":"); idx >= 0 {
+		value := text[idx+1:]
+		info.Value = &value
+		text = text[:idx]
+	}
+	if text == "" {
+		return nil, fmt.Errorf("tag '%s' lacks a key", *s)
+	}
+	info.Key = text
+	return info, nil
+}
+
+// formatTag is the inverse of parseTag
+func formatTag(info *tag.Info) string {
+	if info == nil {
+		return ""
+	}
+	s := info.Key
+	if info.Context != "" && info.Context != tag.Contexts.Contextless {
+		s = "[" + string(info.Context) + "]" + s
+	}
+	if info.Value != nil {
+		s = s + ":" + *info.Value
+	}
+	return s
+}
+
+// enumValues returns the values of the given `Operators` or `Values` struct
+func enumValues(enum interface{}) []string {
+	values := []string{}
+	if enum == nil {
+		return values
+	}
+	v := reflect.ValueOf(enum)
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Field(i); field.Kind() == reflect.String {
+			values = append(values, field.String())
+		}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,326 @@
+package evaluator
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/integer"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/ip_address"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/stringc"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tag"
+	"github.com/dtcookie/opt"
+)
+
+var (
+	opEquals       = string(stringc.Operators.Equals)
+	opExists       = string(stringc.Operators.Exists)
+	opBeginsWith   = string(stringc.Operators.BeginsWith)
+	opContains     = string(stringc.Operators.Contains)
+	opEndsWith     = string(stringc.Operators.EndsWith)
+	opRegexMatches = string(stringc.Operators.RegexMatches)
+)
+
+// Compare evaluates a comparison against the values of an attribute, or against the given tags for tag comparisons.
+// The result already respects the negate flag of the comparison.
+func Compare(cmp comparison.Comparison, values []string, tags []*tag.Info) (bool, error) {
+	var matches bool
+	var err error
+	switch c := cmp.(type) {
+	case *comparison.String:
+		matches, err = compareStrings(string(c.Operator), opt.String(c.Value), c.CaseSensitive, values)
+	case *comparison.IPAddress:
+		if c.Operator == ip_address.Operators.IsIPInRange {
+			matches, err = compareIPRange(opt.String(c.Value), values)
+		} else {
+			matches, err = compareStrings(string(c.Operator), opt.String(c.Value), c.CaseSensitive == nil || *c.CaseSensitive, values)
+		}
+	case *comparison.IndexedName:
+		matches, err = compareStrings(string(c.Operator), opt.String(c.Value), false, values)
+	case *comparison.IndexedString:
+		matches, err = compareStrings(string(c.Operator), opt.String(c.Value), true, values)
+	case *comparison.EntityID:
+		matches, err = compareStrings(string(c.Operator), opt.String(c.Value), true, values)
+	case *comparison.Integer:
+		matches, err = compareIntegers(c.Operator, c.Value, values)
+	case *comparison.Tag:
+		matches, err = compareTags(string(c.Operator), c.Value, tags)
+	case *comparison.IndexedTag:
+		matches, err = compareTags(string(c.Operator), c.Value, tags)
+	case *comparison.SimpleTech:
+		value := ""
+		if c.Value != nil {
+			value = techName(c.Value.Type, c.Value.VerbatimType)
+		}
+		matches, err = compareStrings(string(c.Operator), value, false, values)
+	case *comparison.SimpleHostTech:
+		value := ""
+		if c.Value != nil {
+			value = techName(c.Value.Type, c.Value.VerbatimType)
+		}
+		matches, err = compareStrings(string(c.Operator), value, false, values)
+	case *comparison.ApplicationType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.AzureComputeMode:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.AzureSku:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.Bitness:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.CloudType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.CustomApplicationType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.DatabaseTopology:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.DCRumDecoder:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.HypervisorType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.MobilePlatform:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.OSArchitecture:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.OSType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.PaasType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.ServiceTopology:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.ServiceType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	case *comparison.SyntheticEngineType:
+		matches, err = compareStrings(string(c.Operator), str(c.Value), true, values)
+	default:
+		return false, fmt.Errorf("comparisons of type '%s' are not supported", cmp.GetType())
+	}
+	if err != nil {
+		return false, err
+	}
+	return matches != cmp.IsNegated(), nil
+}
+
+// str returns the string representation of an optional enum value
+func str(v fmt.Stringer) string {
+	if v == nil || reflect.ValueOf(v).IsNil() {
+		return ""
+	}
+	return v.String()
+}
+
+func techName(predefined fmt.Stringer, verbatim *string) string {
+	if verbatim != nil {
+		return *verbatim
+	}
+	return str(predefined)
+}
+
+// Operator returns the operator of a comparison
+func Operator(cmp comparison.Comparison) string {
+	v := reflect.ValueOf(cmp)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if field := v.Elem().FieldByName("Operator"); field.IsValid() && field.Kind() == reflect.String {
+			return field.String()
+		}
+	}
+	return ""
+}
+
+// Value returns the string representation of the value of a comparison
+func Value(cmp comparison.Comparison) string {
+	switch c := cmp.(type) {
+	case *comparison.String:
+		return opt.String(c.Value)
+	case *comparison.IPAddress:
+		return opt.String(c.Value)
+	case *comparison.IndexedName:
+		return opt.String(c.Value)
+	case *comparison.IndexedString:
+		return opt.String(c.Value)
+	case *comparison.EntityID:
+		return opt.String(c.Value)
+	case *comparison.Integer:
+		if c.Value == nil {
+			return ""
+		}
+		return strconv.Itoa(int(*c.Value))
+	case *comparison.Tag:
+		return TagString(c.Value)
+	case *comparison.IndexedTag:
+		return TagString(c.Value)
+	case *comparison.SimpleTech:
+		if c.Value == nil {
+			return ""
+		}
+		return techName(c.Value.Type, c.Value.VerbatimType)
+	case *comparison.SimpleHostTech:
+		if c.Value == nil {
+			return ""
+		}
+		return techName(c.Value.Type, c.Value.VerbatimType)
+	}
+	v := reflect.ValueOf(cmp)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if field := v.Elem().FieldByName("Value"); field.IsValid() && field.CanInterface() {
+			if stringer, ok := field.Interface().(fmt.Stringer); ok {
+				return str(stringer)
+			}
+		}
+	}
+	return ""
+}
+
+// TagString formats a tag the way the Dynatrace UI shows it, e.g. `[AWS]key:value`
+func TagString(info *tag.Info) string {
+	if info == nil {
+		return ""
+	}
+	s := info.Key
+	if info.Context != "" && info.Context != tag.Contexts.Contextless {
+		s = "[" + string(info.Context) + "]" + s
+	}
+	if info.Value != nil {
+		s = s + ":" + *info.Value
+	}
+	return s
+}
+
+func compareStrings(operator string, value string, caseSensitive bool, values []string) (bool, error) {
+	if operator == opExists {
+		return len(values) > 0, nil
+	}
+	var re *regexp.Regexp
+	if operator == opRegexMatches {
+		expr := value
+		if !caseSensitive {
+			expr = "(?i)" + expr
+		}
+		var err error
+		if re, err = regexp.Compile(expr); err != nil {
+			return false, fmt.Errorf("invalid regular expression '%s': %s", value, err.Error())
+		}
+	}
+	if !caseSensitive {
+		value = strings.ToLower(value)
+	}
+	for _, v := range values {
+		if !caseSensitive {
+			v = strings.ToLower(v)
+		}
+		var matches bool
+		switch operator {
+		case opEquals:
+			matches = v == value
+		case opBeginsWith:
+			matches = strings.HasPrefix(v, value)
+		case opContains:
+			matches = strings.Contains(v, value)
+		case opEndsWith:
+			matches = strings.HasSuffix(v, value)
+		case opRegexMatches:
+			matches = re.MatchString(v)
+		default:
+			return false, fmt.Errorf("unsupported operator '%s'", operator)
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func compareIntegers(operator integer.Operator, value *int32, values []string) (bool, error) {
+	if operator == integer.Operators.Exists {
+		return len(values) > 0, nil
+	}
+	if value == nil {
+		return false, fmt.Errorf("operator '%s' requires a value", operator)
+	}
+	for _, v := range values {
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			continue
+		}
+		var matches bool
+		switch operator {
+		case integer.Operators.Equals:
+			matches = n == int64(*value)
+		case integer.Operators.GreaterThan:
+			matches = n > int64(*value)
+		case integer.Operators.GreaterThanOrEqual:
+			matches = n >= int64(*value)
+		case integer.Operators.LowerThan:
+			matches = n < int64(*value)
+		case integer.Operators.LowerThanOrEqual:
+			matches = n <= int64(*value)
+		default:
+			return false, fmt.Errorf("unsupported operator '%s'", operator)
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// compareIPRange supports ranges in CIDR notation as well as `<from>-<to>`
+func compareIPRange(value string, values []string) (bool, error) {
+	var contains func(ip net.IP) bool
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		contains = network.Contains
+	} else if parts := strings.SplitN(value, "-", 2); len(parts) == 2 {
+		from := net.ParseIP(strings.TrimSpace(parts[0]))
+		to := net.ParseIP(strings.TrimSpace(parts[1]))
+		if from == nil || to == nil {
+			return false, fmt.Errorf("invalid IP address range '%s'", value)
+		}
+		contains = func(ip net.IP) bool {
+			return bytes.Compare(ip.To16(), from.To16()) >= 0 && bytes.Compare(ip.To16(), to.To16()) <= 0
+		}
+	} else {
+		return false, fmt.Errorf("invalid IP address range '%s'", value)
+	}
+	for _, v := range values {
+		if ip := net.ParseIP(strings.TrimSpace(v)); ip != nil && contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func compareTags(operator string, value *tag.Info, tags []*tag.Info) (bool, error) {
+	if operator == opExists {
+		return len(tags) > 0, nil
+	}
+	if value == nil {
+		return false, fmt.Errorf("operator '%s' requires a tag", operator)
+	}
+	for _, t := range tags {
+		if contextOf(t) != contextOf(value) || t.Key != value.Key {
+			continue
+		}
+		switch operator {
+		case string(tag.Operators.TagKeyEquals):
+			return true, nil
+		case opEquals:
+			if opt.String(t.Value) == opt.String(value.Value) && (t.Value == nil) == (value.Value == nil) {
+				return true, nil
+			}
+		default:
+			return false, fmt.Errorf("unsupported operator '%s'", operator)
+		}
+	}
+	return false, nil
+}
+
+func contextOf(info *tag.Info) tag.Context {
+	if info.Context == "" {
+		return tag.Contexts.Contextless
+	}
+	return info.Context
+}
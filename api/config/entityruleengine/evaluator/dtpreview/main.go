@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	hostnaming "github.com/dtcookie/dynatrace/api/config/naming/hosts"
+	processgroupnaming "github.com/dtcookie/dynatrace/api/config/naming/processgroups"
+	servicenaming "github.com/dtcookie/dynatrace/api/config/naming/services"
+)
+
+func main() {
+	var environmentURL, apiToken string
+	var snapshotFile, saveSnapshot string
+	var verbose bool
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&environmentURL, "environment-url", os.Getenv("DT_ENVIRONMENT_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.StringVar(&snapshotFile, "snapshot", "", "")
+	flagSet.StringVar(&saveSnapshot, "save-snapshot", "", "")
+	flagSet.BoolVar(&verbose, "verbose", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if flagSet.NArg() != 2 || (snapshotFile == "" && (environmentURL == "" || apiToken == "")) {
+		usage()
+		os.Exit(2)
+	}
+
+	rules, err := readRules(flagSet.Arg(0), flagSet.Arg(1))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	var entities []*evaluator.Entity
+	if snapshotFile != "" {
+		entities, err = evaluator.ReadSnapshot(snapshotFile)
+	} else {
+		entities, err = evaluator.Snapshot(strings.TrimSuffix(environmentURL, "/")+"/api/v1", apiToken)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if saveSnapshot != "" {
+		if err := evaluator.WriteSnapshot(saveSnapshot, entities); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	matches := evaluator.Evaluate(rules, entities)
+	if !verbose {
+		matches = evaluator.Matching(matches)
+	}
+	for _, match := range matches {
+		if len(match.Rules) > 0 {
+			fmt.Println(match.String())
+		}
+	}
+	fmt.Println()
+	fmt.Printf("%d of %d entities match\n", len(evaluator.Matching(matches)), len(entities))
+}
+
+func readRules(kind string, file string) ([]*evaluator.Rule, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "managementzone":
+		var mz managementzones.ManagementZone
+		if err := json.Unmarshal(data, &mz); err != nil {
+			return nil, err
+		}
+		return evaluator.ManagementZoneRules(&mz), nil
+	case "autotag":
+		var autoTag autotags.AutoTag
+		if err := json.Unmarshal(data, &autoTag); err != nil {
+			return nil, err
+		}
+		return evaluator.AutoTagRules(&autoTag), nil
+	case "hostnaming":
+		var rule hostnaming.NamingRule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			return nil, err
+		}
+		return []*evaluator.Rule{evaluator.HostNamingRule(&rule)}, nil
+	case "processgroupnaming":
+		var rule processgroupnaming.NamingRule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			return nil, err
+		}
+		return []*evaluator.Rule{evaluator.ProcessGroupNamingRule(&rule)}, nil
+	case "servicenaming":
+		var rule servicenaming.NamingRule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			return nil, err
+		}
+		return []*evaluator.Rule{evaluator.ServiceNamingRule(&rule)}, nil
+	}
+	return nil, fmt.Errorf("unknown kind '%s'", kind)
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtpreview [-environment-url <environment-url>] [-api-token <api-token>] [-snapshot <file>] [-save-snapshot <file>] [-verbose] <kind> <config-file>")
+	fmt.Println("  Previews which entities the rules of a configuration match.")
+	fmt.Println("  <kind>  one of managementzone, autotag, hostnaming, processgroupnaming, servicenaming")
+	fmt.Println("  -snapshot       evaluates against the entities stored in <file> instead of fetching them")
+	fmt.Println("  -save-snapshot  stores the entities evaluated against in <file>")
+	fmt.Println("  -verbose        also explains why entities don't match")
+	fmt.Println("  Hint: you can also define the environment variables DT_ENVIRONMENT_URL and DT_API_TOKEN")
+}
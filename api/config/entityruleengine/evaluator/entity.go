@@ -0,0 +1,97 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tag"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/condition"
+)
+
+// EntityTypes offers the entity types Snapshot produces
+var EntityTypes = struct {
+	Application          string
+	Host                 string
+	ProcessGroup         string
+	ProcessGroupInstance string
+	Service              string
+}{
+	"WEB_APPLICATION",
+	"HOST",
+	"PROCESS_GROUP",
+	"PROCESS_GROUP_INSTANCE",
+	"SERVICE",
+}
+
+// Entity is the snapshot of a monitored entity conditions get evaluated against
+type Entity struct {
+	ID   string      `json:"entityId"`
+	Type string      `json:"type"` // matched against the type of a rule, e.g. `HOST` or `SERVICE`
+	Name string      `json:"displayName"`
+	Tags []*tag.Info `json:"tags,omitempty"`
+	// Attributes holds the values of any further attributes, keyed by the name AttributeKey returns.
+	// Attributes like `HOST_IP_ADDRESS` may have several values, a condition matches if any of them does.
+	Attributes map[string][]string `json:"attributes,omitempty"`
+	// RelatedTags holds the tags of related entities, keyed by attribute, e.g. `HOST_TAGS` for the tags of the host a service runs on
+	RelatedTags map[string][]*tag.Info `json:"relatedTags,omitempty"`
+	// Relationships holds the IDs of the entities this entity relates to, keyed by relationship, e.g. `runsOn` or `isProcessOf`.
+	// These are the fromRelationships reported by the Topology API.
+	Relationships map[string][]string `json:"relationships,omitempty"`
+}
+
+// AttributeKey returns the name the values for the given condition key are expected under within Entity.Attributes.
+// This is the attribute itself, for keys with a dynamic key it's `<attribute>/<dynamic key>`
+// and for custom metadata `<attribute>/<source>/<key>`.
+func AttributeKey(key condition.Key) string {
+	attribute := string(key.GetAttribute())
+	switch k := key.(type) {
+	case *condition.String:
+		if k.DynamicKey != "" {
+			return attribute + "/" + k.DynamicKey
+		}
+	case *condition.ProcessMetadata:
+		if k.DynamicKey != nil {
+			return attribute + "/" + string(*k.DynamicKey)
+		}
+	case *condition.CustomHostMetadata:
+		if k.DynamicKey != nil {
+			return attribute + "/" + string(k.DynamicKey.Source) + "/" + k.DynamicKey.Key
+		}
+	case *condition.CustomProcessMetadata:
+		if k.DynamicKey != nil {
+			return attribute + "/" + string(k.DynamicKey.Source) + "/" + k.DynamicKey.Key
+		}
+	}
+	return attribute
+}
+
+// Values returns the values of the given attribute.
+// Unless specified explicitly within Attributes, `<type>_NAME` resolves to the name and `<type>_ID` to the ID of the entity.
+func (me *Entity) Values(attribute string) []string {
+	if values, found := me.Attributes[attribute]; found {
+		return values
+	}
+	if me.Type != "" {
+		switch attribute {
+		case me.Type + "_NAME":
+			return []string{me.Name}
+		case me.Type + "_ID":
+			return []string{me.ID}
+		}
+	}
+	return []string{}
+}
+
+// TagsOf returns the tags conditions on the given attribute get evaluated against.
+// For `<type>_TAGS` these are the tags of the entity itself, otherwise the according RelatedTags.
+func (me *Entity) TagsOf(attribute string) []*tag.Info {
+	if me.Type != "" && attribute == me.Type+"_TAGS" {
+		return me.Tags
+	}
+	if tags, found := me.RelatedTags[attribute]; found {
+		return tags
+	}
+	if !strings.HasSuffix(attribute, "_TAGS") {
+		return me.Tags
+	}
+	return []*tag.Info{}
+}
@@ -0,0 +1,152 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+)
+
+// Rule is a list of conditions which all need to match for an entity of the given type
+type Rule struct {
+	Name       string `json:"name"`
+	Type       string `json:"type,omitempty"` // the entity type the rule applies to, empty for any type
+	Enabled    bool   `json:"enabled"`
+	Conditions []*entityruleengine.Condition
+}
+
+// Condition is the outcome of evaluating a single condition against an entity
+type Condition struct {
+	Condition *entityruleengine.Condition
+	Attribute string   // the attribute the condition got evaluated against
+	Values    []string // the values of the attribute, or the tags for tag comparisons
+	Matches   bool
+	Error     error // set if the condition couldn't get evaluated, the condition is then treated as not matching
+}
+
+func (me *Condition) String() string {
+	operator := ""
+	negated := false
+	if me.Condition.ComparisonInfo != nil {
+		operator = Operator(me.Condition.ComparisonInfo)
+		negated = me.Condition.ComparisonInfo.IsNegated()
+	}
+	if negated {
+		operator = "NOT " + operator
+	}
+	s := fmt.Sprintf("%s %s", me.Attribute, operator)
+	if value := Value(me.Condition.ComparisonInfo); value != "" {
+		s = s + fmt.Sprintf(" '%s'", value)
+	}
+	values := make([]string, len(me.Values))
+	for idx, v := range me.Values {
+		values[idx] = "'" + v + "'"
+	}
+	s = s + fmt.Sprintf(" [%s]", strings.Join(values, ", "))
+	if me.Error != nil {
+		return s + " -> " + me.Error.Error()
+	}
+	return fmt.Sprintf("%s -> %v", s, me.Matches)
+}
+
+// RuleResult is the outcome of evaluating a rule against an entity
+type RuleResult struct {
+	Rule       *Rule
+	Matches    bool
+	Conditions []*Condition
+}
+
+// Match is the outcome of evaluating a set of rules against an entity.
+// Only rules which apply to the type of the entity are getting evaluated.
+type Match struct {
+	Entity  *Entity
+	Matches bool // at least one enabled rule matches
+	Rules   []*RuleResult
+}
+
+func (me *Match) String() string {
+	lines := []string{}
+	mark := "-"
+	if me.Matches {
+		mark = "+"
+	}
+	lines = append(lines, fmt.Sprintf("%s %s %s (%s)", mark, me.Entity.Type, me.Entity.Name, me.Entity.ID))
+	for _, rule := range me.Rules {
+		state := "no match"
+		if !rule.Rule.Enabled {
+			state = "disabled"
+		} else if rule.Matches {
+			state = "match"
+		}
+		lines = append(lines, fmt.Sprintf("    %s: %s", rule.Rule.Name, state))
+		for _, condition := range rule.Conditions {
+			lines = append(lines, "      "+condition.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EvaluateCondition evaluates a single condition against an entity
+func EvaluateCondition(condition *entityruleengine.Condition, entity *Entity) *Condition {
+	result := &Condition{Condition: condition, Values: []string{}}
+	if condition.Key == nil || condition.ComparisonInfo == nil {
+		result.Error = fmt.Errorf("condition lacks a key or a comparison")
+		return result
+	}
+	result.Attribute = AttributeKey(condition.Key)
+	switch condition.ComparisonInfo.(type) {
+	case *comparison.Tag, *comparison.IndexedTag:
+		tags := entity.TagsOf(result.Attribute)
+		for _, t := range tags {
+			result.Values = append(result.Values, TagString(t))
+		}
+		result.Matches, result.Error = Compare(condition.ComparisonInfo, nil, tags)
+	default:
+		result.Values = entity.Values(result.Attribute)
+		result.Matches, result.Error = Compare(condition.ComparisonInfo, result.Values, nil)
+	}
+	return result
+}
+
+// EvaluateRule evaluates all conditions of a rule against an entity.
+// Conditions are all evaluated, even after one of them didn't match, in order to explain the outcome.
+func EvaluateRule(rule *Rule, entity *Entity) *RuleResult {
+	result := &RuleResult{Rule: rule, Matches: rule.Enabled, Conditions: []*Condition{}}
+	for _, condition := range rule.Conditions {
+		c := EvaluateCondition(condition, entity)
+		result.Conditions = append(result.Conditions, c)
+		result.Matches = result.Matches && c.Matches
+	}
+	return result
+}
+
+// Evaluate evaluates the given rules against every entity.
+// An entity matches if any of the enabled rules applying to its type matches.
+func Evaluate(rules []*Rule, entities []*Entity) []*Match {
+	matches := []*Match{}
+	for _, entity := range entities {
+		match := &Match{Entity: entity, Rules: []*RuleResult{}}
+		for _, rule := range rules {
+			if rule.Type != "" && rule.Type != entity.Type {
+				continue
+			}
+			result := EvaluateRule(rule, entity)
+			match.Rules = append(match.Rules, result)
+			match.Matches = match.Matches || result.Matches
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// Matching filters the given results down to the entities which matched
+func Matching(matches []*Match) []*Match {
+	result := []*Match{}
+	for _, match := range matches {
+		if match.Matches {
+			result = append(result, match)
+		}
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package evaluator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tag"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator"
+)
+
+const conditions = `[
+	{
+		"key": { "attribute": "HOST_NAME" },
+		"comparisonInfo": { "type": "STRING", "operator": "BEGINS_WITH", "value": "WEB-", "negate": false, "caseSensitive": false }
+	},
+	{
+		"key": { "attribute": "HOST_IP_ADDRESS" },
+		"comparisonInfo": { "type": "IP_ADDRESS", "operator": "IS_IP_IN_RANGE", "value": "10.0.0.0/16", "negate": false }
+	},
+	{
+		"key": { "attribute": "HOST_TAGS" },
+		"comparisonInfo": { "type": "TAG", "operator": "TAG_KEY_EQUALS", "value": { "context": "CONTEXTLESS", "key": "legacy" }, "negate": true }
+	}
+]`
+
+func TestEvaluate(t *testing.T) {
+	var conds []*entityruleengine.Condition
+	if err := json.Unmarshal([]byte(conditions), &conds); err != nil {
+		t.Fatal(err)
+	}
+	rules := []*evaluator.Rule{{Name: "web hosts", Type: evaluator.EntityTypes.Host, Enabled: true, Conditions: conds}}
+
+	legacy := "true"
+	entities := []*evaluator.Entity{
+		{ID: "HOST-1", Type: "HOST", Name: "web-01", Attributes: map[string][]string{"HOST_IP_ADDRESS": {"192.168.0.1", "10.0.3.4"}}},
+		{ID: "HOST-2", Type: "HOST", Name: "web-02", Attributes: map[string][]string{"HOST_IP_ADDRESS": {"10.0.3.5"}}, Tags: []*tag.Info{{Context: "CONTEXTLESS", Key: "legacy", Value: &legacy}}},
+		{ID: "HOST-3", Type: "HOST", Name: "db-01", Attributes: map[string][]string{"HOST_IP_ADDRESS": {"10.0.3.6"}}},
+		{ID: "HOST-4", Type: "HOST", Name: "web-03", Attributes: map[string][]string{"HOST_IP_ADDRESS": {"10.1.0.1"}}},
+		{ID: "SERVICE-1", Type: "SERVICE", Name: "web-frontend"},
+	}
+
+	matches := evaluator.Evaluate(rules, entities)
+	matching := evaluator.Matching(matches)
+	if len(matching) != 1 || matching[0].Entity.ID != "HOST-1" {
+		for _, match := range matches {
+			t.Log(match.String())
+		}
+		t.Fatalf("expected only HOST-1 to match, got %d matches", len(matching))
+	}
+	if len(matches[4].Rules) != 0 {
+		t.Error("expected host rules not to get evaluated against services")
+	}
+	for _, condition := range matches[1].Rules[0].Conditions {
+		if condition.Error != nil {
+			t.Errorf("unexpected error: %s", condition.Error.Error())
+		}
+	}
+}
+
+func comparisonOf(t *testing.T, s string) comparison.Comparison {
+	var cond entityruleengine.Condition
+	if err := json.Unmarshal([]byte(`{"key":{"attribute":"HOST_NAME"},"comparisonInfo":`+s+`}`), &cond); err != nil {
+		t.Fatal(err)
+	}
+	return cond.ComparisonInfo
+}
+
+func TestCompare(t *testing.T) {
+	prod := "prod"
+	tags := []*tag.Info{
+		{Context: "CONTEXTLESS", Key: "env", Value: &prod},
+		{Context: "CONTEXTLESS", Key: "legacy"},
+		{Context: "AWS", Key: "app", Value: &prod},
+	}
+
+	tests := []struct {
+		name       string
+		comparison string
+		values     []string
+		tags       []*tag.Info
+		expected   bool
+		err        bool
+	}{
+		// STRING
+		{"string equals ignoring case", `{"type":"STRING","operator":"EQUALS","value":"Web-01","negate":false,"caseSensitive":false}`, []string{"web-01"}, nil, true, false},
+		{"string equals case sensitive", `{"type":"STRING","operator":"EQUALS","value":"Web-01","negate":false,"caseSensitive":true}`, []string{"web-01"}, nil, false, false},
+		{"string begins with", `{"type":"STRING","operator":"BEGINS_WITH","value":"web","negate":false,"caseSensitive":true}`, []string{"db-01", "web-01"}, nil, true, false},
+		{"string contains", `{"type":"STRING","operator":"CONTAINS","value":"eb-0","negate":false,"caseSensitive":true}`, []string{"web-01"}, nil, true, false},
+		{"string ends with", `{"type":"STRING","operator":"ENDS_WITH","value":"-02","negate":false,"caseSensitive":true}`, []string{"web-01"}, nil, false, false},
+		{"string regex matches ignoring case", `{"type":"STRING","operator":"REGEX_MATCHES","value":"^WEB-\\d+$","negate":false,"caseSensitive":false}`, []string{"web-01"}, nil, true, false},
+		{"string regex matches case sensitive", `{"type":"STRING","operator":"REGEX_MATCHES","value":"^WEB-\\d+$","negate":false,"caseSensitive":true}`, []string{"web-01"}, nil, false, false},
+		{"string invalid regex", `{"type":"STRING","operator":"REGEX_MATCHES","value":"web-(","negate":false,"caseSensitive":true}`, []string{"web-01"}, nil, false, true},
+		{"string negated", `{"type":"STRING","operator":"BEGINS_WITH","value":"web","negate":true,"caseSensitive":true}`, []string{"web-01"}, nil, false, false},
+		{"string exists", `{"type":"STRING","operator":"EXISTS","negate":false,"caseSensitive":true}`, []string{"web-01"}, nil, true, false},
+		{"string exists without values", `{"type":"STRING","operator":"EXISTS","negate":false,"caseSensitive":true}`, []string{}, nil, false, false},
+		{"string negated exists", `{"type":"STRING","operator":"EXISTS","negate":true,"caseSensitive":true}`, []string{"web-01"}, nil, false, false},
+		{"string negated exists without values", `{"type":"STRING","operator":"EXISTS","negate":true,"caseSensitive":true}`, []string{}, nil, true, false},
+		// INTEGER
+		{"integer equals", `{"type":"INTEGER","operator":"EQUALS","value":8080,"negate":false}`, []string{"80", "8080"}, nil, true, false},
+		{"integer greater than", `{"type":"INTEGER","operator":"GREATER_THAN","value":8080,"negate":false}`, []string{"8080"}, nil, false, false},
+		{"integer greater than or equal", `{"type":"INTEGER","operator":"GREATER_THAN_OR_EQUAL","value":8080,"negate":false}`, []string{"8080"}, nil, true, false},
+		{"integer lower than", `{"type":"INTEGER","operator":"LOWER_THAN","value":1024,"negate":false}`, []string{"443"}, nil, true, false},
+		{"integer lower than or equal", `{"type":"INTEGER","operator":"LOWER_THAN_OR_EQUAL","value":1024,"negate":false}`, []string{"2048"}, nil, false, false},
+		{"integer skips non-numeric values", `{"type":"INTEGER","operator":"EQUALS","value":80,"negate":false}`, []string{"n/a", " 80 "}, nil, true, false},
+		{"integer exists", `{"type":"INTEGER","operator":"EXISTS","negate":false}`, []string{"80"}, nil, true, false},
+		{"integer negated exists without values", `{"type":"INTEGER","operator":"EXISTS","negate":true}`, []string{}, nil, true, false},
+		{"integer without value", `{"type":"INTEGER","operator":"EQUALS","negate":false}`, []string{"80"}, nil, false, true},
+		// IP_ADDRESS
+		{"ip address in CIDR range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"10.0.0.0/16","negate":false}`, []string{"10.0.3.4"}, nil, true, false},
+		{"ip address outside CIDR range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"10.0.0.0/16","negate":false}`, []string{"10.1.0.1"}, nil, false, false},
+		{"ip address in from-to range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"10.0.0.10 - 10.0.0.20","negate":false}`, []string{"10.0.0.15"}, nil, true, false},
+		{"ip address at the bounds of a from-to range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"10.0.0.10-10.0.0.20","negate":false}`, []string{"10.0.0.20"}, nil, true, false},
+		{"ip address outside from-to range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"10.0.0.10-10.0.0.20","negate":false}`, []string{"10.0.0.9", "10.0.0.21"}, nil, false, false},
+		{"ipv6 address in from-to range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"fd00::1-fd00::ff","negate":false}`, []string{"fd00::42"}, nil, true, false},
+		{"ip address invalid range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"10.0.0.10-x","negate":false}`, []string{"10.0.0.15"}, nil, false, true},
+		{"ip address negated range", `{"type":"IP_ADDRESS","operator":"IS_IP_IN_RANGE","value":"10.0.0.0/8","negate":true}`, []string{"192.168.0.1"}, nil, true, false},
+		{"ip address begins with", `{"type":"IP_ADDRESS","operator":"BEGINS_WITH","value":"192.168.","negate":false}`, []string{"192.168.0.1"}, nil, true, false},
+		{"ip address regex matches", `{"type":"IP_ADDRESS","operator":"REGEX_MATCHES","value":"^10\\.0\\.\\d+\\.4$","negate":false}`, []string{"10.0.3.4"}, nil, true, false},
+		{"ip address negated exists", `{"type":"IP_ADDRESS","operator":"EXISTS","negate":true}`, []string{"10.0.3.4"}, nil, false, false},
+		// TAG
+		{"tag key equals", `{"type":"TAG","operator":"TAG_KEY_EQUALS","value":{"context":"CONTEXTLESS","key":"env"},"negate":false}`, nil, tags, true, false},
+		{"tag key equals of another context", `{"type":"TAG","operator":"TAG_KEY_EQUALS","value":{"context":"AWS","key":"env"},"negate":false}`, nil, tags, false, false},
+		{"tag equals with value", `{"type":"TAG","operator":"EQUALS","value":{"context":"CONTEXTLESS","key":"env","value":"prod"},"negate":false}`, nil, tags, true, false},
+		{"tag equals with another value", `{"type":"TAG","operator":"EQUALS","value":{"context":"CONTEXTLESS","key":"env","value":"dev"},"negate":false}`, nil, tags, false, false},
+		{"tag equals without value", `{"type":"TAG","operator":"EQUALS","value":{"context":"CONTEXTLESS","key":"legacy"},"negate":false}`, nil, tags, true, false},
+		{"tag equals without value on a tag with value", `{"type":"TAG","operator":"EQUALS","value":{"context":"CONTEXTLESS","key":"env"},"negate":false}`, nil, tags, false, false},
+		{"tag equals of a context", `{"type":"TAG","operator":"EQUALS","value":{"context":"AWS","key":"app","value":"prod"},"negate":false}`, nil, tags, true, false},
+		{"tag negated key equals", `{"type":"TAG","operator":"TAG_KEY_EQUALS","value":{"context":"CONTEXTLESS","key":"legacy"},"negate":true}`, nil, tags, false, false},
+		{"tag negated key equals without tags", `{"type":"TAG","operator":"TAG_KEY_EQUALS","value":{"context":"CONTEXTLESS","key":"legacy"},"negate":true}`, nil, nil, true, false},
+		// INDEXED_NAME
+		{"indexed name equals ignoring case", `{"type":"INDEXED_NAME","operator":"EQUALS","value":"Checkout","negate":false}`, []string{"checkout"}, nil, true, false},
+		{"indexed name contains", `{"type":"INDEXED_NAME","operator":"CONTAINS","value":"OUT","negate":false}`, []string{"checkout"}, nil, true, false},
+		{"indexed name negated exists without values", `{"type":"INDEXED_NAME","operator":"EXISTS","negate":true}`, []string{}, nil, true, false},
+		// ENTITY_ID
+		{"entity id equals", `{"type":"ENTITY_ID","operator":"EQUALS","value":"HOST-0123456789ABCDEF","negate":false}`, []string{"HOST-0123456789ABCDEF"}, nil, true, false},
+		{"entity id equals case sensitive", `{"type":"ENTITY_ID","operator":"EQUALS","value":"HOST-0123456789ABCDEF","negate":false}`, []string{"host-0123456789abcdef"}, nil, false, false},
+		{"entity id negated", `{"type":"ENTITY_ID","operator":"EQUALS","value":"HOST-0123456789ABCDEF","negate":true}`, []string{"HOST-FEDCBA9876543210"}, nil, true, false},
+		// SERVICE_TYPE
+		{"service type equals", `{"type":"SERVICE_TYPE","operator":"EQUALS","value":"WEB_SERVICE","negate":false}`, []string{"WEB_SERVICE"}, nil, true, false},
+		{"service type equals another type", `{"type":"SERVICE_TYPE","operator":"EQUALS","value":"WEB_SERVICE","negate":false}`, []string{"DATABASE_SERVICE"}, nil, false, false},
+		{"service type negated", `{"type":"SERVICE_TYPE","operator":"EQUALS","value":"WEB_SERVICE","negate":true}`, []string{"DATABASE_SERVICE"}, nil, true, false},
+		{"service type exists", `{"type":"SERVICE_TYPE","operator":"EXISTS","negate":false}`, []string{"WEB_SERVICE"}, nil, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches, err := evaluator.Compare(comparisonOf(t, test.comparison), test.values, test.tags)
+			if test.err {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if matches != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, matches)
+			}
+		})
+	}
+}
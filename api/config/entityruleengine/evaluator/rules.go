@@ -0,0 +1,60 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	hostnaming "github.com/dtcookie/dynatrace/api/config/naming/hosts"
+	processgroupnaming "github.com/dtcookie/dynatrace/api/config/naming/processgroups"
+	servicenaming "github.com/dtcookie/dynatrace/api/config/naming/services"
+)
+
+// ManagementZoneRules returns the condition based rules of a management zone.
+// Dimensional and entity selector based rules are not covered.
+func ManagementZoneRules(mz *managementzones.ManagementZone) []*Rule {
+	rules := []*Rule{}
+	for idx, rule := range mz.Rules {
+		rules = append(rules, &Rule{
+			Name:       fmt.Sprintf("%s rule #%d", mz.Name, idx+1),
+			Type:       string(rule.Type),
+			Enabled:    rule.Enabled,
+			Conditions: rule.Conditions,
+		})
+	}
+	return rules
+}
+
+// AutoTagRules returns the condition based rules of an auto tag.
+// Entity selector based rules are not covered.
+func AutoTagRules(autoTag *autotags.AutoTag) []*Rule {
+	rules := []*Rule{}
+	for idx, rule := range autoTag.Rules {
+		ruleType := string(rule.Type)
+		if rule.Type == autotags.RuleTypes.Application {
+			ruleType = EntityTypes.Application
+		}
+		rules = append(rules, &Rule{
+			Name:       fmt.Sprintf("%s rule #%d", autoTag.Name, idx+1),
+			Type:       ruleType,
+			Enabled:    rule.Enabled,
+			Conditions: rule.Conditions,
+		})
+	}
+	return rules
+}
+
+// HostNamingRule returns the conditions of a host naming rule
+func HostNamingRule(rule *hostnaming.NamingRule) *Rule {
+	return &Rule{Name: rule.Name, Type: EntityTypes.Host, Enabled: rule.Enabled, Conditions: rule.Conditions}
+}
+
+// ProcessGroupNamingRule returns the conditions of a process group naming rule
+func ProcessGroupNamingRule(rule *processgroupnaming.NamingRule) *Rule {
+	return &Rule{Name: rule.Name, Type: EntityTypes.ProcessGroup, Enabled: rule.Enabled, Conditions: rule.Conditions}
+}
+
+// ServiceNamingRule returns the conditions of a service naming rule
+func ServiceNamingRule(rule *servicenaming.NamingRule) *Rule {
+	return &Rule{Name: rule.Name, Type: EntityTypes.Service, Enabled: rule.Enabled, Conditions: rule.Conditions}
+}
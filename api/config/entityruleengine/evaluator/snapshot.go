@@ -0,0 +1,186 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tag"
+	"github.com/dtcookie/dynatrace/api/config/topology/application"
+	"github.com/dtcookie/dynatrace/api/config/topology/host"
+	"github.com/dtcookie/dynatrace/api/config/topology/process"
+	"github.com/dtcookie/dynatrace/api/config/topology/processgroup"
+	"github.com/dtcookie/dynatrace/api/config/topology/service"
+)
+
+// Snapshot fetches the hosts, process groups, processes, services and web applications of an environment.
+// The Topology API only reports ID, name, tags and relationships. The tags and names of the process groups and hosts
+// an entity runs on get derived from these relationships, further attributes need to get added to the entities explicitly.
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/v1"
+// token is an API Token
+func Snapshot(baseURL string, token string) ([]*Entity, error) {
+	entities := []*Entity{}
+
+	hosts, err := host.NewService(baseURL, token).List()
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hosts {
+		entity := &Entity{ID: h.EntityId, Type: EntityTypes.Host, Name: h.DisplayName, Tags: []*tag.Info{}, Relationships: h.FromRelationships}
+		for _, t := range h.Tags {
+			entity.Tags = append(entity.Tags, newTag(t.Context, t.Key, t.Value))
+		}
+		entities = append(entities, entity)
+	}
+
+	processGroups, err := processgroup.NewService(baseURL, token).List()
+	if err != nil {
+		return nil, err
+	}
+	for _, pg := range processGroups {
+		entity := &Entity{ID: pg.EntityId, Type: EntityTypes.ProcessGroup, Name: pg.DisplayName, Tags: []*tag.Info{}, Relationships: pg.FromRelationships}
+		for _, t := range pg.Tags {
+			entity.Tags = append(entity.Tags, newTag(t.Context, t.Key, t.Value))
+		}
+		entities = append(entities, entity)
+	}
+
+	processes, err := process.NewService(baseURL, token).List()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range processes {
+		entity := &Entity{ID: p.EntityId, Type: EntityTypes.ProcessGroupInstance, Name: p.DisplayName, Tags: []*tag.Info{}, Relationships: p.FromRelationships}
+		for _, t := range p.Tags {
+			entity.Tags = append(entity.Tags, newTag(t.Context, t.Key, t.Value))
+		}
+		entities = append(entities, entity)
+	}
+
+	services, err := service.NewService(baseURL, token).List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range services {
+		entity := &Entity{ID: s.EntityId, Type: EntityTypes.Service, Name: s.DisplayName, Tags: []*tag.Info{}, Relationships: s.FromRelationships}
+		for _, t := range s.Tags {
+			entity.Tags = append(entity.Tags, newTag(t.Context, t.Key, t.Value))
+		}
+		entities = append(entities, entity)
+	}
+
+	applications, err := application.NewService(baseURL, token).List()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range applications {
+		entity := &Entity{ID: a.EntityId, Type: EntityTypes.Application, Name: a.DisplayName, Tags: []*tag.Info{}, Relationships: a.FromRelationships}
+		for _, t := range a.Tags {
+			entity.Tags = append(entity.Tags, newTag(t.Context, t.Key, t.Value))
+		}
+		entities = append(entities, entity)
+	}
+
+	Relate(entities)
+	return entities, nil
+}
+
+// Placement are the relationships telling where an entity runs, e.g. the process group and host of a service
+var Placement = map[string]bool{
+	"runsOn":                     true,
+	"runsOnHost":                 true,
+	"runsOnProcessGroupInstance": true,
+	"isProcessOf":                true,
+	"isInstanceOf":               true,
+}
+
+// Relate derives the tags and names of the entities the given entities run on from their Relationships.
+// A service running on a process group and host for example gets `PROCESS_GROUP_TAGS` and `HOST_TAGS`
+// as RelatedTags and `PROCESS_GROUP_NAME` and `HOST_NAME` as Attributes.
+// Related tags and attributes already present are left untouched.
+func Relate(entities []*Entity) {
+	byID := map[string]*Entity{}
+	for _, entity := range entities {
+		byID[entity.ID] = entity
+	}
+	for _, entity := range entities {
+		tags := map[string][]*tag.Info{}
+		names := map[string][]string{}
+		visited := map[string]bool{entity.ID: true}
+		frontier := []*Entity{entity}
+		for len(frontier) > 0 {
+			next := []*Entity{}
+			for _, current := range frontier {
+				relationships := []string{}
+				for relationship := range current.Relationships {
+					if Placement[relationship] {
+						relationships = append(relationships, relationship)
+					}
+				}
+				sort.Strings(relationships)
+				for _, relationship := range relationships {
+					for _, id := range current.Relationships[relationship] {
+						related := byID[id]
+						if related == nil || visited[id] {
+							continue
+						}
+						visited[id] = true
+						next = append(next, related)
+						if related.Type == entity.Type {
+							continue
+						}
+						tags[related.Type+"_TAGS"] = append(tags[related.Type+"_TAGS"], related.Tags...)
+						names[related.Type+"_NAME"] = append(names[related.Type+"_NAME"], related.Name)
+					}
+				}
+			}
+			frontier = next
+		}
+		for attribute, related := range tags {
+			if _, found := entity.RelatedTags[attribute]; found {
+				continue
+			}
+			if entity.RelatedTags == nil {
+				entity.RelatedTags = map[string][]*tag.Info{}
+			}
+			entity.RelatedTags[attribute] = related
+		}
+		for attribute, values := range names {
+			if _, found := entity.Attributes[attribute]; found {
+				continue
+			}
+			if entity.Attributes == nil {
+				entity.Attributes = map[string][]string{}
+			}
+			entity.Attributes[attribute] = values
+		}
+	}
+}
+
+func newTag(context string, key string, value *string) *tag.Info {
+	return &tag.Info{Context: tag.Context(context), Key: key, Value: value}
+}
+
+// ReadSnapshot reads entities previously stored with WriteSnapshot.
+// Related tags and names missing in the file get derived from the relationships of the entities.
+func ReadSnapshot(file string) ([]*Entity, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	entities := []*Entity{}
+	if err := json.Unmarshal(data, &entities); err != nil {
+		return nil, err
+	}
+	Relate(entities)
+	return entities, nil
+}
+
+// WriteSnapshot stores the given entities as JSON, e.g. in order to enrich them with further attributes
+func WriteSnapshot(file string, entities []*Entity) error {
+	data, err := json.MarshalIndent(entities, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
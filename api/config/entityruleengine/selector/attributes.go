@@ -0,0 +1,126 @@
+package selector
+
+// kind defines how an attribute translates into a predicate of an entity selector
+type kind int
+
+const (
+	nameKind kind = iota
+	tagsKind
+	idKind
+	propertyKind
+)
+
+// attribute describes the entity an attribute belongs to and how it is expressed within an entity selector
+type attribute struct {
+	entityType string
+	kind       kind
+	property   string // the name of the selector predicate for properties
+	quoted     bool   // whether property values are strings rather than enum values
+}
+
+// attributes contains the attributes of conditions which have an entity selector equivalent
+var attributes = map[string]*attribute{
+	"HOST_NAME":                            {entityType: "HOST", kind: nameKind},
+	"HOST_TAGS":                            {entityType: "HOST", kind: tagsKind},
+	"HOST_OS_TYPE":                         {entityType: "HOST", kind: propertyKind, property: "osType"},
+	"HOST_CLOUD_TYPE":                      {entityType: "HOST", kind: propertyKind, property: "cloudType"},
+	"HOST_HYPERVISOR_TYPE":                 {entityType: "HOST", kind: propertyKind, property: "hypervisorType"},
+	"HOST_BITNESS":                         {entityType: "HOST", kind: propertyKind, property: "bitness"},
+	"HOST_PAAS_TYPE":                       {entityType: "HOST", kind: propertyKind, property: "paasType"},
+	"HOST_ARCHITECTURE":                    {entityType: "HOST", kind: propertyKind, property: "osArchitecture"},
+	"HOST_IP_ADDRESS":                      {entityType: "HOST", kind: propertyKind, property: "ipAddress", quoted: true},
+	"HOST_GROUP_NAME":                      {entityType: "HOST_GROUP", kind: nameKind},
+	"HOST_GROUP_ID":                        {entityType: "HOST_GROUP", kind: idKind},
+	"PROCESS_GROUP_NAME":                   {entityType: "PROCESS_GROUP", kind: nameKind},
+	"PROCESS_GROUP_TAGS":                   {entityType: "PROCESS_GROUP", kind: tagsKind},
+	"PROCESS_GROUP_ID":                     {entityType: "PROCESS_GROUP", kind: idKind},
+	"PROCESS_GROUP_TECHNOLOGY":             {entityType: "PROCESS_GROUP", kind: propertyKind, property: "softwareTechnologies"},
+	"SERVICE_NAME":                         {entityType: "SERVICE", kind: nameKind},
+	"SERVICE_TAGS":                         {entityType: "SERVICE", kind: tagsKind},
+	"SERVICE_TYPE":                         {entityType: "SERVICE", kind: propertyKind, property: "serviceType"},
+	"SERVICE_TOPOLOGY":                     {entityType: "SERVICE", kind: propertyKind, property: "serviceTopology"},
+	"SERVICE_DATABASE_VENDOR":              {entityType: "SERVICE", kind: propertyKind, property: "databaseVendor", quoted: true},
+	"SERVICE_TECHNOLOGY":                   {entityType: "SERVICE", kind: propertyKind, property: "softwareTechnologies"},
+	"WEB_APPLICATION_NAME":                 {entityType: "APPLICATION", kind: nameKind},
+	"WEB_APPLICATION_TAGS":                 {entityType: "APPLICATION", kind: tagsKind},
+	"WEB_APPLICATION_TYPE":                 {entityType: "APPLICATION", kind: propertyKind, property: "applicationType"},
+	"MOBILE_APPLICATION_NAME":              {entityType: "MOBILE_APPLICATION", kind: nameKind},
+	"MOBILE_APPLICATION_TAGS":              {entityType: "MOBILE_APPLICATION", kind: tagsKind},
+	"CUSTOM_APPLICATION_NAME":              {entityType: "CUSTOM_APPLICATION", kind: nameKind},
+	"CUSTOM_APPLICATION_TAGS":              {entityType: "CUSTOM_APPLICATION", kind: tagsKind},
+	"CUSTOM_DEVICE_NAME":                   {entityType: "CUSTOM_DEVICE", kind: nameKind},
+	"CUSTOM_DEVICE_TAGS":                   {entityType: "CUSTOM_DEVICE", kind: tagsKind},
+	"CUSTOM_DEVICE_GROUP_NAME":             {entityType: "CUSTOM_DEVICE_GROUP", kind: nameKind},
+	"CUSTOM_DEVICE_GROUP_TAGS":             {entityType: "CUSTOM_DEVICE_GROUP", kind: tagsKind},
+	"ESXI_HOST_NAME":                       {entityType: "HYPERVISOR", kind: nameKind},
+	"ESXI_HOST_TAGS":                       {entityType: "HYPERVISOR", kind: tagsKind},
+	"HTTP_MONITOR_NAME":                    {entityType: "HTTP_CHECK", kind: nameKind},
+	"HTTP_MONITOR_TAGS":                    {entityType: "HTTP_CHECK", kind: tagsKind},
+	"BROWSER_MONITOR_NAME":                 {entityType: "SYNTHETIC_TEST", kind: nameKind},
+	"BROWSER_MONITOR_TAGS":                 {entityType: "SYNTHETIC_TEST", kind: tagsKind},
+	"EXTERNAL_MONITOR_NAME":                {entityType: "EXTERNAL_SYNTHETIC_TEST", kind: nameKind},
+	"EXTERNAL_MONITOR_TAGS":                {entityType: "EXTERNAL_SYNTHETIC_TEST", kind: tagsKind},
+	"KUBERNETES_CLUSTER_NAME":              {entityType: "KUBERNETES_CLUSTER", kind: nameKind},
+	"CLOUD_APPLICATION_NAME":               {entityType: "CLOUD_APPLICATION", kind: nameKind},
+	"CLOUD_APPLICATION_NAMESPACE_NAME":     {entityType: "CLOUD_APPLICATION_NAMESPACE", kind: nameKind},
+	"AWS_APPLICATION_LOAD_BALANCER_NAME":   {entityType: "AWS_APPLICATION_LOAD_BALANCER", kind: nameKind},
+	"AWS_APPLICATION_LOAD_BALANCER_TAGS":   {entityType: "AWS_APPLICATION_LOAD_BALANCER", kind: tagsKind},
+	"AWS_NETWORK_LOAD_BALANCER_NAME":       {entityType: "AWS_NETWORK_LOAD_BALANCER", kind: nameKind},
+	"AWS_NETWORK_LOAD_BALANCER_TAGS":       {entityType: "AWS_NETWORK_LOAD_BALANCER", kind: tagsKind},
+	"AWS_CLASSIC_LOAD_BALANCER_NAME":       {entityType: "ELASTIC_LOAD_BALANCER", kind: nameKind},
+	"AWS_CLASSIC_LOAD_BALANCER_TAGS":       {entityType: "ELASTIC_LOAD_BALANCER", kind: tagsKind},
+	"AWS_RELATIONAL_DATABASE_SERVICE_NAME": {entityType: "RELATIONAL_DATABASE_SERVICE", kind: nameKind},
+	"AWS_RELATIONAL_DATABASE_SERVICE_TAGS": {entityType: "RELATIONAL_DATABASE_SERVICE", kind: tagsKind},
+}
+
+// entityTypes maps the rule types of auto tags and management zones to the entity types known to entity selectors.
+// Rule types not contained are known by the same name.
+var entityTypes = map[string]string{
+	"APPLICATION":                     "APPLICATION",
+	"WEB_APPLICATION":                 "APPLICATION",
+	"ESXI_HOST":                       "HYPERVISOR",
+	"HTTP_MONITOR":                    "HTTP_CHECK",
+	"BROWSER_MONITOR":                 "SYNTHETIC_TEST",
+	"EXTERNAL_MONITOR":                "EXTERNAL_SYNTHETIC_TEST",
+	"AWS_CLASSIC_LOAD_BALANCER":       "ELASTIC_LOAD_BALANCER",
+	"AWS_RELATIONAL_DATABASE_SERVICE": "RELATIONAL_DATABASE_SERVICE",
+}
+
+// unsupportedTypes are rule types which don't correspond to a single entity type
+var unsupportedTypes = map[string]bool{
+	"APPMON_SERVER":          true,
+	"APPMON_SYSTEM_PROFILE":  true,
+	"AZURE":                  true,
+	"DATA_CENTER_SERVICE":    true,
+	"DCRUM_APPLICATION":      true,
+	"ENTERPRISE_APPLICATION": true,
+}
+
+// hop is a single relationship from one entity to another one
+type hop struct {
+	relationship string
+	entityType   string
+}
+
+// relationships lists how to get from the entities a rule applies to (key before `>`)
+// to the related entities a condition may refer to (key after `>`)
+var relationships = map[string][]hop{
+	"SERVICE>HOST":             {{"fromRelationships.runsOnHost", "HOST"}},
+	"SERVICE>PROCESS_GROUP":    {{"fromRelationships.runsOn", "PROCESS_GROUP"}},
+	"SERVICE>HOST_GROUP":       {{"fromRelationships.runsOnHost", "HOST"}, {"fromRelationships.isInstanceOf", "HOST_GROUP"}},
+	"PROCESS_GROUP>HOST":       {{"fromRelationships.runsOn", "HOST"}},
+	"PROCESS_GROUP>HOST_GROUP": {{"fromRelationships.runsOn", "HOST"}, {"fromRelationships.isInstanceOf", "HOST_GROUP"}},
+	"HOST>HOST_GROUP":          {{"fromRelationships.isInstanceOf", "HOST_GROUP"}},
+}
+
+// EntityType returns the entity selector type for the rule type of an auto tag or management zone.
+// An empty string is returned if there is no single equivalent type.
+func EntityType(ruleType string) string {
+	if unsupportedTypes[ruleType] {
+		return ""
+	}
+	if entityType, found := entityTypes[ruleType]; found {
+		return entityType
+	}
+	return ruleType
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/selector"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+)
+
+func main() {
+	var environmentURL, apiToken string
+	var kind string
+	var rewrite bool
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&environmentURL, "environment-url", os.Getenv("DT_ENVIRONMENT_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.StringVar(&kind, "kind", "autotag", "")
+	flagSet.BoolVar(&rewrite, "rewrite", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if (kind != "autotag" && kind != "managementzone") || flagSet.NArg() > 1 || (flagSet.NArg() == 0 && (environmentURL == "" || apiToken == "")) {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	if flagSet.NArg() == 1 {
+		err = convertFile(kind, flagSet.Arg(0), rewrite)
+	} else if kind == "autotag" {
+		err = convertAutoTags(autotags.NewService(strings.TrimSuffix(environmentURL, "/")+"/api/config/v1", apiToken), rewrite)
+	} else {
+		err = convertManagementZones(managementzones.NewService(strings.TrimSuffix(environmentURL, "/")+"/api/config/v1", apiToken), rewrite)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+// convertFile prints the report for a configuration stored in a file, with -rewrite also the rewritten configuration
+func convertFile(kind string, file string, rewrite bool) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var config interface{}
+	var report *selector.Report
+	if kind == "autotag" {
+		var autoTag autotags.AutoTag
+		if err := json.Unmarshal(data, &autoTag); err != nil {
+			return err
+		}
+		report = selector.RewriteAutoTag(&autoTag)
+		config = &autoTag
+	} else {
+		var mz managementzones.ManagementZone
+		if err := json.Unmarshal(data, &mz); err != nil {
+			return err
+		}
+		report = selector.RewriteManagementZone(&mz)
+		config = &mz
+	}
+	fmt.Println(report.String())
+	if !rewrite {
+		return nil
+	}
+	if data, err = json.MarshalIndent(config, "", "  "); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println(string(data))
+	return nil
+}
+
+func convertAutoTags(service *autotags.ServiceClient, rewrite bool) error {
+	stubs, err := service.ListAll()
+	if err != nil {
+		return err
+	}
+	for _, stub := range stubs.Values {
+		autoTag, err := service.Get(stub.ID)
+		if err != nil {
+			return err
+		}
+		report := selector.RewriteAutoTag(autoTag)
+		fmt.Println(report.String())
+		if rewrite && report.Converted > 0 {
+			if err := service.Update(autoTag); err != nil {
+				return err
+			}
+			fmt.Println("  updated")
+		}
+	}
+	return nil
+}
+
+func convertManagementZones(service *managementzones.ServiceClient, rewrite bool) error {
+	stubs, err := service.ListAll()
+	if err != nil {
+		return err
+	}
+	for _, stub := range stubs {
+		mz, err := service.Get(stub.ID, true)
+		if err != nil {
+			return err
+		}
+		report := selector.RewriteManagementZone(mz)
+		fmt.Println(report.String())
+		if rewrite && report.Converted > 0 {
+			if err := service.Update(mz); err != nil {
+				return err
+			}
+			fmt.Println("  updated")
+		}
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtselector [-environment-url <environment-url>] [-api-token <api-token>] [-kind autotag|managementzone] [-rewrite] [<config-file>]")
+	fmt.Println("  Converts condition based rules into entity selector based rules.")
+	fmt.Println("  Without <config-file> all auto tags or management zones of the environment are converted.")
+	fmt.Println("  -kind     autotag (default) or managementzone")
+	fmt.Println("  -rewrite  updates the configurations in the environment, or prints the rewritten <config-file>")
+	fmt.Println("            Rules without entity selector equivalent are kept. Without -rewrite nothing gets modified.")
+	fmt.Println("  Hint: you can also define the environment variables DT_ENVIRONMENT_URL and DT_API_TOKEN")
+}
@@ -0,0 +1,28 @@
+module github.com/dtcookie/dynatrace/api/config/entityruleengine/selector
+
+go 1.15
+
+require (
+	github.com/dtcookie/dynatrace/api/config/autotags v1.0.0
+	github.com/dtcookie/dynatrace/api/config/entityruleengine v1.0.11
+	github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator v1.0.0
+	github.com/dtcookie/dynatrace/api/config/managementzones v1.0.0
+	github.com/dtcookie/opt v1.0.0
+)
+
+replace (
+	github.com/dtcookie/dynatrace/api/config => ../..
+	github.com/dtcookie/dynatrace/api/config/autotags => ../../autotags
+	github.com/dtcookie/dynatrace/api/config/entityruleengine => ..
+	github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator => ../evaluator
+	github.com/dtcookie/dynatrace/api/config/managementzones => ../../managementzones
+	github.com/dtcookie/dynatrace/api/config/naming/hosts => ../../naming/hosts
+	github.com/dtcookie/dynatrace/api/config/naming/processgroups => ../../naming/processgroups
+	github.com/dtcookie/dynatrace/api/config/naming/services => ../../naming/services
+	github.com/dtcookie/dynatrace/api/config/topology/application => ../../topology/application
+	github.com/dtcookie/dynatrace/api/config/topology/host => ../../topology/host
+	github.com/dtcookie/dynatrace/api/config/topology/process => ../../topology/process
+	github.com/dtcookie/dynatrace/api/config/topology/processgroup => ../../topology/processgroup
+	github.com/dtcookie/dynatrace/api/config/topology/service => ../../topology/service
+	github.com/dtcookie/dynatrace/rest => ../../../../rest
+)
@@ -0,0 +1,116 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	"github.com/dtcookie/opt"
+)
+
+const leaveTextAsIs = "LEAVE_TEXT_AS_IS"
+
+// AutoTagRule converts a condition based rule of an auto tag.
+// Propagation and a normalization other than LEAVE_TEXT_AS_IS have no entity selector equivalent.
+func AutoTagRule(rule *autotags.Rule) *Conversion {
+	conversion := Convert(string(rule.Type), rule.Conditions)
+	if len(rule.PropagationTypes) > 0 {
+		types := []string{}
+		for _, propagationType := range rule.PropagationTypes {
+			types = append(types, string(propagationType))
+		}
+		conversion.flag("propagation to related entities (%s) has no entity selector equivalent", strings.Join(types, ", "))
+	}
+	if rule.Normalization != nil && *rule.Normalization != leaveTextAsIs {
+		conversion.flag("normalization %s has no entity selector equivalent", *rule.Normalization)
+	}
+	if !conversion.OK() {
+		conversion.Selector = ""
+	}
+	return conversion
+}
+
+// ManagementZoneRule converts a condition based rule of a management zone.
+// Propagation has no entity selector equivalent.
+func ManagementZoneRule(rule *managementzones.Rule) *Conversion {
+	conversion := Convert(string(rule.Type), rule.Conditions)
+	if len(rule.PropagationTypes) > 0 {
+		types := []string{}
+		for _, propagationType := range rule.PropagationTypes {
+			types = append(types, string(propagationType))
+		}
+		conversion.flag("propagation to related entities (%s) has no entity selector equivalent", strings.Join(types, ", "))
+	}
+	if !conversion.OK() {
+		conversion.Selector = ""
+	}
+	return conversion
+}
+
+// Report summarizes rewriting the rules of an auto tag or a management zone
+type Report struct {
+	Name        string
+	Conversions []*Conversion // one per condition based rule, in the order the rules were defined
+	Converted   int           // the number of rules replaced by entity selector based rules
+}
+
+func (me *Report) String() string {
+	lines := []string{fmt.Sprintf("%s: %d of %d rules converted", me.Name, me.Converted, len(me.Conversions))}
+	for idx, conversion := range me.Conversions {
+		if conversion.OK() {
+			lines = append(lines, fmt.Sprintf("  rule #%d: %s", idx+1, conversion.Selector))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  rule #%d: kept", idx+1))
+		for _, issue := range conversion.Issues {
+			lines = append(lines, "    "+issue.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RewriteAutoTag replaces the condition based rules of an auto tag with entity selector based rules.
+// Rules without an entity selector equivalent are kept. The auto tag gets modified in place.
+func RewriteAutoTag(autoTag *autotags.AutoTag) *Report {
+	report := &Report{Name: autoTag.Name}
+	kept := []*autotags.Rule{}
+	for _, rule := range autoTag.Rules {
+		conversion := AutoTagRule(rule)
+		report.Conversions = append(report.Conversions, conversion)
+		if !conversion.OK() {
+			kept = append(kept, rule)
+			continue
+		}
+		autoTag.EntitySelectorBasedRules = append(autoTag.EntitySelectorBasedRules, &autotags.EntitySelectorBasedRule{
+			Enabled:     opt.NewBool(rule.Enabled),
+			Selector:    conversion.Selector,
+			ValueFormat: rule.ValueFormat,
+		})
+		report.Converted++
+	}
+	autoTag.Rules = kept
+	return report
+}
+
+// RewriteManagementZone replaces the condition based rules of a management zone with entity selector based rules.
+// Rules without an entity selector equivalent are kept. The management zone gets modified in place.
+func RewriteManagementZone(mz *managementzones.ManagementZone) *Report {
+	report := &Report{Name: mz.Name}
+	kept := []*managementzones.Rule{}
+	for _, rule := range mz.Rules {
+		conversion := ManagementZoneRule(rule)
+		report.Conversions = append(report.Conversions, conversion)
+		if !conversion.OK() {
+			kept = append(kept, rule)
+			continue
+		}
+		mz.EntitySelectorBasedRules = append(mz.EntitySelectorBasedRules, &managementzones.EntitySelectorBasedRule{
+			Enabled:  opt.NewBool(rule.Enabled),
+			Selector: conversion.Selector,
+		})
+		report.Converted++
+	}
+	mz.Rules = kept
+	return report
+}
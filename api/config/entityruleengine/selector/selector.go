@@ -0,0 +1,181 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tag"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/condition"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/dsl"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator"
+)
+
+// Issue describes a condition or a setting of a rule which has no entity selector equivalent
+type Issue struct {
+	Condition int    // the index of the condition within the rule, -1 if the issue concerns the rule itself
+	Text      string // the condition in the notation of package dsl
+	Reason    string
+}
+
+func (me *Issue) String() string {
+	if me.Condition < 0 {
+		return me.Reason
+	}
+	return fmt.Sprintf("condition #%d '%s': %s", me.Condition+1, me.Text, me.Reason)
+}
+
+// Conversion is the result of converting the conditions of a rule into an entity selector
+type Conversion struct {
+	Selector string   // the equivalent entity selector, only valid if there are no issues
+	Issues   []*Issue // the reasons why the rule can't be expressed as entity selector
+}
+
+// OK reports whether the rule can be replaced by the entity selector
+func (me *Conversion) OK() bool {
+	return len(me.Issues) == 0
+}
+
+func (me *Conversion) String() string {
+	if me.OK() {
+		return me.Selector
+	}
+	lines := []string{}
+	for _, issue := range me.Issues {
+		lines = append(lines, issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (me *Conversion) flag(reason string, args ...interface{}) {
+	me.Issues = append(me.Issues, &Issue{Condition: -1, Reason: fmt.Sprintf(reason, args...)})
+}
+
+// Convert translates conditions, which all need to match for entities of the given rule type, into an entity selector, e.g.
+//
+//	type(SERVICE),entityName.startsWith("checkout"),fromRelationships.runsOnHost(type(HOST),tag("[AWS]env:prod"))
+//
+// Conditions referring to related entities are expressed via relationships.
+// Conditions without an equivalent, like regular expressions, case sensitive comparisons
+// or custom metadata, are reported as issues of the conversion.
+func Convert(ruleType string, conditions []*entityruleengine.Condition) *Conversion {
+	conversion := &Conversion{}
+	entityType := EntityType(ruleType)
+	if entityType == "" {
+		conversion.flag("rules of type %s have no entity selector equivalent", ruleType)
+		return conversion
+	}
+	predicates := []string{"type(" + entityType + ")"}
+	for idx, c := range conditions {
+		predicate, err := convert(entityType, c)
+		if err != nil {
+			text, _ := dsl.FormatCondition(c)
+			conversion.Issues = append(conversion.Issues, &Issue{Condition: idx, Text: text, Reason: err.Error()})
+			continue
+		}
+		if predicate != "" {
+			predicates = append(predicates, predicate)
+		}
+	}
+	if conversion.OK() {
+		conversion.Selector = strings.Join(predicates, ",")
+	}
+	return conversion
+}
+
+// convert translates a single condition into a predicate.
+// An empty predicate is returned for conditions which are true for every entity of the given type.
+func convert(entityType string, c *entityruleengine.Condition) (string, error) {
+	if c == nil || c.Key == nil || c.ComparisonInfo == nil {
+		return "", fmt.Errorf("the condition lacks a key or a comparison")
+	}
+	if _, plain := c.Key.(*condition.BaseConditionKey); !plain {
+		return "", fmt.Errorf("attributes with a dynamic key have no entity selector equivalent")
+	}
+	name := string(c.Key.GetAttribute())
+	attr, found := attributes[name]
+	if !found {
+		return "", fmt.Errorf("%s has no entity selector equivalent", name)
+	}
+	cmp := c.ComparisonInfo
+	operator := evaluator.Operator(cmp)
+	if s, ok := cmp.(*comparison.String); ok && s.CaseSensitive {
+		return "", fmt.Errorf("case sensitive comparisons have no entity selector equivalent")
+	}
+
+	predicate := ""
+	switch attr.kind {
+	case nameKind:
+		switch operator {
+		case "EQUALS":
+			predicate = "entityName.equals(" + quote(evaluator.Value(cmp)) + ")"
+		case "BEGINS_WITH":
+			predicate = "entityName.startsWith(" + quote(evaluator.Value(cmp)) + ")"
+		case "CONTAINS":
+			predicate = "entityName.contains(" + quote(evaluator.Value(cmp)) + ")"
+		case "EXISTS":
+		default:
+			return "", fmt.Errorf("operator %s has no entity selector equivalent", operator)
+		}
+	case tagsKind:
+		switch t := cmp.(type) {
+		case *comparison.Tag:
+			if t.Value == nil {
+				return "", fmt.Errorf("the tag comparison lacks a value")
+			}
+			info := t.Value
+			if operator == "TAG_KEY_EQUALS" {
+				info = &tag.Info{Context: info.Context, Key: info.Key}
+			}
+			predicate = "tag(" + quote(evaluator.TagString(info)) + ")"
+		default:
+			return "", fmt.Errorf("comparisons of type %s have no entity selector equivalent", cmp.GetType())
+		}
+	case idKind:
+		switch operator {
+		case "EQUALS":
+			predicate = "entityId(" + quote(evaluator.Value(cmp)) + ")"
+		case "EXISTS":
+		default:
+			return "", fmt.Errorf("operator %s has no entity selector equivalent", operator)
+		}
+	case propertyKind:
+		if operator != "EQUALS" {
+			return "", fmt.Errorf("operator %s has no entity selector equivalent", operator)
+		}
+		value := evaluator.Value(cmp)
+		if attr.quoted {
+			value = quote(value)
+		}
+		predicate = attr.property + "(" + value + ")"
+	}
+
+	if cmp.IsNegated() {
+		if predicate == "" {
+			return "", fmt.Errorf("negated EXISTS has no entity selector equivalent")
+		}
+		predicate = "not(" + predicate + ")"
+	}
+	if attr.entityType == entityType {
+		return predicate, nil
+	}
+
+	hops, found := relationships[entityType+">"+attr.entityType]
+	if !found {
+		return "", fmt.Errorf("%s refers to entities of type %s, which can't be reached from entities of type %s", name, attr.entityType, entityType)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		inner := "type(" + hops[i].entityType + ")"
+		if predicate != "" {
+			inner = inner + "," + predicate
+		}
+		predicate = hops[i].relationship + "(" + inner + ")"
+	}
+	return predicate, nil
+}
+
+// quote quotes a string value of an entity selector, `~` and `"` get escaped with `~`
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`~`, `~~`, `"`, `~"`).Replace(s) + `"`
+}
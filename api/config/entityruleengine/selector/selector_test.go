@@ -0,0 +1,65 @@
+package selector_test
+
+import (
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/dsl"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/selector"
+	"github.com/dtcookie/opt"
+)
+
+func TestConvert(t *testing.T) {
+	for _, test := range []struct {
+		ruleType   string
+		conditions string
+		selector   string
+		issues     int
+	}{
+		{"HOST", `HOST_NAME BEGINS_WITH web AND HOST_OS_TYPE EQUALS LINUX AND HOST_TAGS EQUALS [AWS]env:prod`,
+			`type(HOST),entityName.startsWith("web"),osType(LINUX),tag("[AWS]env:prod")`, 0},
+		{"SERVICE", `SERVICE_NAME NOT CONTAINS "say ~\"hi\"" AND HOST_GROUP_NAME EQUALS shop AND PROCESS_GROUP_NAME EXISTS`,
+			`type(SERVICE),not(entityName.contains("say ~~~"hi~"")),fromRelationships.runsOnHost(type(HOST),fromRelationships.isInstanceOf(type(HOST_GROUP),entityName.equals("shop"))),fromRelationships.runsOn(type(PROCESS_GROUP))`, 0},
+		{"WEB_APPLICATION", `WEB_APPLICATION_TAGS TAG_KEY_EQUALS team:a`, `type(APPLICATION),tag("team")`, 0},
+		{"HOST", `HOST_NAME EXISTS`, `type(HOST)`, 0},
+		{"HOST", `HOST_NAME REGEX_MATCHES "^web" AND HOST_NAME EQUALS Web CASE_SENSITIVE AND SERVICE_NAME EQUALS x`, ``, 3},
+		{"AZURE", `HOST_NAME EXISTS`, ``, 1},
+	} {
+		conditions, err := dsl.Parse(test.conditions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conversion := selector.Convert(test.ruleType, conditions)
+		if len(conversion.Issues) != test.issues {
+			t.Errorf("'%s': expected %d issues, got\n%s", test.conditions, test.issues, conversion.String())
+		}
+		if conversion.Selector != test.selector {
+			t.Errorf("'%s': expected selector\n%s\ngot\n%s", test.conditions, test.selector, conversion.Selector)
+		}
+	}
+}
+
+func TestRewriteAutoTag(t *testing.T) {
+	convertible, err := dsl.Parse(`HOST_NAME BEGINS_WITH web`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	autoTag := &autotags.AutoTag{
+		Name: "frontend",
+		Rules: []*autotags.Rule{
+			{Type: autotags.RuleTypes.Host, Enabled: true, Conditions: convertible, ValueFormat: opt.NewString("{Host:DetectedName}")},
+			{Type: autotags.RuleTypes.Host, Enabled: true, Conditions: convertible, PropagationTypes: []autotags.PropagationType{autotags.PropagationTypes.HostToProcessGroupInstance}},
+		},
+	}
+	report := selector.RewriteAutoTag(autoTag)
+	if report.Converted != 1 || len(autoTag.Rules) != 1 || len(autoTag.EntitySelectorBasedRules) != 1 {
+		t.Fatalf("expected one rule to get converted, got\n%s", report.String())
+	}
+	rule := autoTag.EntitySelectorBasedRules[0]
+	if rule.Selector != `type(HOST),entityName.startsWith("web")` || opt.String(rule.ValueFormat) != "{Host:DetectedName}" || !opt.Bool(rule.Enabled) {
+		t.Errorf("unexpected entity selector based rule %s '%s'", rule.Selector, opt.String(rule.ValueFormat))
+	}
+	if len(autoTag.Rules[0].PropagationTypes) != 1 {
+		t.Error("expected the rule with propagation to be kept")
+	}
+}
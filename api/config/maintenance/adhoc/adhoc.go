@@ -0,0 +1,148 @@
+package adhoc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/maintenance/calendar"
+)
+
+// DefaultPrefix is the prefix of the names of maintenance windows created by a Service.
+// It's how Cleanup recognizes the windows it's allowed to delete.
+const DefaultPrefix = "[adhoc] "
+
+// Request describes an ad-hoc maintenance, e.g. for the duration of a deployment.
+// Entities, Tags and ManagementZones narrow down the scope. Putting the whole environment into maintenance requires Environment instead.
+type Request struct {
+	Name        string // e.g. the name of the deployment; gets prefixed
+	Description string
+	Start       time.Time     // defaults to now
+	Duration    time.Duration // gets rounded up to full minutes
+	Suppression maintenance.Suppression
+	// Entities are the IDs of the monitored entities to put into maintenance, e.g. `HOST-0123456789ABCDEF`
+	Entities []string
+	// Tags put the entities carrying them into maintenance, all of them if MatchAllTags is set, any of them otherwise
+	Tags         []*maintenance.TagInfo
+	MatchAllTags bool
+	// EntityType restricts Tags and ManagementZones to entities of the given type, e.g. `HOST`
+	EntityType *maintenance.FilterType
+	// ManagementZones are the IDs of management zones. Combined with Tags only entities matching both are in maintenance.
+	ManagementZones []string
+	// Environment puts the whole environment into maintenance. It can't be combined with Entities, Tags or ManagementZones.
+	Environment bool
+	// SuppressSyntheticMonitors suppresses the execution of synthetic monitors during the maintenance
+	SuppressSyntheticMonitors bool
+}
+
+// Window converts the request into a ONCE maintenance window with the given name prefix
+func (me *Request) Window(prefix string) (*maintenance.Window, error) {
+	if len(strings.TrimSpace(me.Name)) == 0 {
+		return nil, errors.New("a name is required")
+	}
+	if me.Duration <= 0 {
+		return nil, errors.New("the duration must be positive")
+	}
+	scoped := len(me.Entities) > 0 || len(me.Tags) > 0 || len(me.ManagementZones) > 0
+	if !scoped && !me.Environment {
+		return nil, errors.New("entities, tags or management zones are required, unless the whole environment is meant to be in maintenance")
+	}
+	if scoped && me.Environment {
+		return nil, errors.New("the whole environment can't be combined with entities, tags or management zones")
+	}
+	suppression := me.Suppression
+	if suppression == "" {
+		suppression = maintenance.Suppressions.DetectProblemsDontAlert
+	}
+	start := me.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	start = start.UTC().Truncate(time.Minute)
+	end := start.Add(me.Duration)
+	if rounded := end.Truncate(time.Minute); rounded.Before(end) {
+		end = rounded.Add(time.Minute)
+	}
+
+	window := &maintenance.Window{
+		Name:        prefix + me.Name,
+		Description: me.Description,
+		Suppression: suppression,
+		Type:        maintenance.MaintenanceWindowTypes.Planned,
+		Enabled:     true,
+		Schedule: &maintenance.Schedule{
+			Start:          start.Format(calendar.DateTimeLayout),
+			End:            end.Format(calendar.DateTimeLayout),
+			ZoneID:         "UTC",
+			RecurrenceType: maintenance.RecurrenceTypes.Once,
+		},
+	}
+	if me.SuppressSyntheticMonitors {
+		suppress := true
+		window.SuppressSyntheticMonitorsExecution = &suppress
+	}
+	if len(window.Description) == 0 {
+		window.Description = fmt.Sprintf("ad-hoc maintenance from %s until %s UTC", window.Schedule.Start, window.Schedule.End)
+	}
+	if scope := me.scope(); scope != nil {
+		window.Scope = scope
+	}
+	return window, nil
+}
+
+func (me *Request) scope() *maintenance.Scope {
+	if len(me.Entities) == 0 && len(me.Tags) == 0 && len(me.ManagementZones) == 0 {
+		return nil
+	}
+	scope := &maintenance.Scope{Entities: append([]string{}, me.Entities...), Matches: []*maintenance.Filter{}}
+	newFilter := func() *maintenance.Filter {
+		filter := &maintenance.Filter{Type: me.EntityType, Tags: me.Tags}
+		if filter.Tags == nil {
+			filter.Tags = []*maintenance.TagInfo{}
+		}
+		if len(me.Tags) > 1 {
+			if me.MatchAllTags {
+				filter.TagCombination = maintenance.TagCombinations.And.Ref()
+			} else {
+				filter.TagCombination = maintenance.TagCombinations.Or.Ref()
+			}
+		}
+		return filter
+	}
+	// matching rules are combined using OR, hence one per management zone
+	for _, mzID := range me.ManagementZones {
+		filter := newFilter()
+		id := mzID
+		filter.MzID = &id
+		scope.Matches = append(scope.Matches, filter)
+	}
+	if len(me.ManagementZones) == 0 && len(me.Tags) > 0 {
+		scope.Matches = append(scope.Matches, newFilter())
+	}
+	return scope
+}
+
+// ParseTag parses tags in the notation used by Dynatrace: `key`, `key:value`, `[context]key` or `[context]key:value`
+func ParseTag(s string) (*maintenance.TagInfo, error) {
+	tag := &maintenance.TagInfo{Context: maintenance.Contexts.Contextless}
+	if strings.HasPrefix(s, "[") {
+		idx := strings.Index(s, "]")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid tag '%s'", s)
+		}
+		tag.Context = maintenance.Context(strings.ToUpper(s[1:idx]))
+		s = s[idx+1:]
+	}
+	if idx := strings.Index(s, ":"); idx >= 0 {
+		value := s[idx+1:]
+		tag.Value = &value
+		s = s[:idx]
+	}
+	if len(s) == 0 {
+		return nil, errors.New("the key of a tag must not be empty")
+	}
+	tag.Key = s
+	return tag, nil
+}
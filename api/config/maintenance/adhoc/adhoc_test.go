@@ -0,0 +1,99 @@
+package adhoc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/maintenance/adhoc"
+)
+
+func tags(t *testing.T, values ...string) []*maintenance.TagInfo {
+	result := []*maintenance.TagInfo{}
+	for _, value := range values {
+		tag, err := adhoc.ParseTag(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, tag)
+	}
+	return result
+}
+
+func TestWindow(t *testing.T) {
+	start := time.Date(2021, 3, 6, 21, 59, 30, 0, time.FixedZone("CET", 3600))
+	window, err := (&adhoc.Request{Name: "deploy", Start: start, Duration: 90*time.Minute + time.Second, Entities: []string{"HOST-0123456789ABCDEF"}}).Window(adhoc.DefaultPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if window.Name != "[adhoc] deploy" || window.Suppression != maintenance.Suppressions.DetectProblemsDontAlert {
+		t.Errorf("unexpected window %+v", window)
+	}
+	if window.Schedule.Start != "2021-03-06 20:59" || window.Schedule.End != "2021-03-06 22:30" || window.Schedule.ZoneID != "UTC" {
+		t.Errorf("unexpected schedule %+v", window.Schedule)
+	}
+
+	for _, request := range []*adhoc.Request{
+		{Duration: time.Hour, Environment: true},
+		{Name: "deploy", Environment: true},
+		{Name: "deploy", Duration: time.Hour},
+		{Name: "deploy", Duration: time.Hour, Environment: true, ManagementZones: []string{"123"}},
+	} {
+		if _, err := request.Window(adhoc.DefaultPrefix); err == nil {
+			t.Errorf("expected %+v to be rejected", request)
+		}
+	}
+
+	window, err = (&adhoc.Request{Name: "deploy", Duration: time.Hour, Environment: true}).Window(adhoc.DefaultPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if window.Scope != nil {
+		t.Errorf("expected no scope for the whole environment, got %+v", window.Scope)
+	}
+}
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *adhoc.Request
+		matches []string // management zone and tag combination of every matching rule
+	}{
+		{"entities only", &adhoc.Request{Entities: []string{"HOST-0123456789ABCDEF"}}, []string{}},
+		{"single tag", &adhoc.Request{Tags: tags(t, "app")}, []string{" "}},
+		{"any tag", &adhoc.Request{Tags: tags(t, "app", "[AWS]team:payments")}, []string{" OR"}},
+		{"all tags", &adhoc.Request{Tags: tags(t, "app", "env:prod"), MatchAllTags: true}, []string{" AND"}},
+		{"management zones", &adhoc.Request{ManagementZones: []string{"1", "2"}, Tags: tags(t, "app")}, []string{"1 ", "2 "}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.request.Name, test.request.Duration = "deploy", time.Hour
+			window, err := test.request.Window("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if window.Scope == nil || len(window.Scope.Entities) != len(test.request.Entities) {
+				t.Fatalf("unexpected scope %+v", window.Scope)
+			}
+			if len(window.Scope.Matches) != len(test.matches) {
+				t.Fatalf("expected %d matching rules, got %d", len(test.matches), len(window.Scope.Matches))
+			}
+			for i, filter := range window.Scope.Matches {
+				actual := ""
+				if filter.MzID != nil {
+					actual = *filter.MzID
+				}
+				actual += " "
+				if filter.TagCombination != nil {
+					actual += string(*filter.TagCombination)
+				}
+				if actual != test.matches[i] {
+					t.Errorf("expected matching rule '%s', got '%s'", test.matches[i], actual)
+				}
+				if len(filter.Tags) != len(test.request.Tags) {
+					t.Errorf("expected %d tags, got %d", len(test.request.Tags), len(filter.Tags))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/maintenance/adhoc"
+)
+
+// values collects the values of a flag which may get specified several times
+type values []string
+
+func (me *values) String() string {
+	return strings.Join(*me, ",")
+}
+
+func (me *values) Set(value string) error {
+	*me = append(*me, value)
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var apiBaseURL string
+	var apiToken string
+	var prefix string
+	var name string
+	var description string
+	var duration time.Duration
+	var suppression string
+	var entityType string
+	var entities, tags, managementZones values
+	var allTags bool
+	var environment bool
+	var suppressSynthetics bool
+	var grace time.Duration
+	var dryRun bool
+
+	command := os.Args[1]
+	flagSet := flag.NewFlagSet(os.Args[0]+" "+command, flag.ContinueOnError)
+	flagSet.StringVar(&apiBaseURL, "api-base-url", os.Getenv("DT_API_BASE_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.StringVar(&prefix, "prefix", adhoc.DefaultPrefix, "")
+	switch command {
+	case "create":
+		flagSet.StringVar(&name, "name", "", "")
+		flagSet.StringVar(&description, "description", "", "")
+		flagSet.DurationVar(&duration, "duration", 30*time.Minute, "")
+		flagSet.StringVar(&suppression, "suppression", string(maintenance.Suppressions.DetectProblemsDontAlert), "")
+		flagSet.StringVar(&entityType, "type", "", "")
+		flagSet.Var(&entities, "entity", "")
+		flagSet.Var(&tags, "tag", "")
+		flagSet.Var(&managementZones, "mz", "")
+		flagSet.BoolVar(&allTags, "all-tags", false, "")
+		flagSet.BoolVar(&environment, "environment", false, "")
+		flagSet.BoolVar(&suppressSynthetics, "suppress-synthetics", false, "")
+	case "cleanup":
+		flagSet.DurationVar(&grace, "grace", 0, "")
+		flagSet.BoolVar(&dryRun, "dry-run", false, "")
+	default:
+		usage()
+		os.Exit(2)
+	}
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[2:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if apiBaseURL == "" || apiToken == "" || flagSet.NArg() != 0 {
+		usage()
+		os.Exit(2)
+	}
+	service := adhoc.NewService(apiBaseURL, apiToken)
+	service.Prefix = prefix
+
+	if command == "create" {
+		request := &adhoc.Request{
+			Name:                      name,
+			Description:               description,
+			Duration:                  duration,
+			Suppression:               maintenance.Suppression(suppression),
+			Entities:                  entities,
+			ManagementZones:           managementZones,
+			MatchAllTags:              allTags,
+			Environment:               environment,
+			SuppressSyntheticMonitors: suppressSynthetics,
+		}
+		if entityType != "" {
+			request.EntityType = maintenance.FilterType(strings.ToUpper(entityType)).Ref()
+		}
+		for _, s := range tags {
+			tag, err := adhoc.ParseTag(s)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(2)
+			}
+			request.Tags = append(request.Tags, tag)
+		}
+		window, err := service.Create(request)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("created '%s' (%s) from %s until %s UTC\n", window.Name, *window.ID, window.Schedule.Start, window.Schedule.End)
+		return
+	}
+
+	var windows []*maintenance.Window
+	var err error
+	if dryRun {
+		windows, err = service.Expired(time.Now().Add(-grace))
+	} else {
+		windows, err = service.Cleanup(grace)
+	}
+	verb := "deleted"
+	if dryRun {
+		verb = "would delete"
+	}
+	for _, window := range windows {
+		fmt.Printf("%s '%s' (%s), ended %s UTC\n", verb, window.Name, *window.ID, window.Schedule.End)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtmaint create [-api-base-url <api-base-url>] [-api-token <api-token>] -name <name> [-duration <duration>] [-suppression <suppression>]")
+	fmt.Println("                      [-entity <entity-id>]... [-tag <tag>]... [-all-tags] [-mz <management-zone-id>]... [-type <entity-type>] [-environment] [-suppress-synthetics] [-description <text>]")
+	fmt.Println("       dtmaint cleanup [-api-base-url <api-base-url>] [-api-token <api-token>] [-grace <duration>] [-dry-run]")
+	fmt.Println("  create   creates a maintenance window starting now, by default for 30m")
+	fmt.Println("           <suppression> is one of DETECT_PROBLEMS_AND_ALERT, DETECT_PROBLEMS_DONT_ALERT (default), DONT_DETECT_PROBLEMS")
+	fmt.Println("           <tag> is noted like `key`, `key:value` or `[context]key:value`, entities carrying any of them (all of them with -all-tags) are in maintenance")
+	fmt.Println("           -environment puts the whole environment into maintenance, it's required without -entity, -tag or -mz")
+	fmt.Println("  cleanup  deletes the maintenance windows created via `dtmaint create` that ended more than <duration> ago")
+	fmt.Println("  -prefix  the prefix identifying the maintenance windows managed by dtmaint (default: '" + adhoc.DefaultPrefix + "')")
+	fmt.Println("  Hint: you can also define the environment variables DT_API_BASE_URL and DT_API_TOKEN")
+}
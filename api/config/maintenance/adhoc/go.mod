@@ -0,0 +1,15 @@
+module github.com/dtcookie/dynatrace/api/config/maintenance/adhoc
+
+go 1.15
+
+require (
+	github.com/dtcookie/dynatrace/api/config/maintenance v1.0.0
+	github.com/dtcookie/dynatrace/api/config/maintenance/calendar v1.0.0
+)
+
+replace (
+	github.com/dtcookie/dynatrace/api/config => ../..
+	github.com/dtcookie/dynatrace/api/config/maintenance => ..
+	github.com/dtcookie/dynatrace/api/config/maintenance/calendar => ../calendar
+	github.com/dtcookie/dynatrace/rest => ../../../../rest
+)
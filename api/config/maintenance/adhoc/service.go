@@ -0,0 +1,85 @@
+package adhoc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/maintenance/calendar"
+)
+
+// Service creates ad-hoc maintenance windows and cleans them up once they've expired
+type Service struct {
+	client *maintenance.ServiceClient
+	// Prefix is prepended to the names of created windows. Cleanup only considers windows with this prefix.
+	Prefix string
+}
+
+// NewService creates a new Service
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/config/v1"
+// token is an API Token
+func NewService(baseURL string, token string) *Service {
+	return &Service{client: maintenance.NewService(baseURL, token), Prefix: DefaultPrefix}
+}
+
+// Create creates a maintenance window for the given request and returns it including its ID
+func (me *Service) Create(request *Request) (*maintenance.Window, error) {
+	window, err := request.Window(me.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	stub, err := me.client.Create(window)
+	if err != nil {
+		return nil, err
+	}
+	window.ID = &stub.ID
+	return window, nil
+}
+
+// Expired returns the ad-hoc windows which ended before the given point in time
+func (me *Service) Expired(before time.Time) ([]*maintenance.Window, error) {
+	stubList, err := me.client.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	expired := []*maintenance.Window{}
+	for _, stub := range stubList.Values {
+		if len(me.Prefix) == 0 || !strings.HasPrefix(stub.Name, me.Prefix) {
+			continue
+		}
+		window, err := me.client.Get(stub.ID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching '%s' (%s) failed: %s", stub.Name, stub.ID, err.Error())
+		}
+		if window.Schedule == nil || window.Schedule.RecurrenceType != maintenance.RecurrenceTypes.Once {
+			continue
+		}
+		_, end, _, err := calendar.Validity(window.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' (%s): %s", stub.Name, stub.ID, err.Error())
+		}
+		if end.Before(before) {
+			window.ID = &stub.ID
+			expired = append(expired, window)
+		}
+	}
+	return expired, nil
+}
+
+// Cleanup deletes the ad-hoc windows which ended more than `grace` ago and returns them.
+// Only windows with a ONCE schedule whose name starts with the Prefix are considered.
+func (me *Service) Cleanup(grace time.Duration) ([]*maintenance.Window, error) {
+	expired, err := me.Expired(time.Now().Add(-grace))
+	if err != nil {
+		return nil, err
+	}
+	deleted := []*maintenance.Window{}
+	for _, window := range expired {
+		if err = me.client.Delete(*window.ID); err != nil {
+			return deleted, fmt.Errorf("deleting '%s' (%s) failed: %s", window.Name, *window.ID, err.Error())
+		}
+		deleted = append(deleted, window)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,159 @@
+package calendar_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/maintenance/calendar"
+)
+
+func weekly() *maintenance.Window {
+	return &maintenance.Window{
+		Name:        "patch night",
+		Enabled:     true,
+		Suppression: maintenance.Suppressions.DontDetectProblems,
+		Type:        maintenance.MaintenanceWindowTypes.Planned,
+		Schedule: &maintenance.Schedule{
+			Start:          "2021-03-01 00:00",
+			End:            "2021-04-30 00:00",
+			ZoneID:         "Europe/Vienna",
+			RecurrenceType: maintenance.RecurrenceTypes.Weekly,
+			Recurrence: &maintenance.Recurrence{
+				DayOfWeek:       maintenance.DayOfWeeks.Saturday.Ref(),
+				StartTime:       "22:00",
+				DurationMinutes: 120,
+			},
+		},
+	}
+}
+
+func TestWeeklyOccurrencesAcrossDST(t *testing.T) {
+	vienna, _ := time.LoadLocation("Europe/Vienna")
+	from := time.Date(2021, 3, 27, 0, 0, 0, 0, vienna)
+	occurrences, err := calendar.Occurrences(weekly().Schedule, from, from.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 2 {
+		t.Fatalf("expected 2 occurrences, got %v", occurrences)
+	}
+	for _, occurrence := range occurrences {
+		if local := occurrence.Start.In(vienna); local.Hour() != 22 || local.Weekday() != time.Saturday {
+			t.Errorf("unexpected start %s", local)
+		}
+	}
+	if offset := occurrences[1].Start.Sub(occurrences[0].Start); offset != 7*24*time.Hour-time.Hour {
+		t.Errorf("expected the switch to summer time in between, got %s", offset)
+	}
+}
+
+func TestMonthlyLastDay(t *testing.T) {
+	day := int32(31)
+	schedule := &maintenance.Schedule{
+		Start:          "2021-01-01 00:00",
+		End:            "2021-12-31 23:59",
+		RecurrenceType: maintenance.RecurrenceTypes.Monthly,
+		Recurrence:     &maintenance.Recurrence{DayOfMonth: &day, StartTime: "01:00", DurationMinutes: 30},
+	}
+	occurrences, err := calendar.Occurrences(schedule, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{28, 31, 30}
+	if len(occurrences) != len(expected) {
+		t.Fatalf("expected %d occurrences, got %v", len(expected), occurrences)
+	}
+	for i, occurrence := range occurrences {
+		if occurrence.Start.Day() != expected[i] {
+			t.Errorf("expected day %d, got %s", expected[i], occurrence.Start)
+		}
+	}
+}
+
+func TestInMaintenance(t *testing.T) {
+	window := weekly()
+	window.Scope = &maintenance.Scope{Matches: []*maintenance.Filter{{
+		Type:           maintenance.FilterTypes.Host.Ref(),
+		TagCombination: maintenance.TagCombinations.And.Ref(),
+		Tags: []*maintenance.TagInfo{
+			{Context: maintenance.Contexts.Contextless, Key: "linux"},
+			{Context: maintenance.Contexts.Contextless, Key: "prod"},
+		},
+	}}}
+	host := &calendar.Entity{ID: "HOST-0000000000000001", Type: maintenance.FilterTypes.Host, Tags: []*maintenance.TagInfo{
+		{Context: maintenance.Contexts.Contextless, Key: "linux"},
+	}}
+	vienna, _ := time.LoadLocation("Europe/Vienna")
+	saturday := time.Date(2021, 3, 6, 23, 0, 0, 0, vienna)
+	if windows, _ := calendar.InMaintenance([]*maintenance.Window{window}, host, saturday); len(windows) != 0 {
+		t.Error("host lacks the tag 'prod'")
+	}
+	host.Tags = append(host.Tags, &maintenance.TagInfo{Context: maintenance.Contexts.Contextless, Key: "prod"})
+	if windows, _ := calendar.InMaintenance([]*maintenance.Window{window}, host, saturday); len(windows) != 1 {
+		t.Error("host should be in maintenance")
+	}
+	if windows, _ := calendar.InMaintenance([]*maintenance.Window{window}, host, saturday.Add(2*time.Hour)); len(windows) != 0 {
+		t.Error("the maintenance should be over")
+	}
+}
+
+func TestICalendarRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := calendar.Export(&buf, []*maintenance.Window{weekly()}); err != nil {
+		t.Fatal(err)
+	}
+	ics := buf.String()
+	if !strings.Contains(ics, "RRULE:FREQ=WEEKLY;BYDAY=SA;UNTIL=20210429T220000Z") || !strings.Contains(ics, "DTSTART;TZID=Europe/Vienna:20210306T220000") {
+		t.Fatalf("unexpected iCalendar:\n%s", ics)
+	}
+	windows, err := calendar.NewImporter().Import(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 1 {
+		t.Fatalf("expected 1 window, got %d", len(windows))
+	}
+	expected, actual := weekly(), windows[0]
+	if actual.Name != expected.Name || actual.Suppression != expected.Suppression || actual.Schedule.RecurrenceType != expected.Schedule.RecurrenceType {
+		t.Errorf("unexpected window %+v", actual)
+	}
+	if *actual.Schedule.Recurrence.DayOfWeek != *expected.Schedule.Recurrence.DayOfWeek || actual.Schedule.Recurrence.StartTime != "22:00" || actual.Schedule.Recurrence.DurationMinutes != 120 {
+		t.Errorf("unexpected recurrence %+v", actual.Schedule.Recurrence)
+	}
+	if actual.Schedule.End != expected.Schedule.End || actual.Schedule.ZoneID != expected.Schedule.ZoneID {
+		t.Errorf("unexpected schedule %+v", actual.Schedule)
+	}
+}
+
+func TestImportRejectsUnsupportedRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{"workdays", []string{"RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"}, "unsupported BYDAY MO,TU,WE,TH,FR for FREQ DAILY"},
+		{"first monday", []string{"RRULE:FREQ=MONTHLY;BYDAY=1MO"}, "unsupported BYDAY 1MO for FREQ MONTHLY"},
+		{"weekly on two days", []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,TH"}, "only a single day is supported"},
+		{"weekly by month day", []string{"RRULE:FREQ=WEEKLY;BYMONTHDAY=3"}, "unsupported BYMONTHDAY 3 for FREQ WEEKLY"},
+		{"excluded date", []string{"RRULE:FREQ=DAILY", "EXDATE:20210308T220000Z"}, "unsupported EXDATE"},
+		{"additional date", []string{"RDATE:20210310T220000Z"}, "unsupported RDATE"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ics := strings.Join(append([]string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"SUMMARY:patching",
+				"DTSTART:20210306T220000Z",
+				"DTEND:20210307T000000Z",
+			}, append(test.lines, "END:VEVENT", "END:VCALENDAR")...), "\r\n")
+			_, err := calendar.NewImporter().Import(strings.NewReader(ics))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error '%s', got %v", test.err, err)
+			}
+		})
+	}
+}
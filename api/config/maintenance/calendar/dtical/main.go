@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/maintenance/calendar"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var apiBaseURL string
+	var apiToken string
+	var days int
+
+	command := os.Args[1]
+	flagSet := flag.NewFlagSet(os.Args[0]+" "+command, flag.ContinueOnError)
+	flagSet.StringVar(&apiBaseURL, "api-base-url", os.Getenv("DT_API_BASE_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.IntVar(&days, "days", 30, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[2:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if apiBaseURL == "" || apiToken == "" {
+		usage()
+		os.Exit(2)
+	}
+	service := maintenance.NewService(apiBaseURL, apiToken)
+
+	var err error
+	switch {
+	case command == "export" && flagSet.NArg() == 1:
+		err = export(service, flagSet.Arg(0))
+	case command == "import" && flagSet.NArg() == 1:
+		err = importWindows(service, flagSet.Arg(0))
+	case command == "overlaps" && flagSet.NArg() == 0:
+		err = overlaps(service, days)
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func fetchAll(service *maintenance.ServiceClient) ([]*maintenance.Window, error) {
+	stubList, err := service.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	windows := []*maintenance.Window{}
+	for _, stub := range stubList.Values {
+		window, err := service.Get(stub.ID)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func export(service *maintenance.ServiceClient, file string) error {
+	windows, err := fetchAll(service)
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err = calendar.Export(out, windows); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// importWindows creates the windows of the given iCalendar file, updating existing windows with the same name
+func importWindows(service *maintenance.ServiceClient, file string) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	windows, err := calendar.NewImporter().Import(in)
+	if err != nil {
+		return err
+	}
+	stubList, err := service.ListAll()
+	if err != nil {
+		return err
+	}
+	existing := map[string]string{}
+	for _, stub := range stubList.Values {
+		existing[stub.Name] = stub.ID
+	}
+	for _, window := range windows {
+		if id, found := existing[window.Name]; found {
+			window.ID = &id
+			if err = service.Update(window); err != nil {
+				return fmt.Errorf("updating '%s' failed: %s", window.Name, err.Error())
+			}
+			fmt.Printf("updated '%s' (%s)\n", window.Name, id)
+			continue
+		}
+		stub, err := service.Create(window)
+		if err != nil {
+			return fmt.Errorf("creating '%s' failed: %s", window.Name, err.Error())
+		}
+		fmt.Printf("created '%s' (%s)\n", window.Name, stub.ID)
+	}
+	return nil
+}
+
+func overlaps(service *maintenance.ServiceClient, days int) error {
+	windows, err := fetchAll(service)
+	if err != nil {
+		return err
+	}
+	from := time.Now()
+	overlaps, err := calendar.Overlaps(windows, from, from.AddDate(0, 0, days))
+	if err != nil {
+		return err
+	}
+	for _, overlap := range overlaps {
+		fmt.Println(overlap.String())
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtical export [-api-base-url <api-base-url>] [-api-token <api-token>] <ics-file>")
+	fmt.Println("       dtical import [-api-base-url <api-base-url>] [-api-token <api-token>] <ics-file>")
+	fmt.Println("       dtical overlaps [-api-base-url <api-base-url>] [-api-token <api-token>] [-days <days>]")
+	fmt.Println("  export    writes all maintenance windows as iCalendar file")
+	fmt.Println("  import    creates the events of an iCalendar file as maintenance windows, windows with the same name get updated")
+	fmt.Println("  overlaps  lists the maintenance windows which are active at the same time within the next <days> days (default: 30)")
+	fmt.Println("  Hint: you can also define the environment variables DT_API_BASE_URL and DT_API_TOKEN")
+}
@@ -0,0 +1,133 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/opt"
+)
+
+// Properties of the exported events carrying the settings of a maintenance window which iCalendar has no equivalent for
+const (
+	PropertySuppression = "X-DYNATRACE-SUPPRESSION"
+	PropertyType        = "X-DYNATRACE-TYPE"
+	PropertyScope       = "X-DYNATRACE-SCOPE"
+	PropertyEnabled     = "X-DYNATRACE-ENABLED"
+)
+
+const (
+	icalDateTime    = "20060102T150405"
+	icalDateTimeUTC = "20060102T150405Z"
+)
+
+var icalWeekdays = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// Export writes the given maintenance windows as RFC 5545 iCalendar, one VEVENT per window.
+// Recurring windows are exported as a single event with a RRULE, starting at their first occurrence.
+// Times are expressed in the time zone of the schedule: IANA zones via TZID, UTC offsets converted to UTC.
+// A monthly recurrence on day 31 becomes BYMONTHDAY=-1, other days greater than 28 can't express
+// the fallback to the last day of shorter months.
+func Export(w io.Writer, windows []*maintenance.Window) error {
+	lw := &lineWriter{w: w}
+	lw.write("BEGIN:VCALENDAR")
+	lw.write("VERSION:2.0")
+	lw.write("PRODID:-//dtcookie//dynatrace maintenance windows//EN")
+	lw.write("CALSCALE:GREGORIAN")
+	stamp := time.Now().UTC().Format(icalDateTimeUTC)
+	for _, window := range windows {
+		if err := exportWindow(lw, window, stamp); err != nil {
+			return fmt.Errorf("%s: %s", window.Name, err.Error())
+		}
+	}
+	lw.write("END:VCALENDAR")
+	return lw.err
+}
+
+func exportWindow(lw *lineWriter, window *maintenance.Window, stamp string) error {
+	if window.Schedule == nil {
+		return fmt.Errorf("the window has no schedule")
+	}
+	schedule := window.Schedule
+	start, end, location, err := Validity(schedule)
+	if err != nil {
+		return err
+	}
+
+	var first Occurrence
+	var rrule string
+	if schedule.RecurrenceType == maintenance.RecurrenceTypes.Once {
+		first = Occurrence{Start: start, End: end}
+	} else {
+		occurrences, err := Occurrences(schedule, start, end.Add(time.Duration(schedule.Recurrence.DurationMinutes)*time.Minute))
+		if err != nil {
+			return err
+		}
+		if len(occurrences) == 0 {
+			return fmt.Errorf("the schedule has no occurrences")
+		}
+		first = occurrences[0]
+		until := "UNTIL=" + end.UTC().Format(icalDateTimeUTC)
+		switch schedule.RecurrenceType {
+		case maintenance.RecurrenceTypes.Daily:
+			rrule = "FREQ=DAILY;" + until
+		case maintenance.RecurrenceTypes.Weekly:
+			rrule = "FREQ=WEEKLY;BYDAY=" + icalWeekdays[first.Start.Weekday()] + ";" + until
+		case maintenance.RecurrenceTypes.Monthly:
+			dayOfMonth := int(*schedule.Recurrence.DayOfMonth)
+			if dayOfMonth == 31 {
+				dayOfMonth = -1
+			}
+			rrule = fmt.Sprintf("FREQ=MONTHLY;BYMONTHDAY=%d;%s", dayOfMonth, until)
+		}
+	}
+
+	uid := opt.String(window.ID)
+	if uid == "" {
+		uid = window.Name
+	}
+	lw.write("BEGIN:VEVENT")
+	lw.write("UID:" + escape(uid) + "@dynatrace")
+	lw.write("DTSTAMP:" + stamp)
+	lw.write("SUMMARY:" + escape(window.Name))
+	if len(window.Description) > 0 {
+		lw.write("DESCRIPTION:" + escape(window.Description))
+	}
+	lw.write("DTSTART" + formatTime(first.Start, location))
+	lw.write("DTEND" + formatTime(first.End, location))
+	if len(rrule) > 0 {
+		lw.write("RRULE:" + rrule)
+	}
+	lw.write(PropertySuppression + ":" + string(window.Suppression))
+	lw.write(PropertyType + ":" + string(window.Type))
+	lw.write(fmt.Sprintf("%s:%t", PropertyEnabled, window.Enabled))
+	if window.Scope != nil {
+		data, err := json.Marshal(window.Scope)
+		if err != nil {
+			return err
+		}
+		lw.write(PropertyScope + ":" + escape(string(data)))
+	}
+	lw.write("END:VEVENT")
+	return nil
+}
+
+// formatTime renders the parameters and value of a DATE-TIME property, including the leading separator
+func formatTime(t time.Time, location *time.Location) string {
+	name := location.String()
+	if location == time.UTC || strings.HasPrefix(name, "UTC") || strings.HasPrefix(name, "GMT") {
+		return ":" + t.UTC().Format(icalDateTimeUTC)
+	}
+	return ";TZID=" + name + ":" + t.In(location).Format(icalDateTime)
+}
@@ -0,0 +1,116 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// property is a single content line of an iCalendar stream, e.g. `DTSTART;TZID=Europe/Vienna:20210301T220000`
+type property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// escape escapes TEXT values according to RFC 5545, section 3.3.11
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// unescape reverts escape
+func unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				sb.WriteByte('\n')
+			default:
+				sb.WriteByte(s[i])
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// lineWriter writes content lines folded at 75 octets, terminated by CRLF
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (me *lineWriter) write(line string) {
+	if me.err != nil {
+		return
+	}
+	var sb strings.Builder
+	for len(line) > 75 {
+		cut := 75
+		// don't split multi-byte UTF-8 sequences
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	sb.WriteString(line)
+	sb.WriteString("\r\n")
+	_, me.err = io.WriteString(me.w, sb.String())
+}
+
+// readProperties unfolds the content lines of an iCalendar stream and splits them into name, parameters and value
+func readProperties(r io.Reader) ([]*property, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lines := []string{}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	properties := []*property{}
+	for _, line := range lines {
+		properties = append(properties, parseProperty(line))
+	}
+	return properties, nil
+}
+
+func parseProperty(line string) *property {
+	prop := &property{Params: map[string]string{}}
+	// the value starts after the first colon which isn't within a quoted parameter value
+	quoted := false
+	split := len(line)
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			split = i
+			break
+		}
+	}
+	head := line[:split]
+	if split < len(line) {
+		prop.Value = line[split+1:]
+	}
+	parts := strings.Split(head, ";")
+	prop.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if idx := strings.Index(param, "="); idx > 0 {
+			prop.Params[strings.ToUpper(param[:idx])] = strings.Trim(param[idx+1:], `"`)
+		}
+	}
+	return prop
+}
@@ -0,0 +1,301 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+)
+
+// Importer converts the events of an iCalendar stream into maintenance windows
+type Importer struct {
+	// Suppression is used for events without X-DYNATRACE-SUPPRESSION property
+	Suppression maintenance.Suppression
+	// Type is used for events without X-DYNATRACE-TYPE property
+	Type maintenance.WindowType
+	// Horizon is the validity period of events recurring without end
+	Horizon time.Duration
+}
+
+// NewImporter creates an Importer for planned maintenance windows which don't alert, limiting endless recurrences to one year
+func NewImporter() *Importer {
+	return &Importer{
+		Suppression: maintenance.Suppressions.DetectProblemsDontAlert,
+		Type:        maintenance.MaintenanceWindowTypes.Planned,
+		Horizon:     365 * 24 * time.Hour,
+	}
+}
+
+// Import reads the VEVENTs of the given iCalendar stream.
+// Supported are single events and events recurring DAILY, WEEKLY on a single day (BYDAY) or MONTHLY on a single day (BYMONTHDAY),
+// with an INTERVAL of 1. Other events, including events with EXDATE, RDATE or EXRULE, are rejected.
+func (me *Importer) Import(r io.Reader) ([]*maintenance.Window, error) {
+	properties, err := readProperties(r)
+	if err != nil {
+		return nil, err
+	}
+	windows := []*maintenance.Window{}
+	var event map[string]*property
+	depth := 0
+	for _, prop := range properties {
+		switch {
+		case prop.Name == "BEGIN" && strings.ToUpper(prop.Value) == "VEVENT":
+			event = map[string]*property{}
+		case prop.Name == "END" && strings.ToUpper(prop.Value) == "VEVENT":
+			if event == nil {
+				return nil, errors.New("END:VEVENT without BEGIN:VEVENT")
+			}
+			window, err := me.convert(event)
+			if err != nil {
+				summary := ""
+				if p, found := event["SUMMARY"]; found {
+					summary = unescape(p.Value)
+				}
+				return nil, fmt.Errorf("event '%s': %s", summary, err.Error())
+			}
+			windows = append(windows, window)
+			event = nil
+		case prop.Name == "BEGIN" && event != nil:
+			// nested components like VALARM are ignored
+			depth++
+		case prop.Name == "END" && event != nil && depth > 0:
+			depth--
+		case event != nil && depth == 0:
+			event[prop.Name] = prop
+		}
+	}
+	return windows, nil
+}
+
+func (me *Importer) convert(event map[string]*property) (*maintenance.Window, error) {
+	dtstart, found := event["DTSTART"]
+	if !found {
+		return nil, errors.New("DTSTART is missing")
+	}
+	start, location, err := parseTime(dtstart)
+	if err != nil {
+		return nil, err
+	}
+	var end time.Time
+	if dtend, found := event["DTEND"]; found {
+		if end, _, err = parseTime(dtend); err != nil {
+			return nil, err
+		}
+	} else if duration, found := event["DURATION"]; found {
+		var d time.Duration
+		if d, err = parseDuration(duration.Value); err != nil {
+			return nil, err
+		}
+		end = start.Add(d)
+	} else if dtstart.Params["VALUE"] == "DATE" {
+		end = start.AddDate(0, 0, 1)
+	} else {
+		return nil, errors.New("neither DTEND nor DURATION are specified")
+	}
+	if !end.After(start) {
+		return nil, errors.New("the event ends before it starts")
+	}
+
+	window := &maintenance.Window{
+		Suppression: me.Suppression,
+		Type:        me.Type,
+		Enabled:     true,
+		Schedule:    &maintenance.Schedule{ZoneID: location.String()},
+	}
+	if p, found := event["SUMMARY"]; found {
+		window.Name = unescape(p.Value)
+	}
+	if p, found := event["DESCRIPTION"]; found {
+		window.Description = unescape(p.Value)
+	}
+	if p, found := event[PropertySuppression]; found {
+		window.Suppression = maintenance.Suppression(p.Value)
+	}
+	if p, found := event[PropertyType]; found {
+		window.Type = maintenance.WindowType(p.Value)
+	}
+	if p, found := event[PropertyEnabled]; found {
+		window.Enabled = strings.ToLower(p.Value) != "false"
+	}
+	if p, found := event[PropertyScope]; found {
+		if err = json.Unmarshal([]byte(unescape(p.Value)), &window.Scope); err != nil {
+			return nil, fmt.Errorf("%s: %s", PropertyScope, err.Error())
+		}
+	}
+
+	for _, unsupported := range []string{"EXDATE", "RDATE", "EXRULE"} {
+		if _, found := event[unsupported]; found {
+			return nil, fmt.Errorf("unsupported %s", unsupported)
+		}
+	}
+	rrule, found := event["RRULE"]
+	if !found {
+		window.Schedule.RecurrenceType = maintenance.RecurrenceTypes.Once
+		window.Schedule.Start = start.Format(DateTimeLayout)
+		window.Schedule.End = end.In(location).Format(DateTimeLayout)
+		return window, nil
+	}
+	if err = me.recurrence(window.Schedule, rrule.Value, start, end, location); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+func (me *Importer) recurrence(schedule *maintenance.Schedule, rrule string, start time.Time, end time.Time, location *time.Location) error {
+	parts := map[string]string{}
+	for _, part := range strings.Split(rrule, ";") {
+		if idx := strings.Index(part, "="); idx > 0 {
+			parts[strings.ToUpper(part[:idx])] = part[idx+1:]
+		}
+	}
+	if interval, found := parts["INTERVAL"]; found && interval != "1" {
+		return fmt.Errorf("unsupported INTERVAL %s", interval)
+	}
+	for _, unsupported := range []string{"BYSETPOS", "BYYEARDAY", "BYWEEKNO", "BYMONTH", "BYHOUR", "BYMINUTE", "BYSECOND"} {
+		if _, found := parts[unsupported]; found {
+			return fmt.Errorf("unsupported %s", unsupported)
+		}
+	}
+	freq := strings.ToUpper(parts["FREQ"])
+	// BYDAY and BYMONTHDAY restrict or expand the occurrences, they can only get mapped for WEEKLY and MONTHLY respectively
+	if byDay, found := parts["BYDAY"]; found && freq != "WEEKLY" {
+		return fmt.Errorf("unsupported BYDAY %s for FREQ %s", byDay, freq)
+	}
+	if byMonthDay, found := parts["BYMONTHDAY"]; found && freq != "MONTHLY" {
+		return fmt.Errorf("unsupported BYMONTHDAY %s for FREQ %s", byMonthDay, freq)
+	}
+
+	recurrence := &maintenance.Recurrence{
+		StartTime:       start.Format(TimeLayout),
+		DurationMinutes: int32(end.Sub(start) / time.Minute),
+	}
+	schedule.Recurrence = recurrence
+	switch freq {
+	case "DAILY":
+		schedule.RecurrenceType = maintenance.RecurrenceTypes.Daily
+	case "WEEKLY":
+		schedule.RecurrenceType = maintenance.RecurrenceTypes.Weekly
+		weekday := start.Weekday()
+		if byDay, found := parts["BYDAY"]; found {
+			if strings.Contains(byDay, ",") {
+				return fmt.Errorf("unsupported BYDAY %s, only a single day is supported", byDay)
+			}
+			found = false
+			for wd, name := range icalWeekdays {
+				if strings.ToUpper(byDay) == name {
+					weekday, found = wd, true
+				}
+			}
+			if !found {
+				return fmt.Errorf("unsupported BYDAY %s", byDay)
+			}
+		}
+		recurrence.DayOfWeek = DayOfWeek(weekday).Ref()
+	case "MONTHLY":
+		schedule.RecurrenceType = maintenance.RecurrenceTypes.Monthly
+		dayOfMonth := int32(start.Day())
+		if byMonthDay, found := parts["BYMONTHDAY"]; found {
+			day, err := strconv.Atoi(byMonthDay)
+			if err != nil || day == 0 || day < -1 || day > 31 {
+				return fmt.Errorf("unsupported BYMONTHDAY %s", byMonthDay)
+			}
+			if day == -1 {
+				day = 31
+			}
+			dayOfMonth = int32(day)
+		}
+		recurrence.DayOfMonth = &dayOfMonth
+	default:
+		return fmt.Errorf("unsupported FREQ %s", parts["FREQ"])
+	}
+
+	validUntil := start.Add(me.Horizon)
+	if until, found := parts["UNTIL"]; found {
+		t, _, err := parseTime(&property{Value: until, Params: map[string]string{}})
+		if err != nil {
+			return err
+		}
+		validUntil = t
+	} else if count, found := parts["COUNT"]; found {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid COUNT %s", count)
+		}
+		// no recurrence supported takes longer than 31 days per occurrence
+		limit := start.AddDate(0, 0, 31*n)
+		schedule.Start = start.Format(DateTimeLayout)
+		schedule.End = limit.In(location).Format(DateTimeLayout)
+		occurrences, err := Occurrences(schedule, start, limit)
+		if err != nil {
+			return err
+		}
+		validUntil = occurrences[n-1].Start
+	}
+	schedule.Start = start.Format(DateTimeLayout)
+	schedule.End = validUntil.In(location).Format(DateTimeLayout)
+	return nil
+}
+
+// parseTime parses the value of a DATE or DATE-TIME property and returns the time zone it's expressed in.
+// Floating times are interpreted as UTC.
+func parseTime(prop *property) (time.Time, *time.Location, error) {
+	location := time.UTC
+	if tzid, found := prop.Params["TZID"]; found {
+		var err error
+		if location, err = Location(tzid); err != nil {
+			return time.Time{}, nil, fmt.Errorf("unknown TZID %s", tzid)
+		}
+	}
+	value := prop.Value
+	var t time.Time
+	var err error
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(icalDateTimeUTC, value)
+		location = time.UTC
+	case len(value) == 8:
+		t, err = time.ParseInLocation("20060102", value, location)
+	default:
+		t, err = time.ParseInLocation(icalDateTime, value, location)
+	}
+	if err != nil {
+		return t, nil, fmt.Errorf("invalid date or time '%s'", value)
+	}
+	return t, location, nil
+}
+
+// parseDuration parses RFC 5545 durations like `PT1H30M` or `P1D`
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	if strings.HasPrefix(value, "-") || s == value {
+		return 0, fmt.Errorf("invalid duration '%s'", value)
+	}
+	var d time.Duration
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+	number := ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 'T':
+		case c >= '0' && c <= '9':
+			number += string(c)
+		default:
+			unit, found := units[c]
+			n, err := strconv.Atoi(number)
+			if !found || err != nil {
+				return 0, fmt.Errorf("invalid duration '%s'", value)
+			}
+			d += time.Duration(n) * unit
+			number = ""
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration '%s'", value)
+	}
+	return d, nil
+}
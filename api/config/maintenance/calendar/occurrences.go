@@ -0,0 +1,210 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+)
+
+// DateTimeLayout is the format of the start and end of a maintenance.Schedule
+const DateTimeLayout = "2006-01-02 15:04"
+
+// TimeLayout is the format of the start time of a maintenance.Recurrence
+const TimeLayout = "15:04"
+
+// Occurrence is a single period during which a maintenance window is active
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Overlaps returns true if the occurrence has at least one instant in common with the period from start (inclusive) to end (exclusive)
+func (me Occurrence) Overlaps(start time.Time, end time.Time) bool {
+	return me.Start.Before(end) && start.Before(me.End)
+}
+
+// Contains returns true if the given point in time lies within the occurrence. The end is exclusive.
+func (me Occurrence) Contains(t time.Time) bool {
+	return !t.Before(me.Start) && t.Before(me.End)
+}
+
+func (me Occurrence) String() string {
+	return me.Start.Format(time.RFC3339) + " - " + me.End.Format(time.RFC3339)
+}
+
+var offsetPattern = regexp.MustCompile(`^(?:UTC|GMT)([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// Location resolves the ZoneID of a maintenance.Schedule.
+// Supported are UTC offsets like `UTC+01:00` and the names of the IANA Time Zone Database like `Europe/Vienna`.
+// An empty ZoneID means UTC.
+func Location(zoneID string) (*time.Location, error) {
+	zoneID = strings.TrimSpace(zoneID)
+	if zoneID == "" || zoneID == "UTC" || zoneID == "GMT" {
+		return time.UTC, nil
+	}
+	if m := offsetPattern.FindStringSubmatch(zoneID); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes := 0
+		if m[3] != "" {
+			minutes, _ = strconv.Atoi(m[3])
+		}
+		offset := hours*3600 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(zoneID, offset), nil
+	}
+	return time.LoadLocation(zoneID)
+}
+
+// Validity returns the period the given schedule is valid for
+func Validity(schedule *maintenance.Schedule) (time.Time, time.Time, *time.Location, error) {
+	var err error
+	var location *time.Location
+	var start, end time.Time
+
+	if location, err = Location(schedule.ZoneID); err != nil {
+		return start, end, nil, err
+	}
+	if start, err = time.ParseInLocation(DateTimeLayout, schedule.Start, location); err != nil {
+		return start, end, nil, fmt.Errorf("invalid start '%s': %s", schedule.Start, err.Error())
+	}
+	if end, err = time.ParseInLocation(DateTimeLayout, schedule.End, location); err != nil {
+		return start, end, nil, fmt.Errorf("invalid end '%s': %s", schedule.End, err.Error())
+	}
+	if end.Before(start) {
+		return start, end, nil, errors.New("the end of the schedule lies before its start")
+	}
+	return start, end, location, nil
+}
+
+// Occurrences computes the periods of the given schedule which overlap with the period from `from` (inclusive) to `to` (exclusive).
+// Recurring occurrences start within the validity period of the schedule, they're not cut off at its end.
+func Occurrences(schedule *maintenance.Schedule, from time.Time, to time.Time) ([]Occurrence, error) {
+	start, end, location, err := Validity(schedule)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := []Occurrence{}
+	if schedule.RecurrenceType == maintenance.RecurrenceTypes.Once {
+		if occurrence := (Occurrence{Start: start, End: end}); occurrence.Overlaps(from, to) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return occurrences, nil
+	}
+
+	recurrence := schedule.Recurrence
+	if recurrence == nil {
+		return nil, fmt.Errorf("a schedule with recurrence type %s requires a recurrence", schedule.RecurrenceType)
+	}
+	startTime, err := time.Parse(TimeLayout, recurrence.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time '%s': %s", recurrence.StartTime, err.Error())
+	}
+	duration := time.Duration(recurrence.DurationMinutes) * time.Minute
+	if duration <= 0 {
+		return nil, errors.New("the duration of a recurrence must be positive")
+	}
+
+	var matches func(day time.Time) bool
+	switch schedule.RecurrenceType {
+	case maintenance.RecurrenceTypes.Daily:
+		matches = func(day time.Time) bool { return true }
+	case maintenance.RecurrenceTypes.Weekly:
+		if recurrence.DayOfWeek == nil {
+			return nil, errors.New("a weekly recurrence requires a day of week")
+		}
+		weekday, err := Weekday(*recurrence.DayOfWeek)
+		if err != nil {
+			return nil, err
+		}
+		matches = func(day time.Time) bool { return day.Weekday() == weekday }
+	case maintenance.RecurrenceTypes.Monthly:
+		if recurrence.DayOfMonth == nil || *recurrence.DayOfMonth < 1 || *recurrence.DayOfMonth > 31 {
+			return nil, errors.New("a monthly recurrence requires a day of month between 1 and 31")
+		}
+		dayOfMonth := int(*recurrence.DayOfMonth)
+		matches = func(day time.Time) bool { return day.Day() == monthDay(day, dayOfMonth) }
+	default:
+		return nil, fmt.Errorf("unsupported recurrence type '%s'", schedule.RecurrenceType)
+	}
+
+	// occurrences starting earlier than one duration before `from` can't overlap with the requested period
+	first := start
+	if lower := from.Add(-duration); lower.After(first) {
+		first = lower.In(location)
+	}
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, location); !day.After(end) && day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !matches(day) {
+			continue
+		}
+		occurrenceStart := time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, location)
+		if occurrenceStart.Before(start) || occurrenceStart.After(end) {
+			continue
+		}
+		if occurrence := (Occurrence{Start: occurrenceStart, End: occurrenceStart.Add(duration)}); occurrence.Overlaps(from, to) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences, nil
+}
+
+// monthDay returns the given day of month, limited to the last day of the month the given time lies in
+func monthDay(t time.Time, dayOfMonth int) int {
+	last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if dayOfMonth > last {
+		return last
+	}
+	return dayOfMonth
+}
+
+var weekdays = map[maintenance.DayOfWeek]time.Weekday{
+	maintenance.DayOfWeeks.Sunday:    time.Sunday,
+	maintenance.DayOfWeeks.Monday:    time.Monday,
+	maintenance.DayOfWeeks.Tuesday:   time.Tuesday,
+	maintenance.DayOfWeeks.Wednesday: time.Wednesday,
+	maintenance.DayOfWeeks.Thursday:  time.Thursday,
+	maintenance.DayOfWeeks.Friday:    time.Friday,
+	maintenance.DayOfWeeks.Saturday:  time.Saturday,
+}
+
+// Weekday converts the given maintenance.DayOfWeek
+func Weekday(dayOfWeek maintenance.DayOfWeek) (time.Weekday, error) {
+	if weekday, found := weekdays[dayOfWeek]; found {
+		return weekday, nil
+	}
+	return time.Sunday, fmt.Errorf("unknown day of week '%s'", dayOfWeek)
+}
+
+// DayOfWeek converts the given time.Weekday
+func DayOfWeek(weekday time.Weekday) maintenance.DayOfWeek {
+	for dayOfWeek, wd := range weekdays {
+		if wd == weekday {
+			return dayOfWeek
+		}
+	}
+	return ""
+}
+
+// Active returns the occurrence of the given window the point in time lies in.
+// Disabled windows are never active.
+func Active(window *maintenance.Window, t time.Time) (*Occurrence, error) {
+	if !window.Enabled || window.Schedule == nil {
+		return nil, nil
+	}
+	occurrences, err := Occurrences(window.Schedule, t, t.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	for _, occurrence := range occurrences {
+		if occurrence.Contains(t) {
+			return &occurrence, nil
+		}
+	}
+	return nil, nil
+}
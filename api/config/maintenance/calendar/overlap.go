@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+)
+
+// Overlap is a period during which two maintenance windows are active at the same time
+type Overlap struct {
+	A     *maintenance.Window
+	B     *maintenance.Window
+	Start time.Time
+	End   time.Time
+}
+
+func (me *Overlap) String() string {
+	return fmt.Sprintf("'%s' and '%s': %s - %s", me.A.Name, me.B.Name, me.Start.Format(time.RFC3339), me.End.Format(time.RFC3339))
+}
+
+// Overlaps determines the periods within `from` and `to` during which enabled windows are active at the same time.
+// Only the schedules are compared. Whether the scopes of two windows have entities in common depends on the
+// monitored environment; use InScope on the results to check that for specific entities.
+func Overlaps(windows []*maintenance.Window, from time.Time, to time.Time) ([]*Overlap, error) {
+	occurrences := make([][]Occurrence, len(windows))
+	for i, window := range windows {
+		if !window.Enabled || window.Schedule == nil {
+			continue
+		}
+		var err error
+		if occurrences[i], err = Occurrences(window.Schedule, from, to); err != nil {
+			return nil, fmt.Errorf("%s: %s", window.Name, err.Error())
+		}
+	}
+	overlaps := []*Overlap{}
+	for i := range windows {
+		for j := i + 1; j < len(windows); j++ {
+			for _, a := range occurrences[i] {
+				for _, b := range occurrences[j] {
+					if !a.Overlaps(b.Start, b.End) {
+						continue
+					}
+					overlap := &Overlap{A: windows[i], B: windows[j], Start: a.Start, End: a.End}
+					if b.Start.After(overlap.Start) {
+						overlap.Start = b.Start
+					}
+					if b.End.Before(overlap.End) {
+						overlap.End = b.End
+					}
+					overlaps = append(overlaps, overlap)
+				}
+			}
+		}
+	}
+	return overlaps, nil
+}
@@ -0,0 +1,99 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/opt"
+)
+
+// Entity is the information about a monitored entity required to decide whether it's within the scope of a maintenance window
+type Entity struct {
+	ID              string                 // the ID of the entity, e.g. `HOST-0123456789ABCDEF`
+	Type            maintenance.FilterType // the type of the entity, e.g. `HOST`
+	Tags            []*maintenance.TagInfo
+	ManagementZones []string // the IDs of the management zones the entity belongs to
+}
+
+// InScope returns true if the given entity lies within the scope.
+// A missing or empty scope covers the whole environment.
+func InScope(scope *maintenance.Scope, entity *Entity) bool {
+	if scope == nil || (len(scope.Entities) == 0 && len(scope.Matches) == 0) {
+		return true
+	}
+	for _, id := range scope.Entities {
+		if id == entity.ID {
+			return true
+		}
+	}
+	for _, filter := range scope.Matches {
+		if Matches(filter, entity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true if the given entity satisfies the filter
+func Matches(filter *maintenance.Filter, entity *Entity) bool {
+	if filter.Type != nil && *filter.Type != entity.Type {
+		return false
+	}
+	if mzID := opt.String(filter.MzID); len(mzID) > 0 {
+		member := false
+		for _, id := range entity.ManagementZones {
+			if id == mzID {
+				member = true
+				break
+			}
+		}
+		if !member {
+			return false
+		}
+	}
+	if len(filter.Tags) == 0 {
+		return true
+	}
+	all := filter.TagCombination != nil && *filter.TagCombination == maintenance.TagCombinations.And
+	for _, required := range filter.Tags {
+		found := hasTag(entity, required)
+		if all && !found {
+			return false
+		}
+		if !all && found {
+			return true
+		}
+	}
+	return all
+}
+
+// hasTag returns true if the entity carries the given tag. A tag without value matches any value.
+func hasTag(entity *Entity, required *maintenance.TagInfo) bool {
+	for _, tag := range entity.Tags {
+		if tag.Context != required.Context || tag.Key != required.Key {
+			continue
+		}
+		if required.Value == nil || opt.String(tag.Value) == *required.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// InMaintenance returns the windows which put the given entity into maintenance at the given point in time
+func InMaintenance(windows []*maintenance.Window, entity *Entity, t time.Time) ([]*maintenance.Window, error) {
+	result := []*maintenance.Window{}
+	for _, window := range windows {
+		if !InScope(window.Scope, entity) {
+			continue
+		}
+		occurrence, err := Active(window, t)
+		if err != nil {
+			return nil, err
+		}
+		if occurrence != nil {
+			result = append(result, window)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,229 @@
+package coverage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+)
+
+// Zone summarizes which entities a management zone covers
+type Zone struct {
+	ID     string         `json:"id,omitempty"`
+	Name   string         `json:"name"`
+	Total  int            `json:"total"`  // the number of entities covered
+	Counts map[string]int `json:"counts"` // the number of entities covered, by entity type
+	// Incomplete is set if the zone also has dimensional or entity selector based rules, or rules propagating
+	// in a way that can't get evaluated against the entities. The zone may therefore cover more entities than reported.
+	Incomplete bool `json:"incomplete,omitempty"`
+
+	entities []string
+}
+
+// Entity is an entity which is covered by no or by several management zones
+type Entity struct {
+	ID    string   `json:"id"`
+	Type  string   `json:"type"`
+	Name  string   `json:"name"`
+	Zones []string `json:"zones,omitempty"`
+}
+
+// Report is the outcome of analyzing the coverage of management zones
+type Report struct {
+	Entities    int        `json:"entities"`    // the number of entities analyzed
+	Zones       []*Zone    `json:"zones"`       // sorted by name
+	Unassigned  []*Entity  `json:"unassigned"`  // entities covered by no zone, except for the ones an incomplete zone may cover
+	Overlapping []*Entity  `json:"overlapping"` // entities covered by more than one zone
+	Identical   [][]string `json:"identical"`   // groups of zones covering exactly the same entities
+	Empty       []string   `json:"empty"`       // zones whose rules match no entity
+}
+
+// propagations lists for every propagation type the paths to the entities a rule propagates to.
+// A path is the sequence of entity types to step through along placement relationships.
+var propagations = map[managementzones.PropagationType][][]string{
+	managementzones.PropagationTypes.HostToProcessGroupInstance: {{evaluator.EntityTypes.ProcessGroupInstance}},
+	managementzones.PropagationTypes.ProcessGroupToHost: {
+		{evaluator.EntityTypes.Host},
+		{evaluator.EntityTypes.ProcessGroupInstance, evaluator.EntityTypes.Host},
+	},
+	managementzones.PropagationTypes.ProcessGroupToService: {{evaluator.EntityTypes.Service}},
+	managementzones.PropagationTypes.ServiceToProcessGroupLike: {
+		{evaluator.EntityTypes.ProcessGroup},
+		{evaluator.EntityTypes.ProcessGroupInstance},
+	},
+	managementzones.PropagationTypes.ServiceToHostLike: {
+		{evaluator.EntityTypes.Host},
+		{evaluator.EntityTypes.ProcessGroupInstance, evaluator.EntityTypes.Host},
+		{evaluator.EntityTypes.ProcessGroup, evaluator.EntityTypes.Host},
+	},
+}
+
+// targets are the entity types a propagation type reaches, used to tell which entities an incomplete zone may cover
+var targets = map[managementzones.PropagationType][]string{
+	managementzones.PropagationTypes.AzureToPg:                       {evaluator.EntityTypes.ProcessGroup},
+	managementzones.PropagationTypes.AzureToService:                  {evaluator.EntityTypes.Service},
+	managementzones.PropagationTypes.CustomDeviceGroupToCustomDevice: {"CUSTOM_DEVICE"},
+	managementzones.PropagationTypes.HostToProcessGroupInstance:      {evaluator.EntityTypes.ProcessGroupInstance},
+	managementzones.PropagationTypes.ProcessGroupToHost:              {evaluator.EntityTypes.Host},
+	managementzones.PropagationTypes.ProcessGroupToService:           {evaluator.EntityTypes.Service},
+	managementzones.PropagationTypes.ServiceToProcessGroupLike:       {evaluator.EntityTypes.ProcessGroup, evaluator.EntityTypes.ProcessGroupInstance},
+	managementzones.PropagationTypes.ServiceToHostLike:               {evaluator.EntityTypes.Host},
+}
+
+// topology provides the entities related to an entity along placement relationships, in either direction
+type topology struct {
+	entities map[string]*evaluator.Entity
+	related  map[string][]string
+	known    bool // whether the entities carry relationships at all
+}
+
+func newTopology(entities []*evaluator.Entity) *topology {
+	t := &topology{entities: map[string]*evaluator.Entity{}, related: map[string][]string{}}
+	for _, entity := range entities {
+		t.entities[entity.ID] = entity
+		if entity.Relationships != nil {
+			t.known = true
+		}
+		for relationship, ids := range entity.Relationships {
+			if !evaluator.Placement[relationship] {
+				continue
+			}
+			for _, id := range ids {
+				t.related[entity.ID] = append(t.related[entity.ID], id)
+				t.related[id] = append(t.related[id], entity.ID)
+			}
+		}
+	}
+	return t
+}
+
+// propagate returns the IDs of the entities a rule matching the given entity propagates to
+func (me *topology) propagate(id string, propagationType managementzones.PropagationType) []string {
+	result := []string{}
+	for _, path := range propagations[propagationType] {
+		frontier := []string{id}
+		for _, entityType := range path {
+			next := []string{}
+			seen := map[string]bool{}
+			for _, current := range frontier {
+				for _, other := range me.related[current] {
+					if entity := me.entities[other]; entity != nil && entity.Type == entityType && !seen[other] {
+						seen[other] = true
+						next = append(next, other)
+					}
+				}
+			}
+			frontier = next
+		}
+		result = append(result, frontier...)
+	}
+	return result
+}
+
+// Analyze evaluates the condition based rules of the given management zones against the given entities.
+// Rules propagate to related entities along the Relationships of the entities. Zones with propagation
+// types that can't get evaluated, e.g. because the entities lack relationships, are reported as incomplete.
+// Related tags and names the entities lack, e.g. the `HOST_TAGS` of a service, get derived from the relationships, see evaluator.Relate.
+func Analyze(mzs []*managementzones.ManagementZone, entities []*evaluator.Entity) *Report {
+	evaluator.Relate(entities)
+	report := &Report{
+		Entities:    len(entities),
+		Zones:       []*Zone{},
+		Unassigned:  []*Entity{},
+		Overlapping: []*Entity{},
+		Identical:   [][]string{},
+		Empty:       []string{},
+	}
+	topo := newTopology(entities)
+	zonesOf := map[string][]string{}
+	undetermined := map[string]bool{} // the entity types incomplete zones may cover
+	for _, mz := range mzs {
+		zone := &Zone{Name: mz.Name, Counts: map[string]int{}, entities: []string{}}
+		if mz.ID != nil {
+			zone.ID = *mz.ID
+		}
+		zone.Incomplete = len(mz.DimensionalRules) > 0 || len(mz.EntitySelectorBasedRules) > 0
+		rules := evaluator.ManagementZoneRules(mz)
+		ruleOf := map[*evaluator.Rule]*managementzones.Rule{}
+		for idx, rule := range rules {
+			ruleOf[rule] = mz.Rules[idx]
+			if !rule.Enabled {
+				continue
+			}
+			for _, propagationType := range mz.Rules[idx].PropagationTypes {
+				if _, supported := propagations[propagationType]; !supported || !topo.known {
+					zone.Incomplete = true
+					for _, entityType := range targets[propagationType] {
+						undetermined[entityType] = true
+					}
+				}
+			}
+		}
+		covered := map[string]bool{}
+		cover := func(id string) {
+			if covered[id] {
+				return
+			}
+			covered[id] = true
+			zone.Total++
+			zone.Counts[topo.entities[id].Type]++
+			zone.entities = append(zone.entities, id)
+			zonesOf[id] = append(zonesOf[id], mz.Name)
+		}
+		propagated := []string{}
+		for _, match := range evaluator.Evaluate(rules, entities) {
+			for _, result := range match.Rules {
+				if !result.Matches {
+					continue
+				}
+				cover(match.Entity.ID)
+				for _, propagationType := range ruleOf[result.Rule].PropagationTypes {
+					propagated = append(propagated, topo.propagate(match.Entity.ID, propagationType)...)
+				}
+			}
+		}
+		for _, id := range propagated {
+			cover(id)
+		}
+		report.Zones = append(report.Zones, zone)
+		if zone.Total == 0 {
+			report.Empty = append(report.Empty, zone.Name)
+		}
+	}
+	sort.Slice(report.Zones, func(i, j int) bool { return report.Zones[i].Name < report.Zones[j].Name })
+	sort.Strings(report.Empty)
+
+	for _, entity := range entities {
+		zones := zonesOf[entity.ID]
+		sort.Strings(zones)
+		switch {
+		case len(zones) == 0 && !undetermined[entity.Type]:
+			report.Unassigned = append(report.Unassigned, &Entity{ID: entity.ID, Type: entity.Type, Name: entity.Name})
+		case len(zones) > 1:
+			report.Overlapping = append(report.Overlapping, &Entity{ID: entity.ID, Type: entity.Type, Name: entity.Name, Zones: zones})
+		}
+	}
+
+	// zones matching nothing are already reported as empty and not considered identical
+	groups := map[string][]string{}
+	keys := []string{}
+	for _, zone := range report.Zones {
+		if zone.Total == 0 {
+			continue
+		}
+		ids := append([]string{}, zone.entities...)
+		sort.Strings(ids)
+		key := strings.Join(ids, ",")
+		if _, found := groups[key]; !found {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], zone.Name)
+	}
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			report.Identical = append(report.Identical, groups[key])
+		}
+	}
+	return report
+}
@@ -0,0 +1,145 @@
+package coverage_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison/tag"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/dsl"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	"github.com/dtcookie/dynatrace/api/config/managementzones/coverage"
+)
+
+func zone(t *testing.T, name string, ruleType managementzones.RuleType, conditions string) *managementzones.ManagementZone {
+	parsed, err := dsl.Parse(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &managementzones.ManagementZone{Name: name, Rules: []*managementzones.Rule{{Type: ruleType, Enabled: true, Conditions: parsed}}}
+}
+
+func TestAnalyze(t *testing.T) {
+	mzs := []*managementzones.ManagementZone{
+		zone(t, "web", managementzones.RuleTypes.Host, `HOST_NAME BEGINS_WITH web`),
+		zone(t, "frontend", managementzones.RuleTypes.Host, `HOST_NAME CONTAINS web-`),
+		zone(t, "shop", managementzones.RuleTypes.Service, `SERVICE_NAME CONTAINS shop`),
+		zone(t, "legacy", managementzones.RuleTypes.Host, `HOST_NAME BEGINS_WITH mainframe`),
+	}
+	entities := []*evaluator.Entity{
+		{ID: "HOST-1", Type: "HOST", Name: "web-01"},
+		{ID: "HOST-2", Type: "HOST", Name: "web-02"},
+		{ID: "HOST-3", Type: "HOST", Name: "db-01"},
+		{ID: "SERVICE-1", Type: "SERVICE", Name: "shop-frontend"},
+	}
+	report := coverage.Analyze(mzs, entities)
+
+	if len(report.Zones) != 4 || report.Zones[3].Name != "web" || report.Zones[3].Counts["HOST"] != 2 {
+		t.Errorf("unexpected zones %v", report.Zones)
+	}
+	if len(report.Unassigned) != 1 || report.Unassigned[0].ID != "HOST-3" {
+		t.Errorf("expected HOST-3 to be unassigned, got %v", report.Unassigned)
+	}
+	if len(report.Overlapping) != 2 || !reflect.DeepEqual(report.Overlapping[0].Zones, []string{"frontend", "web"}) {
+		t.Errorf("expected HOST-1 and HOST-2 to overlap, got %v", report.Overlapping)
+	}
+	if !reflect.DeepEqual(report.Identical, [][]string{{"frontend", "web"}}) {
+		t.Errorf("expected frontend and web to be identical, got %v", report.Identical)
+	}
+	if !reflect.DeepEqual(report.Empty, []string{"legacy"}) {
+		t.Errorf("expected legacy to be empty, got %v", report.Empty)
+	}
+
+	for _, format := range []coverage.Format{coverage.Formats.JSON, coverage.Formats.CSV, coverage.Formats.Markdown} {
+		var buf bytes.Buffer
+		if err := report.Write(&buf, format); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "db-01") {
+			t.Errorf("expected %s output to mention the unassigned host, got\n%s", format, buf.String())
+		}
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	web := zone(t, "web", managementzones.RuleTypes.Host, `HOST_NAME BEGINS_WITH web`)
+	web.Rules[0].PropagationTypes = []managementzones.PropagationType{managementzones.PropagationTypes.HostToProcessGroupInstance}
+	shop := zone(t, "shop", managementzones.RuleTypes.Service, `SERVICE_NAME CONTAINS shop`)
+	shop.Rules[0].PropagationTypes = []managementzones.PropagationType{managementzones.PropagationTypes.ServiceToHostLike, managementzones.PropagationTypes.ServiceToProcessGroupLike}
+	mzs := []*managementzones.ManagementZone{web, shop}
+	entities := []*evaluator.Entity{
+		{ID: "HOST-1", Type: "HOST", Name: "web-01", Relationships: map[string][]string{}},
+		{ID: "HOST-2", Type: "HOST", Name: "db-01", Relationships: map[string][]string{}},
+		{ID: "PROCESS_GROUP-1", Type: "PROCESS_GROUP", Name: "tomcat", Relationships: map[string][]string{"runsOn": {"HOST-1"}}},
+		{ID: "PROCESS_GROUP_INSTANCE-1", Type: "PROCESS_GROUP_INSTANCE", Name: "tomcat", Relationships: map[string][]string{"isProcessOf": {"HOST-1"}, "isInstanceOf": {"PROCESS_GROUP-1"}}},
+		{ID: "PROCESS_GROUP_INSTANCE-2", Type: "PROCESS_GROUP_INSTANCE", Name: "postgres", Relationships: map[string][]string{"isProcessOf": {"HOST-2"}}},
+		{ID: "SERVICE-1", Type: "SERVICE", Name: "shop", Relationships: map[string][]string{"runsOn": {"PROCESS_GROUP-1"}, "runsOnProcessGroupInstance": {"PROCESS_GROUP_INSTANCE-1"}, "calls": {"SERVICE-2"}}},
+		{ID: "SERVICE-2", Type: "SERVICE", Name: "database", Relationships: map[string][]string{"runsOnProcessGroupInstance": {"PROCESS_GROUP_INSTANCE-2"}}},
+	}
+	report := coverage.Analyze(mzs, entities)
+	if report.Zones[0].Name != "shop" || report.Zones[0].Total != 4 || report.Zones[0].Counts["HOST"] != 1 || report.Zones[0].Incomplete {
+		t.Errorf("expected shop to cover the service, its process group, process and host, got %+v", report.Zones[0])
+	}
+	if report.Zones[1].Name != "web" || report.Zones[1].Total != 2 || report.Zones[1].Incomplete {
+		t.Errorf("expected web to cover the host and its process, got %+v", report.Zones[1])
+	}
+	unassigned := []string{}
+	for _, entity := range report.Unassigned {
+		unassigned = append(unassigned, entity.ID)
+	}
+	if !reflect.DeepEqual(unassigned, []string{"HOST-2", "PROCESS_GROUP_INSTANCE-2", "SERVICE-2"}) {
+		t.Errorf("unexpected unassigned entities %v", unassigned)
+	}
+	overlapping := []string{}
+	for _, entity := range report.Overlapping {
+		overlapping = append(overlapping, entity.ID)
+	}
+	if !reflect.DeepEqual(overlapping, []string{"HOST-1", "PROCESS_GROUP_INSTANCE-1"}) {
+		t.Errorf("unexpected overlapping entities %v", overlapping)
+	}
+
+	// without relationships propagation can't get evaluated
+	for _, entity := range entities {
+		entity.Relationships = nil
+	}
+	report = coverage.Analyze(mzs, entities)
+	if !report.Zones[0].Incomplete || !report.Zones[1].Incomplete {
+		t.Error("expected both zones to be incomplete")
+	}
+	for _, entity := range report.Unassigned {
+		if entity.Type != "SERVICE" {
+			t.Errorf("expected %s to possibly be covered by propagation", entity.ID)
+		}
+	}
+}
+
+func TestRelatedTags(t *testing.T) {
+	prod := "prod"
+	mzs := []*managementzones.ManagementZone{
+		zone(t, "prod hosts", managementzones.RuleTypes.Service, `HOST_TAGS EQUALS env:prod`),
+		zone(t, "kubernetes", managementzones.RuleTypes.Service, `PROCESS_GROUP_TAGS TAG_KEY_EQUALS [KUBERNETES]namespace`),
+		zone(t, "tomcat", managementzones.RuleTypes.Service, `PROCESS_GROUP_NAME EQUALS tomcat`),
+	}
+	entities := []*evaluator.Entity{
+		{ID: "HOST-1", Type: "HOST", Name: "web-01", Tags: []*tag.Info{{Context: "CONTEXTLESS", Key: "env", Value: &prod}}},
+		{ID: "HOST-2", Type: "HOST", Name: "db-01"},
+		{ID: "PROCESS_GROUP-1", Type: "PROCESS_GROUP", Name: "tomcat", Tags: []*tag.Info{{Context: "KUBERNETES", Key: "namespace", Value: &prod}}, Relationships: map[string][]string{"runsOn": {"HOST-1"}}},
+		{ID: "PROCESS_GROUP_INSTANCE-2", Type: "PROCESS_GROUP_INSTANCE", Name: "postgres", Relationships: map[string][]string{"isProcessOf": {"HOST-2"}}},
+		{ID: "SERVICE-1", Type: "SERVICE", Name: "shop", Relationships: map[string][]string{"runsOn": {"PROCESS_GROUP-1"}}},
+		{ID: "SERVICE-2", Type: "SERVICE", Name: "database", Relationships: map[string][]string{"runsOnProcessGroupInstance": {"PROCESS_GROUP_INSTANCE-2"}, "calls": {"SERVICE-1"}}},
+	}
+	report := coverage.Analyze(mzs, entities)
+	for _, zone := range report.Zones {
+		if zone.Total != 1 || zone.Counts["SERVICE"] != 1 {
+			t.Errorf("expected %s to cover the shop service only, got %+v", zone.Name, zone)
+		}
+	}
+	if !reflect.DeepEqual(report.Identical, [][]string{{"kubernetes", "prod hosts", "tomcat"}}) {
+		t.Errorf("expected all zones to cover the same service, got %v", report.Identical)
+	}
+	if names := entities[5].Values("HOST_NAME"); !reflect.DeepEqual(names, []string{"db-01"}) {
+		t.Errorf("expected the database service to run on db-01, got %v", names)
+	}
+}
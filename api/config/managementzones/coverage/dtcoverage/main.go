@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/evaluator"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	"github.com/dtcookie/dynatrace/api/config/managementzones/coverage"
+)
+
+func main() {
+	var environmentURL, apiToken string
+	var zonesFile, snapshotFile, format, outFile string
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&environmentURL, "environment-url", os.Getenv("DT_ENVIRONMENT_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.StringVar(&zonesFile, "zones", "", "")
+	flagSet.StringVar(&snapshotFile, "snapshot", "", "")
+	flagSet.StringVar(&format, "format", string(coverage.Formats.Markdown), "")
+	flagSet.StringVar(&outFile, "out", "", "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	online := zonesFile == "" || snapshotFile == ""
+	if flagSet.NArg() != 0 || (online && (environmentURL == "" || apiToken == "")) {
+		usage()
+		os.Exit(2)
+	}
+	environmentURL = strings.TrimSuffix(environmentURL, "/")
+
+	var mzs []*managementzones.ManagementZone
+	var err error
+	if zonesFile != "" {
+		mzs, err = coverage.ReadZones(zonesFile)
+	} else {
+		mzs, err = coverage.Fetch(environmentURL+"/api/config/v1", apiToken)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	var entities []*evaluator.Entity
+	if snapshotFile != "" {
+		entities, err = evaluator.ReadSnapshot(snapshotFile)
+	} else {
+		entities, err = evaluator.Snapshot(environmentURL+"/api/v1", apiToken)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if outFile != "" {
+		if out, err = os.Create(outFile); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+	if err := coverage.Analyze(mzs, entities).Write(out, coverage.Format(format)); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtcoverage [-environment-url <environment-url>] [-api-token <api-token>] [-zones <file>] [-snapshot <file>] [-format json|csv|markdown] [-out <file>]")
+	fmt.Println("  Reports which entities the management zones of an environment cover.")
+	fmt.Println("  -zones     reads the management zones from <file> (a JSON array) instead of fetching them")
+	fmt.Println("  -snapshot  evaluates against the entities stored in <file> instead of fetching them")
+	fmt.Println("  -format    the format of the report, markdown by default")
+	fmt.Println("  -out       writes the report to <file> instead of stdout")
+	fmt.Println("  Hint: you can also define the environment variables DT_ENVIRONMENT_URL and DT_API_TOKEN")
+}
@@ -0,0 +1,41 @@
+package coverage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+)
+
+// Fetch retrieves all management zones of an environment
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/config/v1"
+// token is an API Token
+func Fetch(baseURL string, token string) ([]*managementzones.ManagementZone, error) {
+	service := managementzones.NewService(baseURL, token)
+	stubs, err := service.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	mzs := []*managementzones.ManagementZone{}
+	for _, stub := range stubs {
+		mz, err := service.Get(stub.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		mzs = append(mzs, mz)
+	}
+	return mzs, nil
+}
+
+// ReadZones reads management zones from a file containing a JSON array of management zones
+func ReadZones(file string) ([]*managementzones.ManagementZone, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	mzs := []*managementzones.ManagementZone{}
+	if err := json.Unmarshal(data, &mzs); err != nil {
+		return nil, err
+	}
+	return mzs, nil
+}
@@ -0,0 +1,139 @@
+package coverage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format is an output format of a report
+type Format string
+
+// Formats offers the known enum values
+var Formats = struct {
+	JSON     Format
+	CSV      Format
+	Markdown Format
+}{
+	"json",
+	"csv",
+	"markdown",
+}
+
+// Write writes the report in the given format
+func (me *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case Formats.JSON:
+		return me.WriteJSON(w)
+	case Formats.CSV:
+		return me.WriteCSV(w)
+	case Formats.Markdown:
+		return me.WriteMarkdown(w)
+	}
+	return fmt.Errorf("unknown format '%s'", format)
+}
+
+// WriteJSON writes the report as indented JSON
+func (me *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(me)
+}
+
+// WriteCSV writes the report as a single table. The first column denotes the section a record belongs to:
+//
+//	zone,<zone>,<entity type>,,,<count>
+//	unassigned,,<entity type>,<entity id>,<entity name>,
+//	overlapping,<zone>;<zone>,<entity type>,<entity id>,<entity name>,
+//	identical,<zone>;<zone>,,,,
+//	empty,<zone>,,,,
+func (me *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"section", "zone", "type", "id", "name", "count"}}
+	for _, zone := range me.Zones {
+		for _, entityType := range sortedKeys(zone.Counts) {
+			records = append(records, []string{"zone", zone.Name, entityType, "", "", strconv.Itoa(zone.Counts[entityType])})
+		}
+	}
+	for _, entity := range me.Unassigned {
+		records = append(records, []string{"unassigned", "", entity.Type, entity.ID, entity.Name, ""})
+	}
+	for _, entity := range me.Overlapping {
+		records = append(records, []string{"overlapping", strings.Join(entity.Zones, ";"), entity.Type, entity.ID, entity.Name, ""})
+	}
+	for _, zones := range me.Identical {
+		records = append(records, []string{"identical", strings.Join(zones, ";"), "", "", "", ""})
+	}
+	for _, zone := range me.Empty {
+		records = append(records, []string{"empty", zone, "", "", "", ""})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// WriteMarkdown writes the report as Markdown document
+func (me *Report) WriteMarkdown(w io.Writer) error {
+	lines := []string{"# Management Zone Coverage", ""}
+	lines = append(lines, fmt.Sprintf("%d entities, %d zones, %d unassigned, %d overlapping", me.Entities, len(me.Zones), len(me.Unassigned), len(me.Overlapping)), "")
+
+	types := map[string]int{}
+	for _, zone := range me.Zones {
+		for entityType := range zone.Counts {
+			types[entityType]++
+		}
+	}
+	columns := sortedKeys(types)
+	lines = append(lines, "## Zones", "")
+	lines = append(lines, "| Zone | Total | "+strings.Join(columns, " | ")+" |")
+	lines = append(lines, "|---|---:|"+strings.Repeat("---:|", len(columns)))
+	for _, zone := range me.Zones {
+		name := markdownEscape(zone.Name)
+		if zone.Incomplete {
+			name = name + " \\*"
+		}
+		cells := []string{name, strconv.Itoa(zone.Total)}
+		for _, entityType := range columns {
+			cells = append(cells, strconv.Itoa(zone.Counts[entityType]))
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+	lines = append(lines, "", "\\* the zone also has dimensional or entity selector based rules or propagates to entities in a way which can't get evaluated", "")
+
+	lines = append(lines, "## Entities without zone", "")
+	for _, entity := range me.Unassigned {
+		lines = append(lines, fmt.Sprintf("- %s %s (%s)", entity.Type, markdownEscape(entity.Name), entity.ID))
+	}
+	lines = append(lines, "", "## Entities in several zones", "")
+	for _, entity := range me.Overlapping {
+		lines = append(lines, fmt.Sprintf("- %s %s (%s): %s", entity.Type, markdownEscape(entity.Name), entity.ID, markdownEscape(strings.Join(entity.Zones, ", "))))
+	}
+	lines = append(lines, "", "## Zones with identical coverage", "")
+	for _, zones := range me.Identical {
+		lines = append(lines, "- "+markdownEscape(strings.Join(zones, ", ")))
+	}
+	lines = append(lines, "", "## Zones matching nothing", "")
+	for _, zone := range me.Empty {
+		lines = append(lines, "- "+markdownEscape(zone))
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, `*`, `\*`, `_`, `\_`).Replace(s)
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
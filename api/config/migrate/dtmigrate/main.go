@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/apply"
+	"github.com/dtcookie/dynatrace/api/config/migrate"
+)
+
+func main() {
+	var sourceURL, sourceToken string
+	var targetURL, targetToken string
+	var entityMap string
+	var only string
+	var conflicts string
+	var dryRun bool
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&sourceURL, "source-url", os.Getenv("DT_SOURCE_API_BASE_URL"), "")
+	flagSet.StringVar(&sourceToken, "source-token", os.Getenv("DT_SOURCE_API_TOKEN"), "")
+	flagSet.StringVar(&targetURL, "target-url", os.Getenv("DT_TARGET_API_BASE_URL"), "")
+	flagSet.StringVar(&targetToken, "target-token", os.Getenv("DT_TARGET_API_TOKEN"), "")
+	flagSet.StringVar(&entityMap, "entity-map", "", "")
+	flagSet.StringVar(&only, "only", "", "")
+	flagSet.StringVar(&conflicts, "conflicts", string(migrate.ConflictPolicies.Overwrite), "")
+	flagSet.BoolVar(&dryRun, "dry-run", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if sourceURL == "" || sourceToken == "" || targetURL == "" || targetToken == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	migrator := migrate.New(apply.Kinds(sourceURL, sourceToken), apply.Kinds(targetURL, targetToken))
+	migrator.DryRun = dryRun
+	migrator.Conflicts = migrate.ConflictPolicy(conflicts)
+	if only != "" {
+		migrator.Only = strings.Split(only, ",")
+	}
+	if entityMap != "" {
+		data, err := ioutil.ReadFile(entityMap)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if err = json.Unmarshal(data, &migrator.Entities); err != nil {
+			fmt.Println(entityMap + ": " + err.Error())
+			os.Exit(1)
+		}
+	}
+
+	report, err := migrator.Run()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(report.String())
+	if len(report.Failed()) > 0 {
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtmigrate -source-url <api-base-url> -source-token <api-token> -target-url <api-base-url> -target-token <api-token> [-only <kind>,...] [-entity-map <json-file>] [-conflicts overwrite|skip|fail] [-dry-run]")
+	fmt.Println("  -only        restricts the migration to the given kinds (managementzones, alertingprofiles, notifications, autotags, maintenancewindows, dashboards)")
+	fmt.Println("  -entity-map  JSON file mapping IDs of monitored entities in the source environment to their IDs in the target environment")
+	fmt.Println("  -conflicts   what to do with configurations already existing in the target environment (default: overwrite)")
+	fmt.Println("  -dry-run     only reports what would get migrated")
+	fmt.Println("  Hint: you can also define the environment variables DT_SOURCE_API_BASE_URL, DT_SOURCE_API_TOKEN, DT_TARGET_API_BASE_URL and DT_TARGET_API_TOKEN")
+}
@@ -0,0 +1,34 @@
+module github.com/dtcookie/dynatrace/api/config/migrate
+
+go 1.15
+
+require (
+	github.com/dtcookie/dynatrace/api/cluster/v1/users v1.0.0
+	github.com/dtcookie/dynatrace/api/config v1.0.10
+	github.com/dtcookie/dynatrace/api/config/alerting v1.0.0
+	github.com/dtcookie/dynatrace/api/config/apply v1.0.0
+	github.com/dtcookie/dynatrace/api/config/autotags v1.0.0
+	github.com/dtcookie/dynatrace/api/config/dashboards v1.0.0
+	github.com/dtcookie/dynatrace/api/config/diff v1.0.0
+	github.com/dtcookie/dynatrace/api/config/entityruleengine v1.0.11
+	github.com/dtcookie/dynatrace/api/config/maintenance v1.0.0
+	github.com/dtcookie/dynatrace/api/config/managementzones v1.0.0
+	github.com/dtcookie/dynatrace/api/config/notifications v1.0.0
+	github.com/dtcookie/opt v1.0.0
+)
+
+replace (
+	github.com/dtcookie/dynatrace/api/cluster/v1/users => ../../cluster/v1/users
+	github.com/dtcookie/dynatrace/api/config => ..
+	github.com/dtcookie/dynatrace/api/config/alerting => ../alerting
+	github.com/dtcookie/dynatrace/api/config/apply => ../apply
+	github.com/dtcookie/dynatrace/api/config/autotags => ../autotags
+	github.com/dtcookie/dynatrace/api/config/common => ../common
+	github.com/dtcookie/dynatrace/api/config/dashboards => ../dashboards
+	github.com/dtcookie/dynatrace/api/config/diff => ../diff
+	github.com/dtcookie/dynatrace/api/config/entityruleengine => ../entityruleengine
+	github.com/dtcookie/dynatrace/api/config/maintenance => ../maintenance
+	github.com/dtcookie/dynatrace/api/config/managementzones => ../managementzones
+	github.com/dtcookie/dynatrace/api/config/notifications => ../notifications
+	github.com/dtcookie/dynatrace/rest => ../../../rest
+)
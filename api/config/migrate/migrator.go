@@ -0,0 +1,260 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/apply"
+	"github.com/dtcookie/dynatrace/api/config/diff"
+)
+
+// Migrator copies the configuration of one environment into another one.
+// Configurations are matched by name. References to other configurations are rewritten
+// to the IDs of the configurations with the same name in the target environment.
+type Migrator struct {
+	source []apply.Kind
+	target []apply.Kind
+	// Entities maps IDs of monitored entities in the source environment to the IDs in the target environment
+	Entities map[string]string
+	// Only restricts the migration to the kinds with the given names. References to configurations
+	// of other kinds are still resolved, provided the target environment contains them already.
+	Only []string
+	// KeepUnmappedEntities leaves IDs of monitored entities without an entry in Entities untouched
+	// instead of treating them as unresolved. Useful if source and target are the same environment.
+	KeepUnmappedEntities bool
+	// Conflicts determines what happens to configurations which already exist in the target environment
+	Conflicts ConflictPolicy
+	// DryRun determines the necessary changes without applying them
+	DryRun bool
+}
+
+// ConflictPolicy determines how configurations are treated which already exist in the target environment
+type ConflictPolicy string
+
+// ConflictPolicies offers the known enum values
+var ConflictPolicies = struct {
+	Overwrite ConflictPolicy // the configuration in the target environment gets updated
+	Skip      ConflictPolicy // the configuration in the target environment remains unchanged
+	Fail      ConflictPolicy // the configuration is reported as failed
+}{
+	"overwrite",
+	"skip",
+	"fail",
+}
+
+// New creates a Migrator. The kinds for source and target are expected to be created
+// for the according environment via apply.Kinds
+func New(source []apply.Kind, target []apply.Kind) *Migrator {
+	return &Migrator{source: source, target: target, Entities: map[string]string{}}
+}
+
+// index holds the short representations of a single kind within an environment
+type index struct {
+	names map[string]string   // ID -> name
+	ids   map[string][]string // name -> IDs
+}
+
+func newIndex(kind apply.Kind) (*index, error) {
+	var err error
+	var stubs []*api.EntityShortRepresentation
+
+	if stubs, err = kind.List(); err != nil {
+		return nil, fmt.Errorf("listing %s failed: %s", kind.Name(), err.Error())
+	}
+	idx := &index{names: map[string]string{}, ids: map[string][]string{}}
+	for _, stub := range stubs {
+		idx.add(stub.ID, stub.Name)
+	}
+	return idx, nil
+}
+
+func (me *index) add(id string, name string) {
+	me.names[id] = name
+	me.ids[name] = append(me.ids[name], id)
+}
+
+// sorted returns the IDs ordered by name
+func (me *index) sorted() []string {
+	ids := []string{}
+	for id := range me.names {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if me.names[ids[i]] == me.names[ids[j]] {
+			return ids[i] < ids[j]
+		}
+		return me.names[ids[i]] < me.names[ids[j]]
+	})
+	return ids
+}
+
+// Run migrates the configurations in dependency order, so references to configurations
+// created during the migration can get resolved
+func (me *Migrator) Run() (*Report, error) {
+	var err error
+	var kinds []apply.Kind
+
+	switch me.Conflicts {
+	case "", ConflictPolicies.Overwrite, ConflictPolicies.Skip, ConflictPolicies.Fail:
+	default:
+		return nil, fmt.Errorf("unknown conflict policy '%s'", me.Conflicts)
+	}
+	if kinds, err = apply.Order(me.source); err != nil {
+		return nil, err
+	}
+	targets := map[string]apply.Kind{}
+	for _, kind := range me.target {
+		targets[kind.Name()] = kind
+	}
+
+	sources := map[string]*index{}
+	destinations := map[string]*index{}
+	for _, kind := range kinds {
+		target, found := targets[kind.Name()]
+		if !found {
+			return nil, fmt.Errorf("no target for kind %s", kind.Name())
+		}
+		if sources[kind.Name()], err = newIndex(kind); err != nil {
+			return nil, err
+		}
+		if destinations[kind.Name()], err = newIndex(target); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{Results: []*Result{}, Unresolved: []*Unresolved{}}
+	for _, kind := range kinds {
+		if !me.included(kind.Name()) {
+			continue
+		}
+		source := sources[kind.Name()]
+		for _, id := range source.sorted() {
+			result := me.migrate(kind, targets[kind.Name()], id, source.names[id], sources, destinations, report)
+			report.Results = append(report.Results, result)
+		}
+	}
+	return report, nil
+}
+
+func (me *Migrator) included(kind string) bool {
+	if len(me.Only) == 0 {
+		return true
+	}
+	for _, name := range me.Only {
+		if name == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (me *Migrator) migrate(kind apply.Kind, target apply.Kind, id string, name string, sources map[string]*index, destinations map[string]*index, report *Report) *Result {
+	var err error
+	var config interface{}
+
+	result := &Result{Kind: kind.Name(), Name: name, SourceID: id}
+	if config, err = kind.Get(id); err != nil {
+		result.Error = fmt.Errorf("fetching from source failed: %s", err.Error())
+		return result
+	}
+
+	unresolved := 0
+	for _, ref := range References(config) {
+		if reason := me.resolve(ref, sources, destinations); len(reason) > 0 {
+			report.Unresolved = append(report.Unresolved, &Unresolved{Kind: kind.Name(), Name: name, Path: ref.Path, ReferencedKind: ref.Kind, ID: ref.ID, Reason: reason})
+			unresolved++
+		}
+	}
+	if unresolved > 0 {
+		result.Error = fmt.Errorf("skipped because of %d unresolved references", unresolved)
+		return result
+	}
+
+	destination := destinations[kind.Name()]
+	ids := destination.ids[name]
+	switch len(ids) {
+	case 0:
+		result.Action = apply.Actions.Create
+		if me.DryRun {
+			destination.add(pending(kind.Name(), name), name)
+			return result
+		}
+		if result.TargetID, result.Error = target.Create(config); result.Error == nil {
+			destination.add(result.TargetID, name)
+		}
+	case 1:
+		result.TargetID = ids[0]
+		switch me.Conflicts {
+		case ConflictPolicies.Skip:
+			result.Action = apply.Actions.NoOp
+			return result
+		case ConflictPolicies.Fail:
+			result.Error = errors.New("already exists in the target environment")
+			return result
+		}
+		var current interface{}
+		if current, err = target.Get(ids[0]); err != nil {
+			result.Error = fmt.Errorf("fetching from target failed: %s", err.Error())
+			return result
+		}
+		var differences diff.Changes
+		if differences, err = diff.Compare(current, config); err != nil {
+			result.Error = err
+			return result
+		}
+		if differences.Empty() {
+			result.Action = apply.Actions.NoOp
+			return result
+		}
+		result.Action = apply.Actions.Update
+		if !me.DryRun {
+			result.Error = target.Update(ids[0], config)
+		}
+	default:
+		result.Error = fmt.Errorf("the name is ambiguous in the target environment: %v", ids)
+	}
+	return result
+}
+
+// resolve rewrites the given reference to the according ID in the target environment.
+// It returns the reason in case that isn't possible.
+func (me *Migrator) resolve(ref *Reference, sources map[string]*index, destinations map[string]*index) string {
+	if ref.Kind == Entities {
+		if id, found := me.Entities[ref.ID]; found {
+			ref.Set(id)
+			return ""
+		}
+		if me.KeepUnmappedEntities {
+			return ""
+		}
+		return "no mapping for monitored entity"
+	}
+	source, found := sources[ref.Kind]
+	if !found {
+		return fmt.Sprintf("%s are not part of the migration", ref.Kind)
+	}
+	name, found := source.names[ref.ID]
+	if !found {
+		return "doesn't exist in the source environment"
+	}
+	if len(source.ids[name]) > 1 {
+		return fmt.Sprintf("the name '%s' is ambiguous in the source environment", name)
+	}
+	ids := destinations[ref.Kind].ids[name]
+	switch len(ids) {
+	case 0:
+		return fmt.Sprintf("'%s' doesn't exist in the target environment", name)
+	case 1:
+		ref.Set(ids[0])
+		return ""
+	default:
+		return fmt.Sprintf("the name '%s' is ambiguous in the target environment", name)
+	}
+}
+
+// pending is the placeholder ID for configurations which would get created if it weren't a dry run
+func pending(kind string, name string) string {
+	return fmt.Sprintf("<%s '%s'>", kind, name)
+}
@@ -0,0 +1,114 @@
+package migrate
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/dtcookie/dynatrace/api/cluster/v1/users"
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/dashboards"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	"github.com/dtcookie/dynatrace/api/config/notifications"
+	"github.com/dtcookie/opt"
+)
+
+// Entities is the pseudo kind of references to monitored entities, e.g. `HOST-0123456789ABCDEF`.
+// Monitored entities can't be matched by name, their IDs need to get mapped explicitly.
+const Entities = "entities"
+
+var entityIDPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[0-9A-F]{16}$`)
+
+// alertingProfileRef is implemented by the notification configurations based on notifications.BaseNotificationConfig.
+// It isn't part of notifications.NotificationConfig in order to not break other implementations of that interface.
+type alertingProfileRef interface {
+	GetAlertingProfile() string
+	SetAlertingProfile(string)
+}
+
+// Reference is an ID within a configuration which refers to another configuration or to a monitored entity
+type Reference struct {
+	Kind string // the name of the kind of the referenced configuration or Entities
+	Path string // the location of the reference within the configuration
+	ID   string // the referenced ID
+	set  func(id string)
+}
+
+// Set replaces the referenced ID within the configuration
+func (me *Reference) Set(id string) {
+	me.ID = id
+	me.set(id)
+}
+
+// References returns the cross references contained in the given configuration
+func References(v interface{}) []*Reference {
+	refs := []*Reference{}
+	switch config := v.(type) {
+	case *alerting.Profile:
+		if len(opt.String(config.MzID)) > 0 {
+			refs = append(refs, &Reference{Kind: "managementzones", Path: "mzId", ID: *config.MzID, set: func(id string) { config.MzID = &id }})
+		}
+	case *notifications.NotificationRecord:
+		if cfg, ok := config.NotificationConfig.(alertingProfileRef); ok && len(cfg.GetAlertingProfile()) > 0 {
+			refs = append(refs, &Reference{Kind: "alertingprofiles", Path: "alertingProfile", ID: cfg.GetAlertingProfile(), set: cfg.SetAlertingProfile})
+		}
+	case *dashboards.Dashboard:
+		if config.Metadata != nil && config.Metadata.Filter != nil && config.Metadata.Filter.ManagementZone != nil {
+			ref := config.Metadata.Filter.ManagementZone
+			refs = append(refs, &Reference{Kind: "managementzones", Path: "dashboardMetadata.dashboardFilter.managementZone.id", ID: ref.ID, set: func(id string) { ref.ID = id }})
+		}
+		for i, tile := range config.Tiles {
+			if tile.Filter != nil && tile.Filter.ManagementZone != nil {
+				ref := tile.Filter.ManagementZone
+				refs = append(refs, &Reference{Kind: "managementzones", Path: fmt.Sprintf("tiles[%d].tileFilter.managementZone.id", i), ID: ref.ID, set: func(id string) { ref.ID = id }})
+			}
+			for j := range tile.AssignedEntities {
+				entities, idx := tile.AssignedEntities, j
+				if entityIDPattern.MatchString(entities[idx]) {
+					refs = append(refs, &Reference{Kind: Entities, Path: fmt.Sprintf("tiles[%d].assignedEntities[%d]", i, j), ID: entities[idx], set: func(id string) { entities[idx] = id }})
+				}
+			}
+		}
+	case *users.UserConfig:
+		for i := range config.Groups {
+			groups, idx := config.Groups, i
+			refs = append(refs, &Reference{Kind: "groups", Path: fmt.Sprintf("groups[%d]", i), ID: groups[idx], set: func(id string) { groups[idx] = id }})
+		}
+	case *maintenance.Window:
+		if config.Scope != nil {
+			for i := range config.Scope.Entities {
+				entities, idx := config.Scope.Entities, i
+				refs = append(refs, &Reference{Kind: Entities, Path: fmt.Sprintf("scope.entities[%d]", i), ID: entities[idx], set: func(id string) { entities[idx] = id }})
+			}
+			for i, match := range config.Scope.Matches {
+				if len(opt.String(match.MzID)) > 0 {
+					filter := match
+					refs = append(refs, &Reference{Kind: "managementzones", Path: fmt.Sprintf("scope.matches[%d].mzId", i), ID: *match.MzID, set: func(id string) { filter.MzID = &id }})
+				}
+			}
+		}
+	case *managementzones.ManagementZone:
+		for i, rule := range config.Rules {
+			refs = append(refs, conditionReferences(fmt.Sprintf("rules[%d]", i), rule.Conditions)...)
+		}
+	case *autotags.AutoTag:
+		for i, rule := range config.Rules {
+			refs = append(refs, conditionReferences(fmt.Sprintf("rules[%d]", i), rule.Conditions)...)
+		}
+	}
+	return refs
+}
+
+func conditionReferences(path string, conditions []*entityruleengine.Condition) []*Reference {
+	refs := []*Reference{}
+	for i, condition := range conditions {
+		if cmp, ok := condition.ComparisonInfo.(*comparison.EntityID); ok && len(opt.String(cmp.Value)) > 0 {
+			entityID := cmp
+			refs = append(refs, &Reference{Kind: Entities, Path: fmt.Sprintf("%s.conditions[%d].comparisonInfo.value", path, i), ID: *cmp.Value, set: func(id string) { entityID.Value = &id }})
+		}
+	}
+	return refs
+}
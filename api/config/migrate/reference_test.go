@@ -0,0 +1,117 @@
+package migrate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/cluster/v1/users"
+	api "github.com/dtcookie/dynatrace/api/config"
+	"github.com/dtcookie/dynatrace/api/config/alerting"
+	"github.com/dtcookie/dynatrace/api/config/autotags"
+	"github.com/dtcookie/dynatrace/api/config/dashboards"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine"
+	"github.com/dtcookie/dynatrace/api/config/entityruleengine/comparison"
+	"github.com/dtcookie/dynatrace/api/config/maintenance"
+	"github.com/dtcookie/dynatrace/api/config/managementzones"
+	"github.com/dtcookie/dynatrace/api/config/migrate"
+	"github.com/dtcookie/dynatrace/api/config/notifications"
+	"github.com/dtcookie/opt"
+)
+
+// customNotification implements notifications.NotificationConfig without referring to an alerting profile
+type customNotification struct {
+	notifications.NotificationConfig
+}
+
+func entityIDCondition(id string) []*entityruleengine.Condition {
+	return []*entityruleengine.Condition{
+		{ComparisonInfo: &comparison.String{Value: opt.NewString("web")}},
+		{ComparisonInfo: &comparison.EntityID{Value: opt.NewString(id)}},
+	}
+}
+
+func TestReferences(t *testing.T) {
+	hostID := "HOST-0123456789ABCDEF"
+	tests := []struct {
+		name     string
+		config   interface{}
+		expected []string // kind, path and ID of every reference
+	}{
+		{
+			"alerting profile",
+			&alerting.Profile{MzID: opt.NewString("mz-1")},
+			[]string{"managementzones mzId mz-1"},
+		},
+		{
+			"alerting profile without management zone",
+			&alerting.Profile{},
+			[]string{},
+		},
+		{
+			"notification",
+			&notifications.NotificationRecord{NotificationConfig: &notifications.EmailConfig{BaseNotificationConfig: notifications.BaseNotificationConfig{AlertingProfile: "ap-1"}}},
+			[]string{"alertingprofiles alertingProfile ap-1"},
+		},
+		{
+			"notification of another implementation",
+			&notifications.NotificationRecord{NotificationConfig: &customNotification{}},
+			[]string{},
+		},
+		{
+			"dashboard",
+			&dashboards.Dashboard{
+				Metadata: &dashboards.DashboardMetadata{Filter: &dashboards.DashboardFilter{ManagementZone: &api.EntityRef{ID: "mz-1"}}},
+				Tiles: []*dashboards.Tile{
+					{Filter: &dashboards.TileFilter{ManagementZone: &dashboards.EntityRef{ID: "mz-2"}}},
+					{AssignedEntities: []string{"overview", hostID}},
+				},
+			},
+			[]string{
+				"managementzones dashboardMetadata.dashboardFilter.managementZone.id mz-1",
+				"managementzones tiles[0].tileFilter.managementZone.id mz-2",
+				"entities tiles[1].assignedEntities[1] " + hostID,
+			},
+		},
+		{
+			"user",
+			&users.UserConfig{Groups: []string{"g-1", "g-2"}},
+			[]string{"groups groups[0] g-1", "groups groups[1] g-2"},
+		},
+		{
+			"maintenance window",
+			&maintenance.Window{Scope: &maintenance.Scope{
+				Entities: []string{hostID},
+				Matches:  []*maintenance.Filter{{}, {MzID: opt.NewString("mz-1")}},
+			}},
+			[]string{"entities scope.entities[0] " + hostID, "managementzones scope.matches[1].mzId mz-1"},
+		},
+		{
+			"management zone",
+			&managementzones.ManagementZone{Rules: []*managementzones.Rule{{Conditions: entityIDCondition(hostID)}}},
+			[]string{"entities rules[0].conditions[1].comparisonInfo.value " + hostID},
+		},
+		{
+			"auto tag",
+			&autotags.AutoTag{Rules: []*autotags.Rule{{}, {Conditions: entityIDCondition(hostID)}}},
+			[]string{"entities rules[1].conditions[1].comparisonInfo.value " + hostID},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			refs := migrate.References(test.config)
+			actual := []string{}
+			for _, ref := range refs {
+				actual = append(actual, ref.Kind+" "+ref.Path+" "+ref.ID)
+				ref.Set("new-" + ref.ID)
+			}
+			if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+				t.Fatalf("expected references\n%s\ngot\n%s", strings.Join(test.expected, "\n"), strings.Join(actual, "\n"))
+			}
+			for _, ref := range migrate.References(test.config) {
+				if !strings.HasPrefix(ref.ID, "new-") {
+					t.Errorf("expected %s to be rewritten, got %s", ref.Path, ref.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/api/config/apply"
+)
+
+// Result is the outcome of migrating a single configuration
+type Result struct {
+	Kind     string
+	Name     string
+	SourceID string
+	TargetID string       // the ID in the target environment; empty if it doesn't exist (yet)
+	Action   apply.Action // empty if the configuration has been skipped
+	Error    error
+}
+
+func (me *Result) String() string {
+	if me.Error != nil {
+		return fmt.Sprintf("! %s '%s' (%s): %s", me.Kind, me.Name, me.SourceID, me.Error.Error())
+	}
+	return fmt.Sprintf("%s %s '%s' (%s -> %s)", strings.ToLower(string(me.Action)), me.Kind, me.Name, me.SourceID, me.TargetID)
+}
+
+// Unresolved is a reference which couldn't get rewritten to an ID in the target environment
+type Unresolved struct {
+	Kind           string // the kind of the referring configuration
+	Name           string // the name of the referring configuration
+	Path           string // the location of the reference within the referring configuration
+	ReferencedKind string
+	ID             string // the referenced ID in the source environment
+	Reason         string
+}
+
+func (me *Unresolved) String() string {
+	return fmt.Sprintf("%s '%s' %s: %s %s - %s", me.Kind, me.Name, me.Path, me.ReferencedKind, me.ID, me.Reason)
+}
+
+// Report contains the results of a migration
+type Report struct {
+	Results    []*Result
+	Unresolved []*Unresolved
+}
+
+// Failed returns the results of the configurations that couldn't get migrated
+func (me *Report) Failed() []*Result {
+	failed := []*Result{}
+	for _, result := range me.Results {
+		if result.Error != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// String renders the results followed by the unresolved references
+func (me *Report) String() string {
+	lines := []string{}
+	for _, result := range me.Results {
+		if result.Error != nil || result.Action != apply.Actions.NoOp {
+			lines = append(lines, result.String())
+		}
+	}
+	if len(me.Unresolved) > 0 {
+		lines = append(lines, "", "Unresolved references:")
+		for _, unresolved := range me.Unresolved {
+			lines = append(lines, "  "+unresolved.String())
+		}
+	}
+	lines = append(lines, "", fmt.Sprintf("Migration complete: %d configurations, %d failed or skipped, %d unresolved references.", len(me.Results), len(me.Failed()), len(me.Unresolved)))
+	return strings.Join(lines, "\n")
+}
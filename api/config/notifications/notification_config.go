@@ -42,10 +42,22 @@ func (me *BaseNotificationConfig) GetName() string {
 	return me.Name
 }
 
+func (me *BaseNotificationConfig) IsActive() bool {
+	return me.Active
+}
+
 func (me *BaseNotificationConfig) SetID(id *string) {
 	me.ID = id
 }
 
+func (me *BaseNotificationConfig) GetAlertingProfile() string {
+	return me.AlertingProfile
+}
+
+func (me *BaseNotificationConfig) SetAlertingProfile(id string) {
+	me.AlertingProfile = id
+}
+
 func (me *BaseNotificationConfig) Schema() map[string]*hcl.Schema {
 	return map[string]*hcl.Schema{
 		"name": {
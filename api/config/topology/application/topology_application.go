@@ -8,6 +8,12 @@ type Application struct {
 	EntityId    string `json:"entityId"`    // The entity ID of the application
 	DisplayName string `json:"displayName"` // The name of the application as displayed in the UI
 	Tags        []Tag  `json:"tags"`        // The list of entity tags
+	// FromRelationships holds the IDs of the entities the application relates to, keyed by relationship, e.g. `calls` or `runsOn`
+	FromRelationships map[string][]string `json:"fromRelationships,omitempty"`
+	// ToRelationships holds the IDs of the entities relating to the application, keyed by relationship
+	ToRelationships map[string][]string `json:"toRelationships,omitempty"`
+	// ManagementZones lists the management zones the application belongs to
+	ManagementZones []ManagementZone `json:"managementZones,omitempty"`
 }
 
 // Tag is a single definition of a tag used for a Dynatrace entity
@@ -16,3 +22,9 @@ type Tag struct {
 	Key     string  `json:"key"`
 	Value   *string `json:"value,omitempty"`
 }
+
+// ManagementZone is a short representation of a management zone
+type ManagementZone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"reflect"
+
+	"github.com/dtcookie/dynatrace/api/config/topology/application"
+	"github.com/dtcookie/dynatrace/api/config/topology/host"
+	"github.com/dtcookie/dynatrace/api/config/topology/process"
+	"github.com/dtcookie/dynatrace/api/config/topology/processgroup"
+	"github.com/dtcookie/dynatrace/api/config/topology/service"
+)
+
+// Topology holds the entities of all types the topology clients return
+type Topology struct {
+	Applications  application.Applications   `json:"applications"`
+	Hosts         host.Hosts                 `json:"hosts"`
+	Processes     process.Processes          `json:"processes"`
+	ProcessGroups processgroup.ProcessGroups `json:"processGroups"`
+	Services      service.Services           `json:"services"`
+}
+
+// Fetch retrieves the applications, hosts, processes, process groups and services of an environment
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/v1"
+// token is an API Token
+func Fetch(baseURL string, token string) (*Topology, error) {
+	var err error
+	topology := &Topology{}
+	if topology.Applications, err = application.NewService(baseURL, token).List(); err != nil {
+		return nil, err
+	}
+	if topology.Hosts, err = host.NewService(baseURL, token).List(); err != nil {
+		return nil, err
+	}
+	if topology.Processes, err = process.NewService(baseURL, token).List(); err != nil {
+		return nil, err
+	}
+	if topology.ProcessGroups, err = processgroup.NewService(baseURL, token).List(); err != nil {
+		return nil, err
+	}
+	if topology.Services, err = service.NewService(baseURL, token).List(); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
+
+// Build creates the graph of the given topology.
+// Relationships reported by both involved entities result in a single edge.
+func Build(topology *Topology) *Graph {
+	g := New()
+	for _, e := range topology.Applications {
+		g.add(e.EntityId, e.DisplayName, NodeTypes.Application, e.Tags, e.ManagementZones, e.FromRelationships, e.ToRelationships)
+	}
+	for _, e := range topology.Hosts {
+		g.add(e.EntityId, e.DisplayName, NodeTypes.Host, e.Tags, e.ManagementZones, e.FromRelationships, e.ToRelationships)
+	}
+	for _, e := range topology.Processes {
+		g.add(e.EntityId, e.DisplayName, NodeTypes.ProcessGroupInstance, e.Tags, e.ManagementZones, e.FromRelationships, e.ToRelationships)
+	}
+	for _, e := range topology.ProcessGroups {
+		g.add(e.EntityId, e.DisplayName, NodeTypes.ProcessGroup, e.Tags, e.ManagementZones, e.FromRelationships, e.ToRelationships)
+	}
+	for _, e := range topology.Services {
+		g.add(e.EntityId, e.DisplayName, NodeTypes.Service, e.Tags, e.ManagementZones, e.FromRelationships, e.ToRelationships)
+	}
+	return g
+}
+
+var tagType = reflect.TypeOf(Tag{})
+var managementZoneType = reflect.TypeOf(ManagementZone{})
+
+// add adds an entity of the topology together with its relationships.
+// Every topology package declares its own Tag and ManagementZone types, tags and mzs are slices of these,
+// which share their structure with Tag and ManagementZone of this package.
+func (me *Graph) add(id string, name string, nodeType string, tags interface{}, mzs interface{}, from map[string][]string, to map[string][]string) {
+	node := &Node{ID: id, Type: nodeType, Name: name, Tags: []Tag{}, ManagementZones: []ManagementZone{}}
+	tv := reflect.ValueOf(tags)
+	for i := 0; i < tv.Len(); i++ {
+		node.Tags = append(node.Tags, tv.Index(i).Convert(tagType).Interface().(Tag))
+	}
+	mv := reflect.ValueOf(mzs)
+	for i := 0; i < mv.Len(); i++ {
+		node.ManagementZones = append(node.ManagementZones, mv.Index(i).Convert(managementZoneType).Interface().(ManagementZone))
+	}
+	me.AddNode(node)
+	for key, ids := range from {
+		for _, other := range ids {
+			me.AddEdge(node.ID, other, EdgeType(key))
+		}
+	}
+	for key, ids := range to {
+		for _, other := range ids {
+			me.AddEdge(other, node.ID, EdgeType(key))
+		}
+	}
+}
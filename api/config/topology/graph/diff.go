@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// placementTypes are the relationships describing where an entity lives rather than whom it talks to.
+// A change of their targets is reported as the entity having moved.
+var placementTypes = []EdgeType{
+	EdgeTypes.IsInstanceOf,
+	EdgeTypes.IsProcessOf,
+	EdgeTypes.RunsOn,
+	EdgeTypes.RunsOnHost,
+	EdgeTypes.RunsOnProcessGroupInstance,
+}
+
+// Change is a changed property of an entity
+type Change struct {
+	Property string `json:"property"` // one of `displayName`, `tags` or `managementZones`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+}
+
+// EntityChange lists the changed properties of an entity
+type EntityChange struct {
+	Entity  *Node     `json:"entity"`
+	Changes []*Change `json:"changes"`
+}
+
+// Move is an entity whose placement changed, e.g. a process now running on a different host
+type Move struct {
+	Entity *Node    `json:"entity"`
+	Type   EdgeType `json:"type"`
+	Old    []string `json:"old"` // the IDs of the previous targets of the relationship
+	New    []string `json:"new"` // the IDs of the current targets of the relationship
+}
+
+// Diff is the structural difference between two topologies
+type Diff struct {
+	AddedEntities   []*Node         `json:"addedEntities"`
+	RemovedEntities []*Node         `json:"removedEntities"`
+	ChangedEntities []*EntityChange `json:"changedEntities"`
+	AddedEdges      []*Edge         `json:"addedEdges"`
+	RemovedEdges    []*Edge         `json:"removedEdges"`
+	Moved           []*Move         `json:"moved"`
+}
+
+// Compare computes the difference between an older and a newer graph.
+// Entities only known by a relationship, like host groups, are covered by the edges only.
+func Compare(older *Graph, newer *Graph) *Diff {
+	diff := &Diff{
+		AddedEntities:   []*Node{},
+		RemovedEntities: []*Node{},
+		ChangedEntities: []*EntityChange{},
+		AddedEdges:      []*Edge{},
+		RemovedEdges:    []*Edge{},
+		Moved:           []*Move{},
+	}
+	for _, node := range newer.Nodes() {
+		if node.Name == "" {
+			continue
+		}
+		previous := older.Node(node.ID)
+		if previous == nil || previous.Name == "" {
+			diff.AddedEntities = append(diff.AddedEntities, node)
+			continue
+		}
+		changes := []*Change{}
+		if previous.Name != node.Name {
+			changes = append(changes, &Change{Property: "displayName", Old: previous.Name, New: node.Name})
+		}
+		if o, n := formatTags(previous.Tags), formatTags(node.Tags); o != n {
+			changes = append(changes, &Change{Property: "tags", Old: o, New: n})
+		}
+		if o, n := formatManagementZones(previous.ManagementZones), formatManagementZones(node.ManagementZones); o != n {
+			changes = append(changes, &Change{Property: "managementZones", Old: o, New: n})
+		}
+		if len(changes) > 0 {
+			diff.ChangedEntities = append(diff.ChangedEntities, &EntityChange{Entity: node, Changes: changes})
+		}
+		for _, edgeType := range placementTypes {
+			o, n := targets(older.Out(node.ID, edgeType)), targets(newer.Out(node.ID, edgeType))
+			if len(o) > 0 && len(n) > 0 && strings.Join(o, ",") != strings.Join(n, ",") {
+				diff.Moved = append(diff.Moved, &Move{Entity: node, Type: edgeType, Old: o, New: n})
+			}
+		}
+	}
+	for _, node := range older.Nodes() {
+		if node.Name == "" {
+			continue
+		}
+		if current := newer.Node(node.ID); current == nil || current.Name == "" {
+			diff.RemovedEntities = append(diff.RemovedEntities, node)
+		}
+	}
+	for _, edge := range newer.Edges() {
+		if _, found := older.edges[*edge]; !found {
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		}
+	}
+	for _, edge := range older.Edges() {
+		if _, found := newer.edges[*edge]; !found {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		}
+	}
+	return diff
+}
+
+// Empty reports whether the topologies are structurally equal
+func (me *Diff) Empty() bool {
+	return len(me.AddedEntities) == 0 && len(me.RemovedEntities) == 0 && len(me.ChangedEntities) == 0 &&
+		len(me.AddedEdges) == 0 && len(me.RemovedEdges) == 0 && len(me.Moved) == 0
+}
+
+func (me *Diff) String() string {
+	lines := []string{}
+	for _, node := range me.AddedEntities {
+		lines = append(lines, fmt.Sprintf("+ %s %s (%s)", node.Type, node.Label(), node.ID))
+	}
+	for _, node := range me.RemovedEntities {
+		lines = append(lines, fmt.Sprintf("- %s %s (%s)", node.Type, node.Label(), node.ID))
+	}
+	for _, changed := range me.ChangedEntities {
+		lines = append(lines, fmt.Sprintf("~ %s %s (%s)", changed.Entity.Type, changed.Entity.Label(), changed.Entity.ID))
+		for _, change := range changed.Changes {
+			lines = append(lines, fmt.Sprintf("    %s: '%s' -> '%s'", change.Property, change.Old, change.New))
+		}
+	}
+	for _, move := range me.Moved {
+		lines = append(lines, fmt.Sprintf("> %s %s (%s) %s: %s -> %s", move.Entity.Type, move.Entity.Label(), move.Entity.ID, move.Type, strings.Join(move.Old, ", "), strings.Join(move.New, ", ")))
+	}
+	for _, edge := range me.AddedEdges {
+		lines = append(lines, fmt.Sprintf("+ %s -%s-> %s", edge.From, edge.Type, edge.To))
+	}
+	for _, edge := range me.RemovedEdges {
+		lines = append(lines, fmt.Sprintf("- %s -%s-> %s", edge.From, edge.Type, edge.To))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func targets(edges []*Edge) []string {
+	ids := []string{}
+	for _, edge := range edges {
+		ids = append(ids, edge.To)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func formatTags(tags []Tag) string {
+	parts := []string{}
+	for _, t := range tags {
+		s := t.Key
+		if t.Context != "" && t.Context != "CONTEXTLESS" {
+			s = "[" + t.Context + "]" + s
+		}
+		if t.Value != nil {
+			s = s + ":" + *t.Value
+		}
+		parts = append(parts, s)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func formatManagementZones(mzs []ManagementZone) string {
+	names := []string{}
+	for _, mz := range mzs {
+		names = append(names, mz.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
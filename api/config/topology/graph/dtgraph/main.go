@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/api/config/topology/graph"
+)
+
+type values []string
+
+func (me *values) String() string {
+	return strings.Join(*me, ",")
+}
+
+func (me *values) Set(value string) error {
+	*me = append(*me, value)
+	return nil
+}
+
+func main() {
+	var environmentURL, apiToken string
+	var depth int
+	var format, root, snapshotFile string
+	var jsonOutput bool
+	var managementZones, tags, types values
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&environmentURL, "environment-url", os.Getenv("DT_ENVIRONMENT_URL"), "")
+	flagSet.StringVar(&apiToken, "api-token", os.Getenv("DT_API_TOKEN"), "")
+	flagSet.IntVar(&depth, "depth", 0, "")
+	flagSet.StringVar(&format, "format", string(graph.Formats.DOT), "")
+	flagSet.StringVar(&root, "root", "", "")
+	flagSet.StringVar(&snapshotFile, "snapshot", "", "")
+	flagSet.BoolVar(&jsonOutput, "json", false, "")
+	flagSet.Var(&managementZones, "mz", "")
+	flagSet.Var(&tags, "tag", "")
+	flagSet.Var(&types, "type", "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	args := flagSet.Args()
+	if len(args) < 1 {
+		usage()
+		os.Exit(2)
+	}
+	query := args[0]
+	expected := 2
+	switch query {
+	case "export":
+		expected = 1
+	case "path", "diff":
+		expected = 3
+	}
+	online := query == "snapshot" || (query != "diff" && snapshotFile == "")
+	if len(args) != expected || (online && (environmentURL == "" || apiToken == "")) {
+		usage()
+		os.Exit(2)
+	}
+	baseURL := strings.TrimSuffix(environmentURL, "/") + "/api/v1"
+
+	if query == "diff" {
+		if err := diff(args[1], args[2], jsonOutput); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	if query == "snapshot" {
+		snapshot, err := graph.TakeSnapshot(baseURL, apiToken)
+		if err == nil {
+			err = graph.WriteSnapshot(args[1], snapshot)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	var snapshot *graph.Snapshot
+	var err error
+	if snapshotFile != "" {
+		snapshot, err = graph.ReadSnapshot(snapshotFile)
+	} else {
+		snapshot, err = graph.TakeSnapshot(baseURL, apiToken)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	g := graph.Build(snapshot.Topology)
+
+	if query == "export" {
+		if root != "" && g.Node(root) == nil {
+			fmt.Printf("unknown entity '%s'\n", root)
+			os.Exit(1)
+		}
+		sub := g.Subgraph(&graph.Filter{ManagementZones: managementZones, Tags: tags, Types: types, Root: root, Depth: depth})
+		if err := sub.Write(os.Stdout, graph.Format(format)); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	if g.Node(args[1]) == nil {
+		fmt.Printf("unknown entity '%s'\n", args[1])
+		os.Exit(1)
+	}
+	var hops []*graph.Hop
+	switch query {
+	case "callers":
+		hops = g.Callers(args[1], depth)
+	case "dependencies":
+		hops = g.Dependencies(args[1], depth)
+	case "blast-radius":
+		hops = g.BlastRadius(args[1])
+	case "path":
+		path := g.ShortestPath(args[1], args[2])
+		if path == nil {
+			fmt.Printf("%s and %s are not connected\n", args[1], args[2])
+			os.Exit(1)
+		}
+		for _, edge := range path {
+			fmt.Printf("%s -%s-> %s\n", g.Node(edge.From).Label(), edge.Type, g.Node(edge.To).Label())
+		}
+		return
+	default:
+		usage()
+		os.Exit(2)
+	}
+	for _, hop := range hops {
+		fmt.Printf("%s%s %s (%s) via %s\n", strings.Repeat("  ", hop.Depth-1), hop.Node.Type, hop.Node.Label(), hop.Node.ID, hop.Via.Type)
+	}
+	fmt.Println()
+	fmt.Printf("%d entities\n", len(hops))
+}
+
+// diff prints the differences between two snapshots
+func diff(oldFile string, newFile string, jsonOutput bool) error {
+	older, err := graph.ReadSnapshot(oldFile)
+	if err != nil {
+		return err
+	}
+	newer, err := graph.ReadSnapshot(newFile)
+	if err != nil {
+		return err
+	}
+	d := graph.Compare(graph.Build(older.Topology), graph.Build(newer.Topology))
+	if jsonOutput {
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("changes between %s and %s\n", older.Time.Format(time.RFC3339), newer.Time.Format(time.RFC3339))
+	if d.Empty() {
+		fmt.Println("none")
+		return nil
+	}
+	fmt.Println(d.String())
+	return nil
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtgraph [-environment-url <environment-url>] [-api-token <api-token>] [-snapshot <file>] [-depth <n>] <query> <entity-id> [<entity-id>]")
+	fmt.Println("       dtgraph [-environment-url <environment-url>] [-api-token <api-token>] [-snapshot <file>] [-format dot|mermaid|graphml] [-mz <zone>]... [-tag <tag>]... [-type <type>]... [-root <entity-id> [-depth <n>]] export")
+	fmt.Println("       dtgraph [-environment-url <environment-url>] [-api-token <api-token>] snapshot <file>")
+	fmt.Println("       dtgraph [-json] diff <old-file> <new-file>")
+	fmt.Println("  Queries the topology of an environment.")
+	fmt.Println("  <query>  one of")
+	fmt.Println("    callers <entity-id>            the entities calling the entity, directly or indirectly")
+	fmt.Println("    dependencies <entity-id>       the entities the entity calls, directly or indirectly")
+	fmt.Println("    blast-radius <entity-id>       the entities affected by an outage of the entity")
+	fmt.Println("    path <entity-id> <entity-id>   the shortest path between two entities")
+	fmt.Println("    export                         renders the topology, dot by default")
+	fmt.Println("    snapshot <file>                stores the current topology in <file>")
+	fmt.Println("    diff <file> <file>             reports the changes between two snapshots, with -json as JSON")
+	fmt.Println("  -depth  limits callers, dependencies and the exported entities around -root to <n> hops")
+	fmt.Println("  -snapshot  queries or exports the topology stored in <file> instead of fetching it")
+	fmt.Println("  -mz, -tag, -type  restrict the exported entities to the given management zones (name or ID), tags or entity types")
+	fmt.Println("  Hint: you can also define the environment variables DT_ENVIRONMENT_URL and DT_API_TOKEN")
+}
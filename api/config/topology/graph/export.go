@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is an output format of a graph
+type Format string
+
+// Formats offers the known enum values
+var Formats = struct {
+	DOT     Format
+	Mermaid Format
+	GraphML Format
+}{
+	"dot",
+	"mermaid",
+	"graphml",
+}
+
+// shapes used for the entity types within GraphViz DOT, other types are rendered as ellipse
+var shapes = map[string]string{
+	NodeTypes.Application:          "house",
+	NodeTypes.Host:                 "box3d",
+	NodeTypes.ProcessGroup:         "component",
+	NodeTypes.ProcessGroupInstance: "component",
+	NodeTypes.Service:              "box",
+}
+
+// Write renders the graph in the given format
+func (me *Graph) Write(w io.Writer, format Format) error {
+	switch format {
+	case Formats.DOT:
+		return me.WriteDOT(w)
+	case Formats.Mermaid:
+		return me.WriteMermaid(w)
+	case Formats.GraphML:
+		return me.WriteGraphML(w)
+	}
+	return fmt.Errorf("unknown format '%s'", format)
+}
+
+// WriteDOT renders the graph as GraphViz DOT.
+// Entities are labeled with their display names, relationships with their type.
+func (me *Graph) WriteDOT(w io.Writer) error {
+	lines := []string{"digraph topology {", "  rankdir=LR;"}
+	for _, node := range me.Nodes() {
+		shape, found := shapes[node.Type]
+		if !found {
+			shape = "ellipse"
+		}
+		lines = append(lines, fmt.Sprintf("  %s [label=%s, shape=%s, tooltip=%s];", dotQuote(node.ID), dotQuote(node.Label()), shape, dotQuote(node.Type+" "+node.ID)))
+	}
+	for _, edge := range me.Edges() {
+		lines = append(lines, fmt.Sprintf("  %s -> %s [label=%s];", dotQuote(edge.From), dotQuote(edge.To), dotQuote(string(edge.Type))))
+	}
+	lines = append(lines, "}")
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// WriteMermaid renders the graph as Mermaid flowchart.
+// Entity IDs are turned into Mermaid node IDs by replacing `-` with `_`.
+func (me *Graph) WriteMermaid(w io.Writer) error {
+	lines := []string{"graph LR"}
+	for _, node := range me.Nodes() {
+		lines = append(lines, fmt.Sprintf("  %s[\"%s\"]", mermaidID(node.ID), mermaidEscape(node.Label())))
+	}
+	for _, edge := range me.Edges() {
+		lines = append(lines, fmt.Sprintf("  %s -->|%s| %s", mermaidID(edge.From), mermaidEscape(string(edge.Type)), mermaidID(edge.To)))
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func mermaidID(id string) string {
+	return strings.ReplaceAll(id, "-", "_")
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;", "\n", " ").Replace(s)
+}
+
+type graphML struct {
+	XMLName xml.Name       `xml:"graphml"`
+	XMLNS   string         `xml:"xmlns,attr"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graph   graphMLContent `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	Name     string `xml:"attr.name,attr"`
+	DataType string `xml:"attr.type,attr"`
+}
+
+type graphMLContent struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML renders the graph as GraphML.
+// Entities carry their display name (`label`) and type (`type`), relationships their type (`relationship`).
+func (me *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", DataType: "string"},
+			{ID: "type", For: "node", Name: "type", DataType: "string"},
+			{ID: "relationship", For: "edge", Name: "relationship", DataType: "string"},
+		},
+		Graph: graphMLContent{ID: "topology", EdgeDefault: "directed"},
+	}
+	for _, node := range me.Nodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: []graphMLData{{Key: "label", Value: node.Label()}, {Key: "type", Value: node.Type}}})
+	}
+	for _, edge := range me.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: edge.From, Target: edge.To, Data: []graphMLData{{Key: "relationship", Value: string(edge.Type)}}})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,133 @@
+package graph
+
+import "strings"
+
+// Filter selects the entities of a subgraph.
+// An entity needs to satisfy every criterion specified, lists are satisfied by any of their elements.
+type Filter struct {
+	ManagementZones []string // names or IDs of management zones
+	Tags            []string // tags written as `key`, `key:value` or `[CONTEXT]key:value`
+	Types           []string // entity types, e.g. `SERVICE`
+	// Root restricts the subgraph to the entities connected to the given entity,
+	// following relationships in both directions, within Depth hops if Depth is greater than 0.
+	// The root entity itself is always selected.
+	Root  string
+	Depth int
+}
+
+// Subgraph returns a graph consisting of the entities selected by the filter and the relationships between them
+func (me *Graph) Subgraph(filter *Filter) *Graph {
+	var reachable map[string]bool
+	if filter.Root != "" {
+		reachable = map[string]bool{}
+		if me.nodes[filter.Root] != nil {
+			reachable[filter.Root] = true
+			for _, id := range me.traverseUndirected(filter.Root, filter.Depth) {
+				reachable[id] = true
+			}
+		}
+	}
+
+	sub := New()
+	for _, node := range me.Nodes() {
+		if node.ID == filter.Root && reachable[node.ID] {
+			sub.AddNode(node)
+			continue
+		}
+		if reachable != nil && !reachable[node.ID] {
+			continue
+		}
+		if len(filter.Types) > 0 && !containsString(filter.Types, node.Type) {
+			continue
+		}
+		if len(filter.ManagementZones) > 0 && !inManagementZone(node, filter.ManagementZones) {
+			continue
+		}
+		if len(filter.Tags) > 0 && !hasTag(node, filter.Tags) {
+			continue
+		}
+		sub.AddNode(node)
+	}
+	for _, edge := range me.Edges() {
+		if sub.nodes[edge.From] != nil && sub.nodes[edge.To] != nil {
+			sub.AddEdge(edge.From, edge.To, edge.Type)
+		}
+	}
+	return sub
+}
+
+// traverseUndirected returns the IDs of the entities within maxDepth hops, following relationships in both directions
+func (me *Graph) traverseUndirected(id string, maxDepth int) []string {
+	ids := []string{}
+	visited := map[string]bool{id: true}
+	frontier := []string{id}
+	for depth := 1; len(frontier) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		next := []string{}
+		for _, current := range frontier {
+			for _, edge := range append(me.Out(current), me.In(current)...) {
+				for _, other := range []string{edge.From, edge.To} {
+					if !visited[other] {
+						visited[other] = true
+						ids = append(ids, other)
+						next = append(next, other)
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+	return ids
+}
+
+func inManagementZone(node *Node, mzs []string) bool {
+	for _, mz := range node.ManagementZones {
+		if containsString(mzs, mz.Name) || containsString(mzs, mz.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTag(node *Node, tags []string) bool {
+	for _, s := range tags {
+		context, key, value := parseTag(s)
+		for _, t := range node.Tags {
+			if context != "" && !strings.EqualFold(context, t.Context) {
+				continue
+			}
+			if key != t.Key {
+				continue
+			}
+			if value != nil && (t.Value == nil || *value != *t.Value) {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// parseTag splits `[CONTEXT]key:value` into its parts, context and value are optional
+func parseTag(s string) (string, string, *string) {
+	context := ""
+	if strings.HasPrefix(s, "[") {
+		if idx := strings.Index(s, "]"); idx > 0 {
+			context = s[1:idx]
+			s = s[idx+1:]
+		}
+	}
+	if idx := strings.Index(s, ":"); idx >= 0 {
+		value := s[idx+1:]
+		return context, s[:idx], &value
+	}
+	return context, s, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
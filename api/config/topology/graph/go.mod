@@ -0,0 +1,20 @@
+module github.com/dtcookie/dynatrace/api/config/topology/graph
+
+go 1.16
+
+require (
+	github.com/dtcookie/dynatrace/api/config/topology/application v1.0.0
+	github.com/dtcookie/dynatrace/api/config/topology/host v1.0.0
+	github.com/dtcookie/dynatrace/api/config/topology/process v1.0.0
+	github.com/dtcookie/dynatrace/api/config/topology/processgroup v1.0.0
+	github.com/dtcookie/dynatrace/api/config/topology/service v1.0.0
+)
+
+replace (
+	github.com/dtcookie/dynatrace/api/config/topology/application => ../application
+	github.com/dtcookie/dynatrace/api/config/topology/host => ../host
+	github.com/dtcookie/dynatrace/api/config/topology/process => ../process
+	github.com/dtcookie/dynatrace/api/config/topology/processgroup => ../processgroup
+	github.com/dtcookie/dynatrace/api/config/topology/service => ../service
+	github.com/dtcookie/dynatrace/rest => ../../../../rest
+)
@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// NodeTypes offers the types of the entities the topology clients return.
+// The type of an entity is the prefix of its ID, which also applies to entities only known by ID, like `HOST_GROUP`.
+var NodeTypes = struct {
+	Application          string
+	Host                 string
+	ProcessGroup         string
+	ProcessGroupInstance string
+	Service              string
+}{
+	"APPLICATION",
+	"HOST",
+	"PROCESS_GROUP",
+	"PROCESS_GROUP_INSTANCE",
+	"SERVICE",
+}
+
+// TypeOf returns the entity type of an entity ID, e.g. `HOST` for `HOST-0123456789ABCDEF`
+func TypeOf(id string) string {
+	if idx := strings.LastIndex(id, "-"); idx > 0 {
+		return id[:idx]
+	}
+	return ""
+}
+
+// Tag is a single definition of a tag used for a Dynatrace entity
+type Tag struct {
+	Context string  `json:"context"`
+	Key     string  `json:"key"`
+	Value   *string `json:"value,omitempty"`
+}
+
+// ManagementZone is a short representation of a management zone
+type ManagementZone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Node is an entity within the topology
+type Node struct {
+	ID              string           `json:"entityId"`
+	Type            string           `json:"type"`
+	Name            string           `json:"displayName,omitempty"` // empty for entities only known by a relationship
+	Tags            []Tag            `json:"tags,omitempty"`
+	ManagementZones []ManagementZone `json:"managementZones,omitempty"`
+}
+
+// Label returns the display name of the entity, or its ID if the name is unknown
+func (me *Node) Label() string {
+	if me.Name == "" {
+		return me.ID
+	}
+	return me.Name
+}
+
+// EdgeType is the type of a relationship, named after the key within `fromRelationships` of the source entity
+type EdgeType string
+
+// EdgeTypes offers the known enum values
+var EdgeTypes = struct {
+	Calls                         EdgeType
+	IsInstanceOf                  EdgeType
+	IsNetworkClientOf             EdgeType
+	IsNetworkClientOfHost         EdgeType
+	IsNetworkClientOfProcessGroup EdgeType
+	IsProcessOf                   EdgeType
+	RunsOn                        EdgeType
+	RunsOnHost                    EdgeType
+	RunsOnProcessGroupInstance    EdgeType
+}{
+	"calls",
+	"isInstanceOf",
+	"isNetworkClientOf",
+	"isNetworkClientOfHost",
+	"isNetworkClientOfProcessGroup",
+	"isProcessOf",
+	"runsOn",
+	"runsOnHost",
+	"runsOnProcessGroupInstance",
+}
+
+// Edge is a directed relationship between two entities, e.g. a service (From) `calls` another service (To)
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Type EdgeType `json:"type"`
+}
+
+// Graph is an in-memory graph of entities and their relationships
+type Graph struct {
+	nodes map[string]*Node
+	edges map[Edge]*Edge
+	out   map[string][]*Edge
+	in    map[string][]*Edge
+}
+
+// New creates an empty graph
+func New() *Graph {
+	return &Graph{
+		nodes: map[string]*Node{},
+		edges: map[Edge]*Edge{},
+		out:   map[string][]*Edge{},
+		in:    map[string][]*Edge{},
+	}
+}
+
+// AddNode adds an entity to the graph.
+// If the entity is already known, only its type, name, tags and management zones are updated.
+func (me *Graph) AddNode(node *Node) *Node {
+	if node.Type == "" {
+		node.Type = TypeOf(node.ID)
+	}
+	if existing, found := me.nodes[node.ID]; found {
+		existing.Type = node.Type
+		if node.Name != "" {
+			existing.Name = node.Name
+		}
+		if node.Tags != nil {
+			existing.Tags = node.Tags
+		}
+		if node.ManagementZones != nil {
+			existing.ManagementZones = node.ManagementZones
+		}
+		return existing
+	}
+	me.nodes[node.ID] = node
+	return node
+}
+
+// AddEdge adds a relationship to the graph, unless it's already known.
+// Entities not added yet are added with ID and type only.
+func (me *Graph) AddEdge(from string, to string, edgeType EdgeType) *Edge {
+	key := Edge{From: from, To: to, Type: edgeType}
+	if edge, found := me.edges[key]; found {
+		return edge
+	}
+	for _, id := range []string{from, to} {
+		if _, found := me.nodes[id]; !found {
+			me.AddNode(&Node{ID: id})
+		}
+	}
+	edge := &key
+	me.edges[key] = edge
+	me.out[from] = append(me.out[from], edge)
+	me.in[to] = append(me.in[to], edge)
+	return edge
+}
+
+// Node returns the entity with the given ID, nil if it's unknown
+func (me *Graph) Node(id string) *Node {
+	return me.nodes[id]
+}
+
+// Nodes returns all entities, sorted by ID
+func (me *Graph) Nodes() []*Node {
+	nodes := []*Node{}
+	for _, node := range me.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// Edges returns all relationships, sorted by source, type and target
+func (me *Graph) Edges() []*Edge {
+	edges := []*Edge{}
+	for _, edge := range me.edges {
+		edges = append(edges, edge)
+	}
+	sortEdges(edges)
+	return edges
+}
+
+// Out returns the relationships starting at the given entity, optionally restricted to the given types
+func (me *Graph) Out(id string, types ...EdgeType) []*Edge {
+	return filter(me.out[id], types)
+}
+
+// In returns the relationships ending at the given entity, optionally restricted to the given types
+func (me *Graph) In(id string, types ...EdgeType) []*Edge {
+	return filter(me.in[id], types)
+}
+
+func filter(edges []*Edge, types []EdgeType) []*Edge {
+	result := []*Edge{}
+	for _, edge := range edges {
+		if len(types) == 0 {
+			result = append(result, edge)
+			continue
+		}
+		for _, t := range types {
+			if edge.Type == t {
+				result = append(result, edge)
+				break
+			}
+		}
+	}
+	sortEdges(result)
+	return result
+}
+
+func sortEdges(edges []*Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].Type != edges[j].Type {
+			return edges[i].Type < edges[j].Type
+		}
+		return edges[i].To < edges[j].To
+	})
+}
@@ -0,0 +1,166 @@
+package graph_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/api/config/topology/application"
+	"github.com/dtcookie/dynatrace/api/config/topology/graph"
+	"github.com/dtcookie/dynatrace/api/config/topology/host"
+	"github.com/dtcookie/dynatrace/api/config/topology/process"
+	"github.com/dtcookie/dynatrace/api/config/topology/processgroup"
+	"github.com/dtcookie/dynatrace/api/config/topology/service"
+)
+
+func topology() *graph.Topology {
+	team := "web"
+	return &graph.Topology{
+		Applications: application.Applications{
+			{EntityId: "APPLICATION-1", DisplayName: "shop", FromRelationships: map[string][]string{"calls": {"SERVICE-1"}}},
+		},
+		Hosts: host.Hosts{
+			{EntityId: "HOST-1", DisplayName: "web-01", FromRelationships: map[string][]string{"isInstanceOf": {"HOST_GROUP-1"}}},
+			{EntityId: "HOST-2", DisplayName: "db-01", Tags: []host.Tag{{Context: "AWS", Key: "database"}}, ManagementZones: []host.ManagementZone{{ID: "2", Name: "infrastructure"}}},
+		},
+		Processes: process.Processes{
+			{EntityId: "PROCESS_GROUP_INSTANCE-1", DisplayName: "tomcat", FromRelationships: map[string][]string{"isProcessOf": {"HOST-1"}, "isInstanceOf": {"PROCESS_GROUP-1"}}},
+		},
+		ProcessGroups: processgroup.ProcessGroups{
+			{EntityId: "PROCESS_GROUP-1", DisplayName: "tomcat", FromRelationships: map[string][]string{"runsOn": {"HOST-1"}}},
+		},
+		Services: service.Services{
+			{EntityId: "SERVICE-1", DisplayName: "frontend", Tags: []service.Tag{{Context: "CONTEXTLESS", Key: "team", Value: &team}}, ManagementZones: []service.ManagementZone{{ID: "1", Name: "shop"}}, FromRelationships: map[string][]string{"calls": {"SERVICE-2"}, "runsOn": {"PROCESS_GROUP-1"}}},
+			// the relationship to SERVICE-1 is also reported by SERVICE-1 itself
+			{EntityId: "SERVICE-2", DisplayName: "check\"out", ManagementZones: []service.ManagementZone{{ID: "1", Name: "shop"}}, FromRelationships: map[string][]string{"calls": {"SERVICE-3"}}, ToRelationships: map[string][]string{"calls": {"SERVICE-1"}}},
+			{EntityId: "SERVICE-3", DisplayName: "orders", FromRelationships: map[string][]string{"runsOnHost": {"HOST-2"}}},
+		},
+	}
+}
+
+func ids(hops []*graph.Hop) []string {
+	result := []string{}
+	for _, hop := range hops {
+		result = append(result, hop.Node.ID)
+	}
+	return result
+}
+
+func TestGraph(t *testing.T) {
+	g := graph.Build(topology())
+	if len(g.Edges()) != 9 {
+		t.Errorf("expected 9 edges, got %d", len(g.Edges()))
+	}
+	if node := g.Node("HOST_GROUP-1"); node == nil || node.Type != "HOST_GROUP" || node.Label() != "HOST_GROUP-1" {
+		t.Errorf("expected a node for the host group, got %v", node)
+	}
+	if callers := ids(g.Callers("SERVICE-3", 0)); len(callers) != 3 || callers[2] != "APPLICATION-1" {
+		t.Errorf("unexpected callers %v", callers)
+	}
+	if dependencies := ids(g.Dependencies("SERVICE-1", 1)); len(dependencies) != 1 || dependencies[0] != "SERVICE-2" {
+		t.Errorf("unexpected dependencies %v", dependencies)
+	}
+	expected := []string{"PROCESS_GROUP-1", "PROCESS_GROUP_INSTANCE-1", "SERVICE-1", "APPLICATION-1"}
+	if radius := ids(g.BlastRadius("HOST-1")); len(radius) != len(expected) {
+		t.Errorf("expected blast radius %v, got %v", expected, radius)
+	} else {
+		for idx := range expected {
+			if radius[idx] != expected[idx] {
+				t.Errorf("expected blast radius %v, got %v", expected, radius)
+				break
+			}
+		}
+	}
+	path := g.ShortestPath("HOST-2", "HOST-1")
+	if len(path) != 5 || path[0].Type != graph.EdgeTypes.RunsOnHost || path[4].To != "HOST-1" {
+		t.Errorf("unexpected path %v", path)
+	}
+	if g.ShortestPath("HOST-1", "HOST-9") != nil {
+		t.Error("expected no path to an unknown entity")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := graph.Build(topology())
+	if node := g.Node("HOST-2"); len(node.Tags) != 1 || node.Tags[0].Context != "AWS" || node.Tags[0].Key != "database" || node.Tags[0].Value != nil {
+		t.Errorf("unexpected tags %v", node.Tags)
+	}
+	if node := g.Node("HOST-2"); len(node.ManagementZones) != 1 || node.ManagementZones[0].Name != "infrastructure" {
+		t.Errorf("unexpected management zones %v", node.ManagementZones)
+	}
+	if node := g.Node("SERVICE-1"); len(node.Tags) != 1 || *node.Tags[0].Value != "web" {
+		t.Errorf("unexpected tags %v", node.Tags)
+	}
+	if node := g.Node("APPLICATION-1"); len(node.Tags) != 0 || len(node.ManagementZones) != 0 {
+		t.Errorf("expected no tags and management zones, got %+v", node)
+	}
+}
+
+func TestExport(t *testing.T) {
+	g := graph.Build(topology())
+
+	sub := g.Subgraph(&graph.Filter{ManagementZones: []string{"shop"}})
+	if len(sub.Nodes()) != 2 || len(sub.Edges()) != 1 {
+		t.Errorf("expected the two services of management zone shop, got %d entities and %d edges", len(sub.Nodes()), len(sub.Edges()))
+	}
+	if sub := g.Subgraph(&graph.Filter{Tags: []string{"team:web"}, Types: []string{"SERVICE"}}); len(sub.Nodes()) != 1 {
+		t.Errorf("expected a single service tagged team:web, got %d", len(sub.Nodes()))
+	}
+	if sub := g.Subgraph(&graph.Filter{Root: "SERVICE-1", Depth: 1, Types: []string{"SERVICE"}}); len(sub.Nodes()) != 2 {
+		t.Errorf("expected SERVICE-1 and SERVICE-2 within one hop, got %d", len(sub.Nodes()))
+	}
+
+	for format, expected := range map[graph.Format]string{
+		graph.Formats.DOT:     `"SERVICE-1" -> "SERVICE-2" [label="calls"];`,
+		graph.Formats.Mermaid: `SERVICE_1 -->|calls| SERVICE_2`,
+		graph.Formats.GraphML: `<data key="label">check&#34;out</data>`,
+	} {
+		var buf bytes.Buffer
+		if err := sub.Write(&buf, format); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected %s output to contain\n%s\ngot\n%s", format, expected, buf.String())
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := graph.WriteSnapshot(file, &graph.Snapshot{Topology: topology()}); err != nil {
+		t.Fatal(err)
+	}
+	older, err := graph.ReadSnapshot(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newer := topology()
+	// the process moved to HOST-2, SERVICE-1 got renamed, SERVICE-3 removed and SERVICE-4 added
+	newer.Processes[0].FromRelationships["isProcessOf"] = []string{"HOST-2"}
+	newer.Services[0].DisplayName = "storefront"
+	newer.Services[1].FromRelationships = map[string][]string{"calls": {"SERVICE-4"}}
+	newer.Services[2] = service.Service{EntityId: "SERVICE-4", DisplayName: "payments"}
+
+	if diff := graph.Compare(graph.Build(older.Topology), graph.Build(older.Topology)); !diff.Empty() {
+		t.Errorf("expected no differences, got\n%s", diff.String())
+	}
+	diff := graph.Compare(graph.Build(older.Topology), graph.Build(newer))
+	if len(diff.AddedEntities) != 1 || diff.AddedEntities[0].ID != "SERVICE-4" {
+		t.Errorf("expected SERVICE-4 to be added, got %v", diff.AddedEntities)
+	}
+	if len(diff.RemovedEntities) != 1 || diff.RemovedEntities[0].ID != "SERVICE-3" {
+		t.Errorf("expected SERVICE-3 to be removed, got %v", diff.RemovedEntities)
+	}
+	if len(diff.ChangedEntities) != 1 || diff.ChangedEntities[0].Changes[0].New != "storefront" {
+		t.Errorf("expected SERVICE-1 to be renamed, got %v", diff.ChangedEntities)
+	}
+	if len(diff.Moved) != 1 || diff.Moved[0].Entity.ID != "PROCESS_GROUP_INSTANCE-1" || diff.Moved[0].New[0] != "HOST-2" {
+		t.Errorf("expected the process to move to HOST-2, got\n%s", diff.String())
+	}
+	// the calls to SERVICE-3 and of SERVICE-3 to HOST-2 are gone, the process on HOST-2 and the call to SERVICE-4 are new
+	if len(diff.AddedEdges) != 2 || len(diff.RemovedEdges) != 3 {
+		t.Errorf("unexpected edges\n%s", diff.String())
+	}
+}
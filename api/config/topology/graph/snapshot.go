@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// Snapshot is the topology of an environment at a specific point in time
+type Snapshot struct {
+	Time     time.Time `json:"time"`
+	Topology *Topology `json:"topology"`
+}
+
+// TakeSnapshot fetches the current topology of an environment
+// baseURL should look like this: "https://siz65484.live.dynatrace.com/api/v1"
+// token is an API Token
+func TakeSnapshot(baseURL string, token string) (*Snapshot, error) {
+	topology, err := Fetch(baseURL, token)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Time: time.Now().UTC(), Topology: topology}, nil
+}
+
+// ReadSnapshot reads a snapshot previously stored with WriteSnapshot
+func ReadSnapshot(file string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Topology == nil {
+		snapshot.Topology = &Topology{}
+	}
+	return &snapshot, nil
+}
+
+// WriteSnapshot stores a snapshot as JSON
+func WriteSnapshot(file string, snapshot *Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
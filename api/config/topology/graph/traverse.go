@@ -0,0 +1,124 @@
+package graph
+
+// Direction determines which way relationships are followed during a traversal
+type Direction int
+
+// Directions offers the known enum values
+var Directions = struct {
+	Downstream Direction // from source to target, e.g. from a service to the services it calls
+	Upstream   Direction // from target to source, e.g. from a service to its callers
+}{
+	0,
+	1,
+}
+
+// Hop is an entity reached during a traversal
+type Hop struct {
+	Node  *Node
+	Depth int   // the number of relationships followed to reach the entity
+	Via   *Edge // the relationship the entity got reached by first
+}
+
+// dependencyTypes are the relationships along which an outage propagates from target to source
+var dependencyTypes = []EdgeType{
+	EdgeTypes.Calls,
+	EdgeTypes.IsProcessOf,
+	EdgeTypes.RunsOn,
+	EdgeTypes.RunsOnHost,
+	EdgeTypes.RunsOnProcessGroupInstance,
+}
+
+// Traverse visits the entities reachable from the given entity in breadth first order,
+// following relationships of the given types only, or of any type if none are given.
+// A maxDepth of 0 or less doesn't limit the depth. The start entity isn't contained in the result.
+func (me *Graph) Traverse(id string, direction Direction, maxDepth int, types ...EdgeType) []*Hop {
+	hops := []*Hop{}
+	visited := map[string]bool{id: true}
+	frontier := []string{id}
+	for depth := 1; len(frontier) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		next := []string{}
+		for _, current := range frontier {
+			edges := me.Out(current, types...)
+			if direction == Directions.Upstream {
+				edges = me.In(current, types...)
+			}
+			for _, edge := range edges {
+				other := edge.To
+				if direction == Directions.Upstream {
+					other = edge.From
+				}
+				if visited[other] {
+					continue
+				}
+				visited[other] = true
+				hops = append(hops, &Hop{Node: me.nodes[other], Depth: depth, Via: edge})
+				next = append(next, other)
+			}
+		}
+		frontier = next
+	}
+	return hops
+}
+
+// Callers returns the entities calling the given entity, directly or indirectly
+func (me *Graph) Callers(id string, maxDepth int) []*Hop {
+	return me.Traverse(id, Directions.Upstream, maxDepth, EdgeTypes.Calls)
+}
+
+// Dependencies returns the entities the given entity calls, directly or indirectly
+func (me *Graph) Dependencies(id string, maxDepth int) []*Hop {
+	return me.Traverse(id, Directions.Downstream, maxDepth, EdgeTypes.Calls)
+}
+
+// BlastRadius returns the entities affected by an outage of the given entity.
+// For a host these are the processes and process groups running on it, the services they provide
+// and every entity calling these services, directly or indirectly.
+func (me *Graph) BlastRadius(id string) []*Hop {
+	return me.Traverse(id, Directions.Upstream, 0, dependencyTypes...)
+}
+
+// ShortestPath returns the relationships connecting two entities with the fewest hops.
+// Relationships are followed in both directions, the returned edges keep their original direction.
+// The result is nil if the entities aren't connected and empty if both IDs are the same.
+func (me *Graph) ShortestPath(from string, to string) []*Edge {
+	if _, found := me.nodes[from]; !found {
+		return nil
+	}
+	if from == to {
+		return []*Edge{}
+	}
+	via := map[string]*Edge{}
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		edges := append(me.Out(current), me.In(current)...)
+		for _, edge := range edges {
+			other := edge.To
+			if other == current {
+				other = edge.From
+			}
+			if visited[other] {
+				continue
+			}
+			visited[other] = true
+			via[other] = edge
+			if other == to {
+				path := []*Edge{}
+				for id := to; id != from; {
+					edge := via[id]
+					path = append([]*Edge{edge}, path...)
+					if edge.To == id {
+						id = edge.From
+					} else {
+						id = edge.To
+					}
+				}
+				return path
+			}
+			queue = append(queue, other)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/log"
+)
+
+// AuthConfig configures how the listener authenticates incoming requests.
+// Every mechanism configured needs to succeed, none of them is enabled by default.
+type AuthConfig struct {
+	// HeaderName and HeaderSecret require a custom header with a shared secret,
+	// as configured in the custom integration within Dynatrace
+	HeaderName   string `json:"headerName,omitempty"`
+	HeaderSecret string `json:"headerSecret,omitempty"`
+	// Username and Password require HTTP basic authentication
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// SignatureSecret requires the hex encoded HMAC-SHA256 of the request body within SignatureHeader,
+	// optionally prefixed with `sha256=`. SignatureHeader defaults to `X-Signature`.
+	SignatureHeader string `json:"signatureHeader,omitempty"`
+	SignatureSecret string `json:"signatureSecret,omitempty"`
+	// AllowedIPs are the IP addresses or CIDR ranges requests are accepted from
+	AllowedIPs []string `json:"allowedIPs,omitempty"`
+	// HistoryToken requires `Authorization: Bearer <token>` for `/history` instead of the header secret and basic authentication.
+	// `/history` is served only if at least one of these credentials is configured, the signature doesn't suffice as a GET has no body to sign.
+	HistoryToken string `json:"historyToken,omitempty"`
+}
+
+// TLSConfig makes the listener serve HTTPS.
+// With ClientCAFile only clients presenting a certificate signed by one of these CAs are accepted (mTLS).
+type TLSConfig struct {
+	CertFile     string `json:"certFile"`
+	KeyFile      string `json:"keyFile"`
+	ClientCAFile string `json:"clientCAFile,omitempty"`
+}
+
+// RejectReason is the reason a request got rejected by the authentication of the listener
+type RejectReason string
+
+// RejectReasons offers the known enum values
+var RejectReasons = struct {
+	IPNotAllowed     RejectReason
+	InvalidSecret    RejectReason
+	InvalidBasicAuth RejectReason
+	InvalidSignature RejectReason
+	InvalidToken     RejectReason
+	NoCredential     RejectReason
+}{
+	"ip_not_allowed",
+	"invalid_secret",
+	"invalid_basic_auth",
+	"invalid_signature",
+	"invalid_token",
+	"no_credential",
+}
+
+type authenticator struct {
+	config   *AuthConfig
+	networks []*net.IPNet
+	metrics  *metrics
+}
+
+func newAuthenticator(config *AuthConfig, metrics *metrics) (*authenticator, error) {
+	auth := &authenticator{config: config, metrics: metrics}
+	if config == nil {
+		return auth, nil
+	}
+	if (config.HeaderName == "") != (config.HeaderSecret == "") {
+		return nil, errors.New("auth: headerName and headerSecret need to be specified together")
+	}
+	for _, s := range config.AllowedIPs {
+		if !strings.Contains(s, "/") {
+			if ip := net.ParseIP(s); ip != nil && ip.To4() != nil {
+				s = s + "/32"
+			} else {
+				s = s + "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("auth: invalid allowed IP '%s'", s)
+		}
+		auth.networks = append(auth.networks, network)
+	}
+	return auth, nil
+}
+
+// authorize checks the properties of the request available before reading the body
+func (auth *authenticator) authorize(request *http.Request) (RejectReason, bool) {
+	config := auth.config
+	if config == nil {
+		return "", true
+	}
+	if len(auth.networks) > 0 && !auth.allowed(request.RemoteAddr) {
+		return RejectReasons.IPNotAllowed, false
+	}
+	if config.HeaderName != "" && !equal(request.Header.Get(config.HeaderName), config.HeaderSecret) {
+		return RejectReasons.InvalidSecret, false
+	}
+	if config.Username != "" || config.Password != "" {
+		username, password, ok := request.BasicAuth()
+		if !ok || !equal(username, config.Username) || !equal(password, config.Password) {
+			return RejectReasons.InvalidBasicAuth, false
+		}
+	}
+	return "", true
+}
+
+// servesHistory tells whether a credential `/history` can be protected with is configured.
+// The signature doesn't qualify, as a GET has no body to sign, and neither do the allowed IPs alone.
+func (auth *authenticator) servesHistory() bool {
+	config := auth.config
+	if config == nil {
+		return false
+	}
+	return config.HistoryToken != "" || config.HeaderName != "" || config.Username != "" || config.Password != ""
+}
+
+// authorizeHistory checks a request for `/history`, which is served only if servesHistory
+func (auth *authenticator) authorizeHistory(request *http.Request) (RejectReason, bool) {
+	if !auth.servesHistory() {
+		return RejectReasons.NoCredential, false
+	}
+	config := auth.config
+	if config.HistoryToken == "" {
+		return auth.authorize(request)
+	}
+	if len(auth.networks) > 0 && !auth.allowed(request.RemoteAddr) {
+		return RejectReasons.IPNotAllowed, false
+	}
+	token := request.Header.Get("Authorization")
+	if !strings.HasPrefix(token, "Bearer ") || !equal(strings.TrimPrefix(token, "Bearer "), config.HistoryToken) {
+		return RejectReasons.InvalidToken, false
+	}
+	return "", true
+}
+
+// verify checks the signature of the request body
+func (auth *authenticator) verify(request *http.Request, body []byte) (RejectReason, bool) {
+	config := auth.config
+	if config == nil || config.SignatureSecret == "" {
+		return "", true
+	}
+	header := config.SignatureHeader
+	if header == "" {
+		header = "X-Signature"
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(request.Header.Get(header), "sha256="))
+	if err != nil || len(signature) == 0 {
+		return RejectReasons.InvalidSignature, false
+	}
+	mac := hmac.New(sha256.New, []byte(config.SignatureSecret))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return RejectReasons.InvalidSignature, false
+	}
+	return "", true
+}
+
+// reject counts, logs and answers a rejected request
+func (auth *authenticator) reject(w http.ResponseWriter, request *http.Request, reason RejectReason) {
+	auth.metrics.reject(reason)
+	log.Warn(fmt.Sprintf("rejected request from %s: %s", request.RemoteAddr, reason))
+
+	status := http.StatusUnauthorized
+	switch reason {
+	case RejectReasons.IPNotAllowed, RejectReasons.NoCredential:
+		status = http.StatusForbidden
+	case RejectReasons.InvalidBasicAuth:
+		w.Header().Set("WWW-Authenticate", `Basic realm="problem notifications"`)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+func (auth *authenticator) allowed(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range auth.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(actual string, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
+
+// newTLSConfig loads the CAs for verifying client certificates, if configured
+func newTLSConfig(config *TLSConfig) (*tls.Config, error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		return nil, errors.New("tls: certFile and keyFile need to be specified")
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.ClientCAFile != "" {
+		data, err := ioutil.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("tls: no certificates found in '%s'", config.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
@@ -1,56 +1,63 @@
 package main
 
 import (
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"strings"
+	"regexp"
 
-	"github.com/dtcookie/dynatrace/http"
+	"github.com/dtcookie/dynatrace/log"
 	"github.com/dtcookie/dynatrace/notification"
 )
 
+// defaultTagPattern matches the barcodes of applications, e.g. `APP=Online Banking (OBK)`
+const defaultTagPattern = `^APP=.*\(([^()]{3})\)$`
+
 // BSMhandler TODO: documentation
 type BSMhandler struct {
 	notification.Handler
-	Target string
-	client *http.Client
+	config         *BSMConfig
+	sink           *notification.Sink
+	tagPatterns    []*regexp.Regexp
+	entityPatterns []*regexp.Regexp
 }
 
-// Handle TODO: documentation
-func (handler *BSMhandler) Handle(event *notification.ProblemEvent) error {
+func newBSMHandler(config *BSMConfig, listenerConfig *notification.Config) (*BSMhandler, error) {
 	var err error
-	var jsonstr string
-	if jsonstr, err = toJSON(event); err != nil {
-		return err
+	handler := &BSMhandler{config: config}
+	sinkConfig := &notification.SinkConfig{
+		URL:         config.Target,
+		Username:    config.Username,
+		Password:    config.Password,
+		Headers:     config.Headers,
+		ContentType: "application/xml",
+		Timeout:     config.Timeout,
 	}
-	if false {
-		return handler.client.Post(handler.Target, []byte(jsonstr))
+	if handler.sink, err = notification.NewSink("bsm", sinkConfig, listenerConfig); err != nil {
+		return nil, err
 	}
-	fmt.Println(jsonstr)
-	fmt.Println()
-
-	var tags string
-	tags = strings.Trim(event.Notification.Tags, " ")
-	var bcode string
-	barcodes := strings.Split(tags, ",")
-	for _, barcode := range barcodes {
-		barcode = strings.Trim(barcode, " ")
-		if strings.HasPrefix(barcode, "APP=") {
-			idxOpenBrace := strings.LastIndex(barcode, "(")
-			if (idxOpenBrace > 0) && (idxOpenBrace < len(barcode)) {
-				barcode = barcode[idxOpenBrace+1:]
-				if strings.HasSuffix(barcode, ")") {
-					barcode = barcode[0 : len(barcode)-1]
-					bcode = barcode
-					break
-				}
-			}
-		}
+	ciConfig := config.CI
+	if ciConfig == nil {
+		ciConfig = &CIConfig{}
 	}
+	tagPatterns := ciConfig.TagPatterns
+	if len(tagPatterns) == 0 {
+		tagPatterns = []string{defaultTagPattern}
+	}
+	if handler.tagPatterns, err = compile(tagPatterns); err != nil {
+		return nil, err
+	}
+	if handler.entityPatterns, err = compile(ciConfig.EntityPatterns); err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
 
-	if len(bcode) != 3 {
-		fmt.Println("No barcode found in " + event.Notification.Tags)
+// Handle forwards the problem to BSM, provided a CI can be found for it.
+// Failed deliveries are reported as error, which makes the delivery queue of the listener retry them.
+func (handler *BSMhandler) Handle(event *notification.ProblemEvent) error {
+	ci := handler.ci(event)
+	if ci == "" {
+		log.Warn(fmt.Sprintf("no CI found for problem %s, tags '%s'", event.Notification.PID, event.Notification.Tags))
 		return nil
 	}
 
@@ -58,35 +65,61 @@ func (handler *BSMhandler) Handle(event *notification.ProblemEvent) error {
 		Title:         event.Notification.Title,
 		Description:   "For detailed information visit: " + event.Notification.URL,
 		PID:           event.Notification.PID,
-		Severity:      event.Notification.State,
-		RelatedEntity: bcode,
+		Severity:      handler.severity(event.Notification),
+		RelatedEntity: ci,
 	}
 
-	xmlStr, err := toXML(&bsmEvent)
-	fmt.Println(xmlStr)
-
+	data, err := xml.MarshalIndent(&bsmEvent, "", "  ")
 	if err != nil {
-		fmt.Println("Sending to " + handler.Target)
-		return handler.client.Post(handler.Target, []byte(xmlStr))
+		return err
 	}
+	log.Info(fmt.Sprintf("sending problem %s for CI %s to %s", bsmEvent.PID, ci, handler.config.Target))
+	return handler.sink.Send(data)
+}
 
-	return err
+// ci finds the configuration item in the tags or the names of the impacted entities
+func (handler *BSMhandler) ci(event *notification.ProblemEvent) string {
+	tags := []string{}
+	for _, tag := range notification.TagsOf(event) {
+		tags = append(tags, tag.String())
+	}
+	if ci := match(handler.tagPatterns, tags); ci != "" {
+		return ci
+	}
+	return match(handler.entityPatterns, notification.EntityNamesOf(event))
 }
 
-func toJSON(v interface{}) (string, error) {
-	var err error
-	var bytes []byte
-	if bytes, err = json.MarshalIndent(v, "", "  "); err != nil {
-		return "", err
+func (handler *BSMhandler) severity(n *notification.Default) string {
+	for _, key := range []string{n.State + "/" + n.Severity, n.Severity, n.State} {
+		if severity, found := handler.config.Severities[key]; found {
+			return severity
+		}
 	}
-	return string(bytes), nil
+	return n.State
 }
 
-func toXML(v interface{}) (string, error) {
-	var err error
-	var bytes []byte
-	if bytes, err = xml.MarshalIndent(v, "", "  "); err != nil {
-		return "", err
+func match(patterns []*regexp.Regexp, values []string) string {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if m := pattern.FindStringSubmatch(value); m != nil {
+				if len(m) > 1 {
+					return m[1]
+				}
+				return m[0]
+			}
+		}
+	}
+	return ""
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := []*regexp.Regexp{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CI pattern '%s': %s", pattern, err.Error())
+		}
+		compiled = append(compiled, re)
 	}
-	return string(bytes), nil
+	return compiled, nil
 }
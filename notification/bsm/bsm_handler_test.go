@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/apis/problems"
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func problemOf(t *testing.T, s string) *problems.Problem {
+	var problem problems.Problem
+	if err := json.Unmarshal([]byte(s), &problem); err != nil {
+		t.Fatal(err)
+	}
+	return &problem
+}
+
+func TestCI(t *testing.T) {
+	handler, err := newBSMHandler(&BSMConfig{
+		Target: "http://localhost",
+		CI:     &CIConfig{EntityPatterns: []string{`^([A-Z]{3})-`}},
+	}, &notification.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, err := newBSMHandler(&BSMConfig{
+		Target: "http://localhost",
+		CI:     &CIConfig{TagPatterns: []string{`^\[AWS\]app:(\w+)$`}},
+	}, &notification.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shop := "shop"
+	tests := []struct {
+		name     string
+		handler  *BSMhandler
+		event    *notification.ProblemEvent
+		expected string
+	}{
+		{
+			name:     "tag of the notification",
+			handler:  handler,
+			event:    &notification.ProblemEvent{Notification: &notification.Default{Tags: "env:prod, APP=Online Banking (OBK)"}},
+			expected: "OBK",
+		},
+		{
+			name:    "tag of the problem details",
+			handler: handler,
+			event: &notification.ProblemEvent{
+				Notification: &notification.Default{},
+				Problem:      problemOf(t, `{"tagsOfAffectedEntities":[{"context":"CONTEXTLESS","key":"APP=Payments (PAY)"}]}`),
+			},
+			expected: "PAY",
+		},
+		{
+			name:    "tag of an impacted entity",
+			handler: handler,
+			event: &notification.ProblemEvent{
+				Notification: &notification.Default{},
+				Entities:     []*notification.Entity{{Name: "web", Tags: []notification.EntityTag{{Context: "CONTEXTLESS", Key: "APP=Loans (LNS)"}}}},
+			},
+			expected: "LNS",
+		},
+		{
+			name:     "tags before entity names",
+			handler:  handler,
+			event:    &notification.ProblemEvent{Notification: &notification.Default{Tags: "APP=Online Banking (OBK)", ImpactedEntity: "PAY-frontend"}},
+			expected: "OBK",
+		},
+		{
+			name:     "barcode of another length",
+			handler:  handler,
+			event:    &notification.ProblemEvent{Notification: &notification.Default{Tags: "APP=Online Banking (OBKX)"}},
+			expected: "",
+		},
+		{
+			name:     "name of the impacted entity",
+			handler:  handler,
+			event:    &notification.ProblemEvent{Notification: &notification.Default{ImpactedEntity: "PAY-frontend"}},
+			expected: "PAY",
+		},
+		{
+			name:    "name of a ranked impact",
+			handler: handler,
+			event: &notification.ProblemEvent{
+				Notification: &notification.Default{},
+				Problem:      problemOf(t, `{"rankedImpacts":[{"entityName":"frontend"},{"entityName":"LNS-backend"}]}`),
+			},
+			expected: "LNS",
+		},
+		{
+			name:     "no match",
+			handler:  handler,
+			event:    &notification.ProblemEvent{Notification: &notification.Default{Tags: "env:prod", ImpactedEntity: "frontend"}},
+			expected: "",
+		},
+		{
+			name:    "context of problem detail tags",
+			handler: custom,
+			event: &notification.ProblemEvent{
+				Notification: &notification.Default{},
+				Problem:      problemOf(t, `{"tagsOfAffectedEntities":[{"context":"CONTEXTLESS","key":"app","value":"other"},{"context":"AWS","key":"app","value":"shop"}]}`),
+			},
+			expected: "shop",
+		},
+		{
+			name:    "context of entity tags",
+			handler: custom,
+			event: &notification.ProblemEvent{
+				Notification: &notification.Default{},
+				Entities:     []*notification.Entity{{Tags: []notification.EntityTag{{Context: "AWS", Key: "app", Value: &shop}}}},
+			},
+			expected: "shop",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if ci := test.handler.ci(test.event); ci != test.expected {
+				t.Errorf("expected CI '%s', got '%s'", test.expected, ci)
+			}
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	handler, err := newBSMHandler(&BSMConfig{
+		Target: "http://localhost",
+		Severities: map[string]string{
+			"OPEN/AVAILABILITY": "critical",
+			"ERROR":             "major",
+			"OPEN":              "minor",
+			"RESOLVED":          "normal",
+		},
+	}, &notification.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		state    string
+		severity string
+		expected string
+	}{
+		{"OPEN", "AVAILABILITY", "critical"},   // STATE/SEVERITY
+		{"OPEN", "ERROR", "major"},             // SEVERITY before STATE
+		{"RESOLVED", "ERROR", "major"},         // SEVERITY before STATE
+		{"OPEN", "PERFORMANCE", "minor"},       // STATE
+		{"RESOLVED", "AVAILABILITY", "normal"}, // STATE
+		{"MERGED", "PERFORMANCE", "MERGED"},    // the state without match
+	} {
+		n := &notification.Default{State: test.state, Severity: test.severity}
+		if severity := handler.severity(n); severity != test.expected {
+			t.Errorf("%s/%s: expected '%s', got '%s'", test.state, test.severity, test.expected, severity)
+		}
+	}
+}
+
+func TestHandle(t *testing.T) {
+	var received []Event
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		data, _ := ioutil.ReadAll(request.Body)
+		var event Event
+		if err := xml.Unmarshal(data, &event); err != nil {
+			t.Error(err)
+		}
+		received = append(received, event)
+	}))
+	defer target.Close()
+
+	handler, err := newBSMHandler(&BSMConfig{Target: target.URL, Severities: map[string]string{"OPEN/AVAILABILITY": "critical"}}, &notification.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []*notification.ProblemEvent{
+		{Notification: &notification.Default{PID: "1", State: "OPEN", Severity: "AVAILABILITY", Tags: "APP=Online Banking (OBK)"}},
+		{Notification: &notification.Default{PID: "2", State: "OPEN", Severity: "AVAILABILITY", Tags: "env:prod"}},
+	}
+	for _, event := range events {
+		if err := handler.Handle(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected only the problem with CI to be sent, got %d events", len(received))
+	}
+	if event := received[0]; event.PID != "1" || event.RelatedEntity != "OBK" || event.Severity != "critical" {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
@@ -1,40 +1,93 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
 	"github.com/dtcookie/dynatrace/notification"
 )
 
-func parseConfig(handler *BSMhandler) *notification.Config {
+// BSMConfig is the `bsm` section of the config file
+type BSMConfig struct {
+	Target   string            `json:"target"`
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Timeout  string            `json:"timeout,omitempty"`
+	CI       *CIConfig         `json:"ci,omitempty"`
+	// Severities maps `STATE/SEVERITY`, `SEVERITY` or `STATE` of a problem, checked in this order,
+	// e.g. `OPEN/AVAILABILITY`, to the severity sent to BSM. Without match the state is sent.
+	Severities map[string]string `json:"severities,omitempty"`
+}
+
+// CIConfig defines how the configuration item related to a problem is found.
+// The patterns are regular expressions, the first capture group (or the whole match) is the CI.
+// Tag patterns are tried before entity patterns.
+type CIConfig struct {
+	TagPatterns    []string `json:"tagPatterns,omitempty"`    // matched against the tags, `^APP=.*\(([^()]{3})\)$` if not specified
+	EntityPatterns []string `json:"entityPatterns,omitempty"` // matched against the names of the impacted entities
+}
+
+func parseConfig() (*notification.Config, *BSMConfig) {
 	var err error
 	var config *notification.Config
+	var target string
 
 	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
-	flagSet.StringVar(&handler.Target, "target", "", "")
+	flagSet.StringVar(&target, "target", "", "")
 	if config, err = notification.ParseConfig(flagSet); err != nil {
 		if !strings.HasPrefix(err.Error(), "flag provided but not defined") {
 			fmt.Println(err.Error())
 			usage()
 		}
-		return nil
+		return nil, nil
 	}
 
-	if handler.Target == "" {
+	bsmConfig := &BSMConfig{}
+	if config.ConfigFile != "" {
+		if bsmConfig, err = readBSMConfig(config.ConfigFile); err != nil {
+			fmt.Println(err.Error())
+			return nil, nil
+		}
+	}
+	if target != "" {
+		bsmConfig.Target = target
+	}
+	if bsmConfig.Target == "" {
 		fmt.Println("no target specified")
 		usage()
-		return nil
+		return nil, nil
 	}
 
-	return config
+	return config, bsmConfig
+}
+
+func readBSMConfig(file string) (*BSMConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var content struct {
+		BSM *BSMConfig `json:"bsm"`
+	}
+	if err = json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	if content.BSM == nil {
+		return nil, errors.New("no 'bsm' section found in " + file)
+	}
+	return content.BSM, nil
 }
 
 func usage() {
 	fmt.Println()
-	fmt.Println("USAGE: bsm [-api-base-url <api-base-url>] [-api-token <api-token>] [-listen <listen-port>] [-config <config-json-file>")
+	fmt.Println("USAGE: bsm [-api-base-url <api-base-url>] [-api-token <api-token>] [-listen <listen-port>] [-target <bsm-url>] [-config <config-json-file>")
 	fmt.Println("  Hint: you can also define the environment variables DT_API_BASE_URL, DT_API_TOKEN and DT_LISTEN_PORT")
 	fmt.Println("  Hint: you can also specify the -config flag referring to a JSON file containing the parameters")
+	fmt.Println("        the 'bsm' section of that file configures the target, its authentication, the CI extraction and the severity mapping")
 }
@@ -1,16 +1,25 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/dtcookie/dynatrace/notification"
 )
 
 func main() {
-	var config *notification.Config
-	var handler BSMhandler
+	config, bsmConfig := parseConfig()
+	if config == nil {
+		return
+	}
 
-	if config = parseConfig(&handler); config == nil {
+	handler, err := newBSMHandler(bsmConfig, config)
+	if err != nil {
+		fmt.Println(err.Error())
 		return
 	}
+	if config.Queue == nil {
+		fmt.Println("Hint: configure a 'queue' in the config file to retry failed deliveries to BSM")
+	}
 
-	notification.Listen(config, &handler)
+	notification.Listen(config, handler)
 }
@@ -0,0 +1,142 @@
+package cloudevents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+// Options customize how problem events are mapped to CloudEvents
+type Options struct {
+	// Source is the source of the events, by default derived from the ProblemURL of the notifications,
+	// e.g. `https://abc12345.live.dynatrace.com` or `https://managed.example.com/e/<environment-id>`
+	Source string
+	// TypePrefix is completed with the lower-cased state of the problem, `com.dynatrace.problem.` if not specified,
+	// resulting in types like `com.dynatrace.problem.open`
+	TypePrefix string
+}
+
+type cloudEventsAdapter struct {
+	notification.Handler
+	handler Handler
+	options *Options
+}
+
+// NewCloudEventsAdapter TODO: documentation
+func NewCloudEventsAdapter(handler Handler, options *Options) notification.Handler {
+	if options == nil {
+		options = &Options{}
+	}
+	return &cloudEventsAdapter{handler: handler, options: options}
+}
+
+// Handle TODO: documentation
+func (adapter *cloudEventsAdapter) Handle(event *notification.ProblemEvent) error {
+	var err error
+	var cloudEvent *Event
+	if cloudEvent, err = ToCloudEvent(event, adapter.options); err != nil {
+		return err
+	}
+	return adapter.handler.Handle(cloudEvent)
+}
+
+// ToCloudEvent maps a problem event to a CloudEvent.
+// The subject is the PID, the data are the problem details, or the notification if no details are available.
+// The ID is unique per transition of the problem, which allows receivers to drop repeated notifications,
+// but not a problem opened again after it got resolved. The transition is counted by the lifecycle, if state
+// tracking is configured, otherwise it is identified by the time the problem got opened or closed according
+// to the problem details. Lacking these, the ID is derived from a hash of the data.
+// Events without state get the type and ID of the state `unknown`.
+func ToCloudEvent(event *notification.ProblemEvent, options *Options) (*Event, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	n := event.Notification
+	if n == nil {
+		n = &notification.Default{}
+	}
+	state := n.State
+	if state == "" && event.Problem != nil {
+		state = event.Problem.Status.String()
+	}
+	if state = strings.ToLower(state); state == "" {
+		state = "unknown"
+	}
+	pid := n.PID
+	if pid == "" && event.Problem != nil {
+		pid = event.Problem.ID
+	}
+
+	typePrefix := options.TypePrefix
+	if typePrefix == "" {
+		typePrefix = "com.dynatrace.problem."
+	}
+	source := options.Source
+	if source == "" {
+		source = sourceOf(n.URL)
+	}
+
+	var data interface{} = n
+	if event.Problem != nil && event.Problem.ID != "" {
+		data = event.Problem
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudEvent := &Event{
+		Source:          source,
+		Type:            typePrefix + state,
+		Subject:         pid,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            payload,
+		Extensions:      map[string]string{},
+	}
+	var at int64 // the time the problem got opened or closed, in UTC milliseconds
+	if event.Problem != nil {
+		if state == "open" {
+			at = event.Problem.StartTime
+		} else {
+			at = event.Problem.EndTime
+		}
+	}
+	if at > 0 {
+		cloudEvent.Time = time.Unix(0, at*int64(time.Millisecond)).UTC()
+	}
+	if lifecycle := event.Lifecycle; lifecycle != nil && len(lifecycle.Transitions) > 0 {
+		cloudEvent.ID = pid + "-" + state + "-" + strconv.Itoa(len(lifecycle.Transitions))
+	} else if at > 0 {
+		cloudEvent.ID = pid + "-" + state + "-" + strconv.FormatInt(at, 10)
+	} else {
+		sum := sha256.Sum256(payload)
+		cloudEvent.ID = pid + "-" + state + "-" + hex.EncodeToString(sum[:8])
+	}
+	for key, value := range map[string]string{"problemid": n.ProblemID, "severity": n.Severity, "impact": n.Impact} {
+		if value != "" {
+			cloudEvent.Extensions[key] = value
+		}
+	}
+	return cloudEvent, nil
+}
+
+// sourceOf derives the URL of the environment from the URL of a problem,
+// e.g. `https://abc12345.live.dynatrace.com/#problems/problemdetails;pid=...` or `https://managed.example.com/e/<environment-id>/#problems/...`
+func sourceOf(problemURL string) string {
+	u, err := url.Parse(problemURL)
+	if err != nil || u.Host == "" {
+		return "urn:dynatrace"
+	}
+	source := u.Scheme + "://" + u.Host
+	if parts := strings.Split(strings.Trim(u.Path, "/"), "/"); len(parts) >= 2 && parts[0] == "e" {
+		source = source + "/e/" + parts[1]
+	}
+	return source
+}
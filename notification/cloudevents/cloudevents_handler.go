@@ -0,0 +1,99 @@
+package cloudevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Handler TODO: documentation
+type Handler interface {
+	Handle(event *Event) error
+}
+
+// Mode is the content mode CloudEvents are transferred with over HTTP
+type Mode string
+
+// Modes offers the known enum values
+var Modes = struct {
+	Structured Mode
+	Binary     Mode
+}{
+	"structured",
+	"binary",
+}
+
+// HTTPHandler posts CloudEvents to an HTTP endpoint, e.g. a Knative broker.
+// Responses with a status code other than 2xx are reported as errors, which makes the delivery queue of the listener retry them.
+type HTTPHandler struct {
+	URL     string
+	Mode    Mode
+	Headers map[string]string
+	Client  *http.Client
+}
+
+// NewHTTPHandler creates a handler posting CloudEvents to the given URL.
+// In structured mode the whole event is sent as `application/cloudevents+json`,
+// in binary mode the attributes are sent as `ce-` headers and the data as body.
+func NewHTTPHandler(URL string, mode Mode) *HTTPHandler {
+	return &HTTPHandler{URL: URL, Mode: mode, Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Handle TODO: documentation
+func (handler *HTTPHandler) Handle(event *Event) error {
+	var body []byte
+	var err error
+	header := http.Header{}
+	switch handler.Mode {
+	case Modes.Structured:
+		if body, err = json.Marshal(event); err != nil {
+			return err
+		}
+		header.Set("Content-Type", "application/cloudevents+json; charset=UTF-8")
+	case Modes.Binary:
+		body = event.Data
+		header.Set("ce-specversion", SpecVersion)
+		header.Set("ce-id", event.ID)
+		header.Set("ce-source", event.Source)
+		header.Set("ce-type", event.Type)
+		if event.Subject != "" {
+			header.Set("ce-subject", event.Subject)
+		}
+		if !event.Time.IsZero() {
+			header.Set("ce-time", event.Time.UTC().Format(time.RFC3339Nano))
+		}
+		for key, value := range event.Extensions {
+			header.Set("ce-"+key, value)
+		}
+		if event.DataContentType != "" {
+			header.Set("Content-Type", event.DataContentType)
+		}
+	default:
+		return fmt.Errorf("unknown content mode '%s'", handler.Mode)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, handler.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	for key, value := range handler.Headers {
+		request.Header.Set(key, value)
+	}
+	response, err := handler.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	data, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("POST %s responded with %d: %s", handler.URL, response.StatusCode, string(data))
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package cloudevents_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/apis/problems"
+	"github.com/dtcookie/dynatrace/notification"
+	"github.com/dtcookie/dynatrace/notification/cloudevents"
+)
+
+func TestCloudEvents(t *testing.T) {
+	var header http.Header
+	var body []byte
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		header = request.Header
+		body, _ = ioutil.ReadAll(request.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer target.Close()
+
+	event := &notification.ProblemEvent{
+		Notification: &notification.Default{
+			PID:      "-4242",
+			State:    "RESOLVED",
+			Severity: "AVAILABILITY",
+			URL:      "https://managed.example.com/e/abc-123/#problems/problemdetails;pid=-4242",
+		},
+		Problem: &problems.Problem{ID: "-4242", StartTime: 1593000000000, EndTime: 1593000060000},
+	}
+
+	handler := cloudevents.NewHTTPHandler(target.URL, cloudevents.Modes.Structured)
+	if err := cloudevents.NewCloudEventsAdapter(handler, nil).Handle(event); err != nil {
+		t.Fatal(err)
+	}
+	if contentType := header.Get("Content-Type"); contentType != "application/cloudevents+json; charset=UTF-8" {
+		t.Errorf("unexpected content type '%s'", contentType)
+	}
+	var structured map[string]interface{}
+	if err := json.Unmarshal(body, &structured); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"specversion": "1.0",
+		"id":          "-4242-resolved-1593000060000",
+		"type":        "com.dynatrace.problem.resolved",
+		"source":      "https://managed.example.com/e/abc-123",
+		"subject":     "-4242",
+		"time":        "2020-06-24T12:01:00Z",
+		"severity":    "AVAILABILITY",
+	}
+	for key, value := range expected {
+		if structured[key] != value {
+			t.Errorf("expected %s '%s', got '%v'", key, value, structured[key])
+		}
+	}
+	if data, ok := structured["data"].(map[string]interface{}); !ok || data["id"] != "-4242" {
+		t.Errorf("expected the problem as data, got %v", structured["data"])
+	}
+
+	handler = cloudevents.NewHTTPHandler(target.URL, cloudevents.Modes.Binary)
+	options := &cloudevents.Options{Source: "urn:tenant:abc-123", TypePrefix: "dynatrace."}
+	if err := cloudevents.NewCloudEventsAdapter(handler, options).Handle(event); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{"ce-specversion": "1.0", "ce-type": "dynatrace.resolved", "ce-source": "urn:tenant:abc-123", "ce-subject": "-4242", "Content-Type": "application/json"} {
+		if header.Get(key) != value {
+			t.Errorf("expected header %s '%s', got '%s'", key, value, header.Get(key))
+		}
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil || data["id"] != "-4242" {
+		t.Errorf("expected the problem as body, got %s", string(body))
+	}
+}
+
+func TestCloudEventIDs(t *testing.T) {
+	store, err := notification.NewStateStore(&notification.StateConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	now := time.Date(2020, 6, 24, 12, 0, 0, 0, time.UTC)
+	for i, state := range []string{"OPEN", "OPEN", "RESOLVED", "OPEN", "RESOLVED"} {
+		event := &notification.ProblemEvent{Notification: &notification.Default{PID: "-4242", State: state}}
+		if _, err := store.Track(event, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		cloudEvent, err := cloudevents.ToCloudEvent(event, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids[cloudEvent.ID] != event.Lifecycle.Duplicate {
+			t.Errorf("notification %d (%s): expected the ID '%s' to repeat only for a duplicate", i, state, cloudEvent.ID)
+		}
+		ids[cloudEvent.ID] = true
+	}
+
+	// without lifecycle the transitions are told apart by their times
+	reopened := func(start int64) *notification.ProblemEvent {
+		return &notification.ProblemEvent{
+			Notification: &notification.Default{PID: "-4242", State: "OPEN"},
+			Problem:      &problems.Problem{ID: "-4242", StartTime: start},
+		}
+	}
+	first, _ := cloudevents.ToCloudEvent(reopened(1593000000000), nil)
+	second, _ := cloudevents.ToCloudEvent(reopened(1593000600000), nil)
+	if first.ID == second.ID {
+		t.Errorf("expected a problem opened again to get another ID than '%s'", first.ID)
+	}
+
+	// without lifecycle and problem details retries of a notification keep their ID
+	retried := func(state string) *notification.ProblemEvent {
+		return &notification.ProblemEvent{Notification: &notification.Default{PID: "-4242", ProblemID: "42", State: state, Title: "Service unavailable"}}
+	}
+	first, _ = cloudevents.ToCloudEvent(retried("OPEN"), nil)
+	time.Sleep(2 * time.Millisecond)
+	second, _ = cloudevents.ToCloudEvent(retried("OPEN"), nil)
+	if first.ID != second.ID {
+		t.Errorf("expected a retried notification to keep its ID, got '%s' and '%s'", first.ID, second.ID)
+	}
+	if resolved, _ := cloudevents.ToCloudEvent(retried("RESOLVED"), nil); resolved.ID == first.ID {
+		t.Errorf("expected the resolution to get another ID than '%s'", first.ID)
+	}
+
+	unknown, err := cloudevents.ToCloudEvent(&notification.ProblemEvent{Notification: &notification.Default{PID: "-4242"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown.Type != "com.dynatrace.problem.unknown" || !strings.HasPrefix(unknown.ID, "-4242-unknown-") {
+		t.Errorf("expected the state 'unknown' for an event without state, got type '%s' and ID '%s'", unknown.Type, unknown.ID)
+	}
+}
@@ -0,0 +1,47 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SpecVersion is the version of the CloudEvents specification implemented
+const SpecVersion = "1.0"
+
+// Event is a CloudEvent
+type Event struct {
+	ID              string
+	Source          string
+	Type            string
+	Subject         string
+	Time            time.Time
+	DataContentType string
+	Data            json.RawMessage
+	// Extensions are additional context attributes, their names consist of lower-case letters and digits only
+	Extensions map[string]string
+}
+
+// MarshalJSON renders the event in structured content mode, extensions become top-level attributes
+func (me *Event) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{}
+	for key, value := range me.Extensions {
+		m[key] = value
+	}
+	m["specversion"] = SpecVersion
+	m["id"] = me.ID
+	m["source"] = me.Source
+	m["type"] = me.Type
+	if me.Subject != "" {
+		m["subject"] = me.Subject
+	}
+	if !me.Time.IsZero() {
+		m["time"] = me.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if me.DataContentType != "" {
+		m["datacontenttype"] = me.DataContentType
+	}
+	if me.Data != nil {
+		m["data"] = me.Data
+	}
+	return json.Marshal(m)
+}
@@ -16,11 +16,23 @@ import (
 // Config TODO: documentation
 type Config struct {
 	ListenPort  int                     `json:"listenPort,omitempty"`
+	BindAddress string                  `json:"bindAddress,omitempty"` // the address to listen on, all interfaces if not specified
 	Credentials credentials.Credentials `json:"credentials,omitempty"`
 	NoProxy     bool                    `json:"noproxy,omitempty"`
 	Insecure    bool                    `json:"insecure,omitempty"`
 	Verbose     bool                    `json:"verbose,omitempty"`
 	APIBaseURL  string                  `json:"apiBaseURL,omitempty"`
+	Pipeline    *PipelineConfig         `json:"pipeline,omitempty"`
+	Queue       *QueueConfig            `json:"queue,omitempty"`
+	Auth        *AuthConfig             `json:"auth,omitempty"`
+	TLS         *TLSConfig              `json:"tls,omitempty"`
+	State       *StateConfig            `json:"state,omitempty"`
+	Sinks       map[string]*SinkConfig  `json:"sinks,omitempty"`
+	History     *HistoryConfig          `json:"history,omitempty"`
+	// ConfigFile is the file the config has been read from, allowing integrations to read their own sections
+	ConfigFile string `json:"-"`
+	// ShutdownTimeout limits how long the listener waits for the notifications in flight when terminating, `30s` if not specified
+	ShutdownTimeout string `json:"shutdownTimeout,omitempty"`
 }
 
 // NewConfig TODO: documentation
@@ -68,6 +80,7 @@ func readConfigFromEnv(target *Config) {
 	if apiToken != "" {
 		config.Credentials = credentials.New(apiToken)
 	}
+	config.BindAddress = os.Getenv("DT_BIND_ADDRESS")
 	sListenPort = os.Getenv("DT_LISTEN_PORT")
 	if sListenPort != "" {
 		if listenPort, err = strconv.Atoi(sListenPort); err != nil {
@@ -99,6 +112,7 @@ func readConfigFromFlags(target *Config, parentFlags *flag.FlagSet, args []strin
 	flagSet.BoolVar(&configFromFlags.NoProxy, "noproxy", false, "")
 	flagSet.StringVar(&configFileName, "config", "", "")
 	flagSet.IntVar(&configFromFlags.ListenPort, "listen", 0, "")
+	flagSet.StringVar(&configFromFlags.BindAddress, "bind", "", "")
 	flagSet.StringVar(&configFromFlags.APIBaseURL, "api-base-url", "", "")
 	var apiToken string
 	flagSet.StringVar(&apiToken, "api-token", "", "")
@@ -115,6 +129,7 @@ func readConfigFromFlags(target *Config, parentFlags *flag.FlagSet, args []strin
 	if configFileName != "" {
 		readConfigFromFile(&configFromFile, configFileName)
 		adoptConfig(target, &configFromFile)
+		target.ConfigFile = configFileName
 	}
 
 	adoptConfig(target, &configFromFlags)
@@ -129,6 +144,9 @@ func adoptConfig(target *Config, source *Config) {
 	if source.ListenPort != 0 {
 		target.ListenPort = source.ListenPort
 	}
+	if source.BindAddress != "" {
+		target.BindAddress = source.BindAddress
+	}
 	if source.NoProxy {
 		target.NoProxy = source.NoProxy
 	}
@@ -141,6 +159,30 @@ func adoptConfig(target *Config, source *Config) {
 	if source.APIBaseURL != "" {
 		target.APIBaseURL = source.APIBaseURL
 	}
+	if source.Pipeline != nil {
+		target.Pipeline = source.Pipeline
+	}
+	if source.Queue != nil {
+		target.Queue = source.Queue
+	}
+	if source.Auth != nil {
+		target.Auth = source.Auth
+	}
+	if source.TLS != nil {
+		target.TLS = source.TLS
+	}
+	if source.State != nil {
+		target.State = source.State
+	}
+	if source.Sinks != nil {
+		target.Sinks = source.Sinks
+	}
+	if source.History != nil {
+		target.History = source.History
+	}
+	if source.ShutdownTimeout != "" {
+		target.ShutdownTimeout = source.ShutdownTimeout
+	}
 }
 
 func fromJSON(config *Config, configFile *os.File) {
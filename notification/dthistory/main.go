@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+type values []string
+
+func (me *values) String() string {
+	return strings.Join(*me, ",")
+}
+
+func (me *values) Set(value string) error {
+	*me = append(*me, value)
+	return nil
+}
+
+func main() {
+	var directory, listenerURL, username, password, token string
+	var from, to, entity, pid string
+	var limit int
+	var jsonOutput bool
+	var states, tags, headers values
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&directory, "dir", "", "")
+	flagSet.StringVar(&listenerURL, "url", "", "")
+	flagSet.StringVar(&username, "user", "", "")
+	flagSet.StringVar(&password, "password", "", "")
+	flagSet.StringVar(&token, "token", "", "")
+	flagSet.Var(&headers, "header", "")
+	flagSet.StringVar(&from, "from", "", "")
+	flagSet.StringVar(&to, "to", "", "")
+	flagSet.Var(&states, "state", "")
+	flagSet.Var(&tags, "tag", "")
+	flagSet.StringVar(&entity, "entity", "", "")
+	flagSet.StringVar(&pid, "pid", "", "")
+	flagSet.IntVar(&limit, "limit", 0, "")
+	flagSet.BoolVar(&jsonOutput, "json", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if (directory == "") == (listenerURL == "") || len(flagSet.Args()) > 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	params := url.Values{}
+	for key, value := range map[string]string{"from": from, "to": to, "entity": entity, "pid": pid} {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+	if limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	params["state"] = states
+	params["tag"] = tags
+
+	var records []*notification.Record
+	var err error
+	if directory != "" {
+		records, err = query(directory, params)
+	} else {
+		records, err = fetch(listenerURL, params, username, password, token, headers)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	for _, record := range records {
+		n := record.Event.Notification
+		if n == nil {
+			n = &notification.Default{}
+		}
+		fmt.Printf("%s  %-10s %-8s %s (%s)\n", record.Received.Local().Format(time.RFC3339), n.PID, n.State, n.Title, n.ImpactedEntity)
+	}
+	fmt.Println()
+	fmt.Printf("%d notifications\n", len(records))
+}
+
+// query reads the history files of a listener directly
+func query(directory string, params url.Values) ([]*notification.Record, error) {
+	q, err := notification.ParseQuery(params, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	history, err := notification.NewHistory(&notification.HistoryConfig{Directory: directory})
+	if err != nil {
+		return nil, err
+	}
+	return history.Query(q)
+}
+
+// fetch queries the history API of a running listener
+func fetch(listenerURL string, params url.Values, username string, password string, token string, headers []string) ([]*notification.Record, error) {
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(listenerURL, "/")+"/history?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if username != "" || password != "" {
+		request.SetBasicAuth(username, password)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	for _, header := range headers {
+		idx := strings.Index(header, ":")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid header '%s', expected 'Name: value'", header)
+		}
+		request.Header.Set(strings.TrimSpace(header[:idx]), strings.TrimSpace(header[idx+1:]))
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", response.Status, strings.TrimSpace(string(data)))
+	}
+	var records []*notification.Record
+	if err = json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dthistory -dir <history-directory> [<criteria>] [-json]")
+	fmt.Println("       dthistory -url <listener-url> [-user <user> -password <password> | -token <history-token>] [-header '<name>: <value>']... [<criteria>] [-json]")
+	fmt.Println("  Queries the problem notifications received by a listener, either from its history directory or via its API.")
+	fmt.Println("  <criteria>")
+	fmt.Println("    -from <time> -to <time>  RFC 3339 or relative to now, e.g. -from -168h for the last week")
+	fmt.Println("    -state <state>           e.g. OPEN, RESOLVED or MERGED, may be repeated")
+	fmt.Println("    -tag <tag>               written as key, key:value or [CONTEXT]key:value, may be repeated")
+	fmt.Println("    -entity <entity>         the ID of an impacted entity or a part of its name")
+	fmt.Println("    -pid <pid>               the PID or ProblemID")
+	fmt.Println("    -limit <n>               only the <n> most recent notifications")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func main() {
+	var directory string
+	var list bool
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&directory, "dir", "", "")
+	flagSet.BoolVar(&list, "list", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if directory == "" || (list && len(flagSet.Args()) > 0) {
+		usage()
+		os.Exit(2)
+	}
+
+	if list {
+		deliveries, err := notification.DeadLetters(directory)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		for _, delivery := range deliveries {
+			fmt.Printf("%s  received %s  %d attempts  %s\n", delivery.ID, delivery.Received.Format(time.RFC3339), delivery.Attempts, delivery.LastError)
+		}
+		fmt.Println()
+		fmt.Printf("%d dead letters\n", len(deliveries))
+		return
+	}
+
+	replayed, err := notification.Replay(directory, flagSet.Args()...)
+	fmt.Printf("%d dead letters replayed\n", replayed)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtreplay -dir <queue-directory> -list")
+	fmt.Println("       dtreplay -dir <queue-directory> [<delivery-id>]...")
+	fmt.Println("  Lists or replays the problem notifications the listener failed to deliver.")
+	fmt.Println("  -dir   the directory of the delivery queue, as configured in the config file of the listener")
+	fmt.Println("  -list  lists the dead letters instead of replaying them")
+	fmt.Println("  Replays all dead letters unless specific delivery IDs are given.")
+	fmt.Println("  A running listener picks up the replayed notifications within a second.")
+}
@@ -0,0 +1,170 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/apis/problems"
+	"github.com/dtcookie/dynatrace/rest"
+)
+
+// Enricher adds information to a problem event
+type Enricher interface {
+	Enrich(event *ProblemEvent) error
+}
+
+// EnricherFunc allows to use an ordinary function as Enricher
+type EnricherFunc func(event *ProblemEvent) error
+
+// Enrich calls enricher(event)
+func (enricher EnricherFunc) Enrich(event *ProblemEvent) error {
+	return enricher(event)
+}
+
+// EnricherType identifies the built-in enrichers within the pipeline section of the config file
+type EnricherType string
+
+// EnricherTypes offers the known enum values
+var EnricherTypes = struct {
+	ProblemDetails EnricherType
+	Topology       EnricherType
+	OwnerTags      EnricherType
+}{
+	"problemDetails",
+	"topology",
+	"ownerTags",
+}
+
+// entityPaths are the Topology API endpoints for the entity types, keyed by the prefix of the entity ID
+var entityPaths = map[string]string{
+	"APPLICATION":            "/api/v1/entity/applications/",
+	"HOST":                   "/api/v1/entity/infrastructure/hosts/",
+	"PROCESS_GROUP":          "/api/v1/entity/infrastructure/process-groups/",
+	"PROCESS_GROUP_INSTANCE": "/api/v1/entity/infrastructure/processes/",
+	"SERVICE":                "/api/v1/entity/services/",
+}
+
+func newEnricher(config *Config, restConfig *rest.Config, step *StepConfig) (Enricher, error) {
+	apiConfigured := config.APIBaseURL != "" && config.Credentials != nil && config.Credentials.Configured()
+	switch EnricherType(step.Enrich) {
+	case EnricherTypes.ProblemDetails:
+		if !apiConfigured {
+			return nil, fmt.Errorf("enricher '%s' requires an API Base URL and an API Token", step.Enrich)
+		}
+		return &problemDetailsEnricher{api: problems.NewAPI(restConfig, config.APIBaseURL, config.Credentials)}, nil
+	case EnricherTypes.Topology:
+		if !apiConfigured {
+			return nil, fmt.Errorf("enricher '%s' requires an API Base URL and an API Token", step.Enrich)
+		}
+		return &topologyEnricher{client: rest.NewClient(restConfig, config.APIBaseURL, config.Credentials)}, nil
+	case EnricherTypes.OwnerTags:
+		keys := step.TagKeys
+		if len(keys) == 0 {
+			keys = []string{"owner"}
+		}
+		return &ownerTagsEnricher{keys: keys}, nil
+	}
+	return nil, fmt.Errorf("unknown enricher '%s'", step.Enrich)
+}
+
+// problemDetailsEnricher fetches the problem details, unless the event already carries them
+type problemDetailsEnricher struct {
+	api *problems.API
+}
+
+func (enricher *problemDetailsEnricher) Enrich(event *ProblemEvent) error {
+	if event.Problem != nil && event.Problem.ID != "" {
+		return nil
+	}
+	if event.Notification == nil || event.Notification.PID == "" {
+		return errors.New("problem details cannot be fetched without PID")
+	}
+	var problem *problems.Problem
+	var err error
+	for attempt := 0; attempt < 5; attempt++ {
+		if problem, err = enricher.api.Get(event.Notification.PID); err == nil {
+			event.Problem = problem
+			return nil
+		}
+		time.Sleep(1000 * time.Millisecond)
+	}
+	return err
+}
+
+// topologyEnricher fetches the impacted entities, including their tags and management zones
+type topologyEnricher struct {
+	client *rest.Client
+}
+
+func (enricher *topologyEnricher) Enrich(event *ProblemEvent) error {
+	if event.Problem == nil {
+		return nil
+	}
+	ids := []string{}
+	for _, impact := range event.Problem.RankedImpacts {
+		ids = append(ids, impact.EntityID)
+	}
+	for _, rankedEvent := range event.Problem.RankedEvents {
+		ids = append(ids, rankedEvent.EntityID)
+	}
+	known := map[string]bool{}
+	for _, entity := range event.Entities {
+		known[entity.ID] = true
+	}
+	for _, id := range ids {
+		if id == "" || known[id] {
+			continue
+		}
+		known[id] = true
+		idx := strings.LastIndex(id, "-")
+		if idx < 0 {
+			continue
+		}
+		path, found := entityPaths[id[:idx]]
+		if !found {
+			continue
+		}
+		data, err := enricher.client.GET(path+id, 200)
+		if err != nil {
+			return err
+		}
+		var entity Entity
+		if err = json.Unmarshal(data, &entity); err != nil {
+			return err
+		}
+		event.Entities = append(event.Entities, &entity)
+	}
+	return nil
+}
+
+// ownerTagsEnricher collects the values of the tags with the configured keys as owners
+type ownerTagsEnricher struct {
+	keys []string
+}
+
+func (enricher *ownerTagsEnricher) Enrich(event *ProblemEvent) error {
+	owners := map[string]bool{}
+	for _, owner := range event.Owners {
+		owners[owner] = true
+	}
+	for _, tag := range TagsOf(event) {
+		if tag.Value == nil || *tag.Value == "" {
+			continue
+		}
+		for _, key := range enricher.keys {
+			if tag.Key == key {
+				owners[*tag.Value] = true
+			}
+		}
+	}
+	event.Owners = []string{}
+	for owner := range owners {
+		event.Owners = append(event.Owners, owner)
+	}
+	sort.Strings(event.Owners)
+	return nil
+}
@@ -0,0 +1,36 @@
+package notification
+
+// Entity is an entity impacted by a problem, as reported by the Topology API
+type Entity struct {
+	ID                string              `json:"entityId"`
+	Name              string              `json:"displayName"`
+	Tags              []EntityTag         `json:"tags,omitempty"`
+	ManagementZones   []ManagementZone    `json:"managementZones,omitempty"`
+	FromRelationships map[string][]string `json:"fromRelationships,omitempty"`
+	ToRelationships   map[string][]string `json:"toRelationships,omitempty"`
+}
+
+// EntityTag is a tag of an entity
+type EntityTag struct {
+	Context string  `json:"context"`
+	Key     string  `json:"key"`
+	Value   *string `json:"value,omitempty"`
+}
+
+// String formats the tag the way the Dynatrace UI shows it, e.g. `[AWS]key:value`
+func (tag EntityTag) String() string {
+	s := tag.Key
+	if tag.Context != "" && tag.Context != "CONTEXTLESS" {
+		s = "[" + tag.Context + "]" + s
+	}
+	if tag.Value != nil {
+		s = s + ":" + *tag.Value
+	}
+	return s
+}
+
+// ManagementZone is a short representation of a management zone
+type ManagementZone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
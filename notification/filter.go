@@ -0,0 +1,179 @@
+package notification
+
+import (
+	"strings"
+)
+
+// Filter decides whether a problem event gets processed any further
+type Filter interface {
+	Accept(event *ProblemEvent) bool
+}
+
+// FilterFunc allows to use an ordinary function as Filter
+type FilterFunc func(event *ProblemEvent) bool
+
+// Accept calls filter(event)
+func (filter FilterFunc) Accept(event *ProblemEvent) bool {
+	return filter(event)
+}
+
+// FilterConfig configures a filter within the pipeline section of the config file.
+// An event needs to satisfy every criterion specified, lists are satisfied by any of their elements.
+// Severities, states and impacts are compared case insensitive.
+type FilterConfig struct {
+	Severities []string `json:"severities,omitempty"` // e.g. `AVAILABILITY`, `ERROR`, `PERFORMANCE`
+	States     []string `json:"states,omitempty"`     // e.g. `OPEN`, `RESOLVED`, `MERGED`
+	Impacts    []string `json:"impacts,omitempty"`    // e.g. `APPLICATION`, `SERVICE`, `INFRASTRUCTURE`
+	Tags       []string `json:"tags,omitempty"`       // tags written as `key`, `key:value` or `[CONTEXT]key:value`
+	// ManagementZones are names or IDs of management zones.
+	// They are known only for events enriched with the topology of the impacted entities.
+	ManagementZones []string `json:"managementZones,omitempty"`
+	// Exclude inverts the filter, matching events get dropped
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// NewFilter creates a Filter from its configuration
+func NewFilter(config *FilterConfig) Filter {
+	return &criteria{config: config}
+}
+
+type criteria struct {
+	config *FilterConfig
+}
+
+func (criteria *criteria) Accept(event *ProblemEvent) bool {
+	return criteria.matches(event) != criteria.config.Exclude
+}
+
+func (criteria *criteria) matches(event *ProblemEvent) bool {
+	config := criteria.config
+	if len(config.Severities) > 0 && !containsFold(config.Severities, severityOf(event)) {
+		return false
+	}
+	if len(config.States) > 0 && !containsFold(config.States, stateOf(event)) {
+		return false
+	}
+	if len(config.Impacts) > 0 && !containsFold(config.Impacts, impactOf(event)) {
+		return false
+	}
+	if len(config.Tags) > 0 && !hasTag(TagsOf(event), config.Tags) {
+		return false
+	}
+	if len(config.ManagementZones) > 0 && !inManagementZone(event, config.ManagementZones) {
+		return false
+	}
+	return true
+}
+
+func severityOf(event *ProblemEvent) string {
+	if event.Notification != nil && event.Notification.Severity != "" {
+		return event.Notification.Severity
+	}
+	if event.Problem != nil {
+		return event.Problem.SeverityLevel
+	}
+	return ""
+}
+
+func stateOf(event *ProblemEvent) string {
+	if event.Notification != nil && event.Notification.State != "" {
+		return event.Notification.State
+	}
+	if event.Problem != nil {
+		return event.Problem.Status.String()
+	}
+	return ""
+}
+
+func impactOf(event *ProblemEvent) string {
+	if event.Notification != nil && event.Notification.Impact != "" {
+		return event.Notification.Impact
+	}
+	if event.Problem != nil {
+		return event.Problem.ImpactLevel.String()
+	}
+	return ""
+}
+
+// TagsOf collects the tags of the notification, the problem details and the impacted entities
+func TagsOf(event *ProblemEvent) []EntityTag {
+	tags := []EntityTag{}
+	if event.Notification != nil {
+		for _, s := range strings.Split(event.Notification.Tags, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				tags = append(tags, parseTag(s))
+			}
+		}
+	}
+	if event.Problem != nil {
+		for _, info := range event.Problem.TagsOfAffectedEntities {
+			tag := EntityTag{Context: info.Context.String(), Key: info.Key}
+			if info.Value != "" {
+				value := info.Value
+				tag.Value = &value
+			}
+			tags = append(tags, tag)
+		}
+	}
+	for _, entity := range event.Entities {
+		tags = append(tags, entity.Tags...)
+	}
+	return tags
+}
+
+func hasTag(tags []EntityTag, patterns []string) bool {
+	for _, pattern := range patterns {
+		expected := parseTag(pattern)
+		for _, tag := range tags {
+			if expected.Context != "" && !strings.EqualFold(expected.Context, tag.Context) {
+				continue
+			}
+			if expected.Key != tag.Key {
+				continue
+			}
+			if expected.Value != nil && (tag.Value == nil || *expected.Value != *tag.Value) {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// parseTag splits `[CONTEXT]key:value` into its parts, context and value are optional
+func parseTag(s string) EntityTag {
+	var tag EntityTag
+	if strings.HasPrefix(s, "[") {
+		if idx := strings.Index(s, "]"); idx > 0 {
+			tag.Context = s[1:idx]
+			s = s[idx+1:]
+		}
+	}
+	if idx := strings.Index(s, ":"); idx >= 0 {
+		value := s[idx+1:]
+		tag.Value = &value
+		s = s[:idx]
+	}
+	tag.Key = s
+	return tag
+}
+
+func inManagementZone(event *ProblemEvent, mzs []string) bool {
+	for _, entity := range event.Entities {
+		for _, mz := range entity.ManagementZones {
+			if containsFold(mzs, mz.Name) || containsFold(mzs, mz.ID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
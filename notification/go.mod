@@ -5,7 +5,6 @@ go 1.15
 require (
 	github.com/dtcookie/dynatrace/apis/cluster v1.0.12
 	github.com/dtcookie/dynatrace/apis/problems v1.0.0
-	github.com/dtcookie/dynatrace/http v1.0.8
 	github.com/dtcookie/dynatrace/log v1.0.12
 	github.com/dtcookie/dynatrace/rest v1.0.11
 )
@@ -18,3 +18,11 @@ type hub struct {
 func (hub *hub) Handle(event *ProblemEvent) error {
 	return hub.next.Handle(event)
 }
+
+// HandlerFunc allows to use an ordinary function as Handler
+type HandlerFunc func(event *ProblemEvent) error
+
+// Handle calls handler(event)
+func (handler HandlerFunc) Handle(event *ProblemEvent) error {
+	return handler(event)
+}
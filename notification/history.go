@@ -0,0 +1,246 @@
+package notification
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HistoryConfig configures the history of received problem notifications within the config file
+type HistoryConfig struct {
+	Directory string `json:"directory"`
+	Retention string `json:"retention,omitempty"` // how long notifications are kept, `720h` (30 days) if not specified
+}
+
+// Record is a problem notification stored in the history
+type Record struct {
+	Received time.Time     `json:"received"`
+	Event    *ProblemEvent `json:"event"`
+}
+
+// Query selects records of the history.
+// A record needs to satisfy every criterion specified, lists are satisfied by any of their elements.
+type Query struct {
+	From   time.Time // inclusive, unbounded if zero
+	To     time.Time // exclusive, unbounded if zero
+	States []string
+	Tags   []string // tags written as `key`, `key:value` or `[CONTEXT]key:value`
+	Entity string   // the ID of an impacted entity, or a part of its name
+	PID    string
+	Limit  int // returns the most recent records only, all if 0
+}
+
+// History stores the received problem notifications in one file per day
+type History struct {
+	directory string
+	retention time.Duration
+
+	mu     sync.Mutex
+	purged string
+}
+
+const historyDay = "2006-01-02"
+
+// NewHistory opens the history in the configured directory
+func NewHistory(config *HistoryConfig) (*History, error) {
+	if config.Directory == "" {
+		return nil, fmt.Errorf("no history directory specified")
+	}
+	history := &History{directory: config.Directory, retention: 30 * 24 * time.Hour}
+	if config.Retention != "" {
+		var err error
+		if history.retention, err = time.ParseDuration(config.Retention); err != nil {
+			return nil, fmt.Errorf("invalid history retention: %s", err.Error())
+		}
+	}
+	if err := os.MkdirAll(config.Directory, 0755); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Add stores a received problem notification
+func (history *History) Add(event *ProblemEvent, received time.Time) error {
+	received = received.UTC()
+	data, err := json.Marshal(&Record{Received: received, Event: event})
+	if err != nil {
+		return err
+	}
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	day := received.Format(historyDay)
+	if history.purged != day {
+		history.purge(received)
+		history.purged = day
+	}
+	file, err := os.OpenFile(filepath.Join(history.directory, "history-"+day+".jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// purge removes the files of the days beyond the retention
+func (history *History) purge(now time.Time) {
+	oldest := now.Add(-history.retention).Format(historyDay)
+	files, _ := filepath.Glob(filepath.Join(history.directory, "history-*.jsonl"))
+	for _, file := range files {
+		if day := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "history-"), ".jsonl"); day < oldest {
+			os.Remove(file)
+		}
+	}
+}
+
+// Query returns the records matching the query, oldest first.
+// The files are read newest first, so with a limit only the days needed to reach it are read.
+func (history *History) Query(query *Query) ([]*Record, error) {
+	files, err := filepath.Glob(filepath.Join(history.directory, "history-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+	records := []*Record{}
+	for _, file := range files {
+		day := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "history-"), ".jsonl")
+		if !query.From.IsZero() && day < query.From.UTC().Format(historyDay) {
+			break
+		}
+		if !query.To.IsZero() && day > query.To.UTC().Format(historyDay) {
+			continue
+		}
+		matching, err := readRecords(file, query, []*Record{})
+		if err != nil {
+			return nil, err
+		}
+		if query.Limit > 0 && len(records)+len(matching) > query.Limit {
+			matching = matching[len(records)+len(matching)-query.Limit:]
+		}
+		records = append(matching, records...)
+		if query.Limit > 0 && len(records) == query.Limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+func readRecords(file string, query *Query, records []*Record) ([]*Record, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Event == nil {
+			continue
+		}
+		if query.matches(&record) {
+			records = append(records, &record)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func (query *Query) matches(record *Record) bool {
+	event := record.Event
+	if !query.From.IsZero() && record.Received.Before(query.From) {
+		return false
+	}
+	if !query.To.IsZero() && !record.Received.Before(query.To) {
+		return false
+	}
+	if len(query.States) > 0 && !containsFold(query.States, stateOf(event)) {
+		return false
+	}
+	if len(query.Tags) > 0 && !hasTag(TagsOf(event), query.Tags) {
+		return false
+	}
+	if query.PID != "" && (event.Notification == nil || (event.Notification.PID != query.PID && event.Notification.ProblemID != query.PID)) {
+		return false
+	}
+	if query.Entity != "" && !impacts(event, query.Entity) {
+		return false
+	}
+	return true
+}
+
+// impacts reports whether the event refers to an entity with the given ID, or with a name containing the given text
+func impacts(event *ProblemEvent, entity string) bool {
+	for _, name := range EntityNamesOf(event) {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(entity)) {
+			return true
+		}
+	}
+	for _, impacted := range event.Entities {
+		if impacted.ID == entity {
+			return true
+		}
+	}
+	if event.Problem != nil {
+		for _, impact := range event.Problem.RankedImpacts {
+			if impact.EntityID == entity {
+				return true
+			}
+		}
+		for _, rankedEvent := range event.Problem.RankedEvents {
+			if rankedEvent.EntityID == entity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseQuery creates a query from the parameters `from`, `to`, `state`, `tag`, `entity`, `pid` and `limit`.
+// Times are either RFC 3339 or a negative duration relative to now, e.g. `-168h` for a week ago.
+// `state` and `tag` may be repeated.
+func ParseQuery(values url.Values, now time.Time) (*Query, error) {
+	query := &Query{States: values["state"], Tags: values["tag"], Entity: values.Get("entity"), PID: values.Get("pid")}
+	var err error
+	if query.From, err = parseTime(values.Get("from"), now); err != nil {
+		return nil, err
+	}
+	if query.To, err = parseTime(values.Get("to"), now); err != nil {
+		return nil, err
+	}
+	if s := values.Get("limit"); s != "" {
+		if query.Limit, err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("invalid limit '%s'", s)
+		}
+	}
+	return query, nil
+}
+
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if strings.HasPrefix(s, "-") {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time '%s'", s)
+		}
+		return now.Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s'", s)
+	}
+	return t, nil
+}
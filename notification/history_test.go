@@ -0,0 +1,80 @@
+package notification_test
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/apis/problems"
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	history, err := notification.NewHistory(&notification.HistoryConfig{Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	add := func(daysAgo int, pid string, state string, tags string, entity string) {
+		event := &notification.ProblemEvent{
+			Notification: &notification.Default{PID: pid, State: state, Tags: tags},
+			Problem:      &problems.Problem{RankedImpacts: []problems.EventRestImpact{{EntityID: "SERVICE-" + pid, EntityName: entity}}},
+		}
+		if err := history.Add(event, now.Add(time.Duration(-daysAgo)*24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(10, "1", "OPEN", "team:payments", "checkout-service")
+	add(3, "2", "OPEN", "team:payments", "checkout-service")
+	add(2, "2", "RESOLVED", "team:payments", "checkout-service")
+	add(1, "3", "OPEN", "team:search", "search-service")
+
+	queryPIDs := func(params url.Values) []string {
+		query, err := notification.ParseQuery(params, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := history.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pids := []string{}
+		for _, record := range records {
+			pids = append(pids, record.Event.Notification.PID+"/"+record.Event.Notification.State)
+		}
+		return pids
+	}
+	for _, test := range []struct {
+		params   url.Values
+		expected []string
+	}{
+		{url.Values{"from": {"-168h"}, "entity": {"Checkout"}}, []string{"2/OPEN", "2/RESOLVED"}},
+		{url.Values{"state": {"OPEN"}, "tag": {"team:payments"}}, []string{"1/OPEN", "2/OPEN"}},
+		{url.Values{"entity": {"SERVICE-3"}}, []string{"3/OPEN"}},
+		{url.Values{"pid": {"2"}, "limit": {"1"}}, []string{"2/RESOLVED"}},
+		{url.Values{"limit": {"2"}}, []string{"2/RESOLVED", "3/OPEN"}},
+		{url.Values{"state": {"OPEN"}, "limit": {"5"}}, []string{"1/OPEN", "2/OPEN", "3/OPEN"}},
+		{url.Values{"from": {"-36h"}, "limit": {"5"}}, []string{"3/OPEN"}},
+		{url.Values{"to": {"-240h"}}, []string{}},
+	} {
+		actual := queryPIDs(test.params)
+		if len(actual) != len(test.expected) {
+			t.Errorf("%v: expected %v, got %v", test.params, test.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("%v: expected %v, got %v", test.params, test.expected, actual)
+				break
+			}
+		}
+	}
+}
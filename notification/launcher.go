@@ -1,6 +1,23 @@
 package notification
 
-// Listen TODO: documentation
+import "github.com/dtcookie/dynatrace/log"
+
+// Listen receives problem notifications and passes them to the handler.
+// If the config contains a pipeline section, the handler is known to it by the name `default`.
 func Listen(config *Config, handler Handler) {
-	newListener(config, handler).listen()
+	ListenAll(config, map[string]Handler{"default": handler})
+}
+
+// ListenAll passes the received problem notifications through the pipeline configured in the config.
+// The routes of the pipeline refer to the handlers by their names.
+// It returns once the process has been asked to terminate and the notifications in flight are delivered.
+func ListenAll(config *Config, handlers map[string]Handler) {
+	server, err := NewServer(config, handlers)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if err = server.Run(); err != nil {
+		log.Error(err)
+	}
 }
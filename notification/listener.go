@@ -2,19 +2,22 @@ package notification
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/dtcookie/dynatrace/apis/cluster"
 	"github.com/dtcookie/dynatrace/apis/problems"
 	"github.com/dtcookie/dynatrace/log"
 	"github.com/dtcookie/dynatrace/rest"
 )
 
-func newListener(config *Config, handler Handler) *listener {
+// maxBodySize limits the size of the notifications accepted, as their body gets read before the signature is verified
+const maxBodySize = 1 << 20
+
+func newListener(config *Config, pipeline *Pipeline) *listener {
 	var restConfig rest.Config
 	if config.Insecure {
 		restConfig.Insecure = true
@@ -22,33 +25,22 @@ func newListener(config *Config, handler Handler) *listener {
 	if config.NoProxy {
 		restConfig.NoProxy = true
 	}
-	return &listener{config: config, restConfig: &restConfig, handler: handler}
+	return &listener{config: config, restConfig: &restConfig, pipeline: pipeline, metrics: newMetrics()}
 }
 
 // listener TODO: documentation
 type listener struct {
-	handler    Handler
+	pipeline   *Pipeline
+	queue      *Queue
+	auth       *authenticator
+	states     *StateStore
+	history    *History
+	metrics    *metrics
+	inFlight   sync.WaitGroup
 	restConfig *rest.Config
 	config     *Config
 }
 
-func (listener *listener) listen() {
-	var clusterVersion string
-	var err error
-
-	if len(listener.config.APIBaseURL) > 0 && listener.config.Credentials != nil && listener.config.Credentials.Configured() {
-		clusterAPI := cluster.NewAPI(listener.restConfig, listener.config.APIBaseURL, listener.config.Credentials)
-		if clusterVersion, err = clusterAPI.Get(); err != nil {
-			log.Error(err)
-			return
-		}
-		log.Info("Dynatrace Cluster Version: " + clusterVersion)
-	}
-	http.HandleFunc("/", listener.handleHTTP)
-	log.Info(fmt.Sprintf("Listening on port %d for incoming problem notifications.", listener.config.ListenPort))
-	http.ListenAndServe(fmt.Sprintf(":%d", listener.config.ListenPort), nil)
-}
-
 func (listener *listener) handleHTTP(w http.ResponseWriter, request *http.Request) {
 	var err error
 	var body []byte
@@ -60,6 +52,10 @@ func (listener *listener) handleHTTP(w http.ResponseWriter, request *http.Reques
 		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 		return
 	}
+	if reason, ok := listener.auth.authorize(request); !ok {
+		listener.auth.reject(w, request, reason)
+		return
+	}
 	if request.ContentLength == 0 {
 		if listener.config.Verbose {
 			log.Warn("responding with " + http.StatusText(http.StatusBadRequest))
@@ -76,11 +72,23 @@ func (listener *listener) handleHTTP(w http.ResponseWriter, request *http.Reques
 		http.Error(w, http.StatusText(http.StatusBadRequest)+": expected content-type 'application/json'", http.StatusBadRequest)
 		return
 	}
-	if body, err = ioutil.ReadAll(request.Body); err != nil {
+	if request.ContentLength > maxBodySize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if body, err = ioutil.ReadAll(http.MaxBytesReader(w, request.Body, maxBodySize)); err != nil {
+		if len(body) >= maxBodySize {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, http.StatusText(http.StatusInternalServerError)+": "+err.Error(), http.StatusInternalServerError)
 		log.Error(err)
 		return
 	}
+	if reason, ok := listener.auth.verify(request, body); !ok {
+		listener.auth.reject(w, request, reason)
+		return
+	}
 
 	var defNotification Default
 	if err = json.Unmarshal(body, &defNotification); err != nil {
@@ -93,6 +101,7 @@ func (listener *listener) handleHTTP(w http.ResponseWriter, request *http.Reques
 		return
 	}
 
+	listener.metrics.receive()
 	if listener.config.Verbose {
 		log.Info("received problem notification " + request.RequestURI)
 		log.Info(toJSON(defNotification))
@@ -110,46 +119,97 @@ func (listener *listener) handleHTTP(w http.ResponseWriter, request *http.Reques
 		return
 	}
 
-	if len(listener.config.APIBaseURL) > 0 && listener.config.Credentials != nil && listener.config.Credentials.Configured() {
+	problemEvent := ProblemEvent{URI: request.RequestURI, Notification: &defNotification}
+	if listener.states != nil {
+		if _, err = listener.states.Track(&problemEvent, time.Now().UTC()); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError)+": "+err.Error(), http.StatusInternalServerError)
+			log.Error(err)
+			return
+		}
+	}
+	if listener.history != nil {
+		recorded := problemEvent
+		if recorded.Problem == nil {
+			recorded.Problem = defNotification.ProblemDetailsJSON
+		}
+		if err = listener.history.Add(&recorded, time.Now()); err != nil {
+			log.Error(err)
+		}
+	}
+	if lifecycle := problemEvent.Lifecycle; lifecycle != nil && lifecycle.Duplicate && listener.config.State.Deduplicate {
 		if listener.config.Verbose {
-			log.Info("querying for problem details")
+			log.Info("dropping repeated " + string(lifecycle.State) + " notification for PID " + defNotification.PID)
 		}
-		problemAPI := problems.NewAPI(listener.restConfig, listener.config.APIBaseURL, listener.config.Credentials)
-		go func(problemAPI *problems.API) {
-			var problem *problems.Problem
-			numAttempts := 0
-
-			for numAttempts < 25 {
-				if problem, err = problemAPI.Get(defNotification.PID); err != nil {
-					numAttempts++
-					if numAttempts == 25 {
-						log.Warn("querying for problem details failed: " + err.Error())
-						return
-					}
-				} else {
-					numAttempts = 25
+		listener.metrics.drop()
+		http.Error(w, http.StatusText(http.StatusNoContent), http.StatusNoContent)
+		return
+	}
+	if listener.queue != nil {
+		if err = listener.queue.Handle(&problemEvent); err != nil {
+			if listener.states != nil {
+				if untrackErr := listener.states.Untrack(&problemEvent); untrackErr != nil {
+					log.Error(untrackErr)
 				}
-				time.Sleep(1000 * time.Millisecond)
 			}
-
-			problemEvent := ProblemEvent{URI: request.RequestURI, Notification: &defNotification, Problem: problem}
-			listener.handler.Handle(&problemEvent)
-		}(problemAPI)
+			http.Error(w, http.StatusText(http.StatusInternalServerError)+": "+err.Error(), http.StatusInternalServerError)
+			log.Error(err)
+			return
+		}
 	} else {
+		listener.inFlight.Add(1)
 		go func() {
-			var prob *problems.Problem
-			if prob = defNotification.ProblemDetailsJSON; prob == nil {
-				prob = &problems.Problem{}
+			defer listener.inFlight.Done()
+			if err := listener.process(&problemEvent); err != nil {
+				log.Error(err)
 			}
-
-			problemEvent := ProblemEvent{URI: request.RequestURI, Notification: &defNotification, Problem: prob}
-			listener.handler.Handle(&problemEvent)
 		}()
 	}
 
 	http.Error(w, http.StatusText(http.StatusNoContent), http.StatusNoContent)
 }
 
+// process completes the event with the problem details and passes it to the pipeline
+func (listener *listener) process(problemEvent *ProblemEvent) error {
+	start := time.Now()
+	delivered, err := listener.deliver(problemEvent)
+	listener.metrics.record(delivered, err, time.Since(start))
+	return err
+}
+
+func (listener *listener) deliver(problemEvent *ProblemEvent) (bool, error) {
+	var err error
+	var problem *problems.Problem
+
+	if problemEvent.Problem != nil && problemEvent.Problem.ID != "" {
+		// a retried delivery which already got the details with a previous attempt
+		return listener.pipeline.handle(problemEvent)
+	}
+	if len(listener.config.APIBaseURL) > 0 && listener.config.Credentials != nil && listener.config.Credentials.Configured() {
+		if listener.config.Verbose {
+			log.Info("querying for problem details")
+		}
+		problemAPI := problems.NewAPI(listener.restConfig, listener.config.APIBaseURL, listener.config.Credentials)
+		numAttempts := 0
+
+		for numAttempts < 25 {
+			if problem, err = problemAPI.Get(problemEvent.Notification.PID); err != nil {
+				numAttempts++
+				if numAttempts == 25 {
+					return false, errors.New("querying for problem details failed: " + err.Error())
+				}
+			} else {
+				numAttempts = 25
+			}
+			time.Sleep(1000 * time.Millisecond)
+		}
+	} else if problem = problemEvent.Notification.ProblemDetailsJSON; problem == nil {
+		problem = &problems.Problem{}
+	}
+
+	problemEvent.Problem = problem
+	return listener.pipeline.handle(problemEvent)
+}
+
 func toJSON(v interface{}) string {
 	var err error
 	var bytes []byte
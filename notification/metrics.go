@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds in seconds of the delivery latency histogram
+var latencyBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// metrics counts what happened to the problem notifications received
+type metrics struct {
+	mu         sync.Mutex
+	received   uint64
+	rejected   map[RejectReason]uint64
+	filtered   uint64
+	delivered  uint64
+	failed     uint64
+	buckets    []uint64
+	latencySum float64
+	latencyCnt uint64
+}
+
+func newMetrics() *metrics {
+	return &metrics{rejected: map[RejectReason]uint64{}, buckets: make([]uint64, len(latencyBuckets))}
+}
+
+func (metrics *metrics) receive() {
+	metrics.mu.Lock()
+	metrics.received++
+	metrics.mu.Unlock()
+}
+
+func (metrics *metrics) reject(reason RejectReason) {
+	metrics.mu.Lock()
+	metrics.rejected[reason]++
+	metrics.mu.Unlock()
+}
+
+// drop counts a notification dropped before its delivery, e.g. a duplicate
+func (metrics *metrics) drop() {
+	metrics.mu.Lock()
+	metrics.filtered++
+	metrics.mu.Unlock()
+}
+
+// record counts the outcome of a delivery attempt and how long it took
+func (metrics *metrics) record(delivered bool, err error, latency time.Duration) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	switch {
+	case err != nil:
+		metrics.failed++
+	case delivered:
+		metrics.delivered++
+	default:
+		metrics.filtered++
+	}
+	seconds := latency.Seconds()
+	for idx, bound := range latencyBuckets {
+		if seconds <= bound {
+			metrics.buckets[idx]++
+		}
+	}
+	metrics.latencySum += seconds
+	metrics.latencyCnt++
+}
+
+// write renders the metrics in the Prometheus text exposition format
+func (metrics *metrics) write(w io.Writer) error {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	lines := []string{}
+	counter := func(name string, help string, value uint64) {
+		lines = append(lines, "# HELP "+name+" "+help, "# TYPE "+name+" counter", fmt.Sprintf("%s %d", name, value))
+	}
+	counter("dynatrace_notifications_received_total", "Problem notifications received.", metrics.received)
+
+	lines = append(lines, "# HELP dynatrace_notifications_rejected_total Requests rejected by the authentication.", "# TYPE dynatrace_notifications_rejected_total counter")
+	reasons := []string{}
+	for reason := range metrics.rejected {
+		reasons = append(reasons, string(reason))
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		lines = append(lines, fmt.Sprintf("dynatrace_notifications_rejected_total{reason=%q} %d", reason, metrics.rejected[RejectReason(reason)]))
+	}
+
+	counter("dynatrace_notifications_filtered_total", "Problem notifications dropped as duplicate or by a filter of the pipeline.", metrics.filtered)
+	counter("dynatrace_notifications_delivered_total", "Problem notifications delivered to the handlers.", metrics.delivered)
+	counter("dynatrace_notifications_failed_total", "Failed delivery attempts.", metrics.failed)
+
+	name := "dynatrace_notifications_delivery_latency_seconds"
+	lines = append(lines, "# HELP "+name+" Duration of the delivery attempts, including fetching the problem details.", "# TYPE "+name+" histogram")
+	for idx, bound := range latencyBuckets {
+		lines = append(lines, fmt.Sprintf("%s_bucket{le=\"%g\"} %d", name, bound, metrics.buckets[idx]))
+	}
+	lines = append(lines,
+		fmt.Sprintf("%s_bucket{le=\"+Inf\"} %d", name, metrics.latencyCnt),
+		fmt.Sprintf("%s_sum %g", name, metrics.latencySum),
+		fmt.Sprintf("%s_count %d", name, metrics.latencyCnt),
+	)
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
@@ -0,0 +1,225 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dtcookie/dynatrace/log"
+	"github.com/dtcookie/dynatrace/rest"
+)
+
+// PipelineConfig configures how the listener processes problem events.
+// Every event passes the steps in the given order before the routes decide which handlers receive it.
+type PipelineConfig struct {
+	Steps  []*StepConfig  `json:"steps,omitempty"`
+	Routes []*RouteConfig `json:"routes,omitempty"`
+	// Fallback are the names of the handlers receiving the events no route matched.
+	// If not specified, these events are passed to the handler named `default`, if there is one.
+	Fallback []string `json:"fallback,omitempty"`
+}
+
+// StepConfig is either a filter or an enricher
+type StepConfig struct {
+	Filter  *FilterConfig `json:"filter,omitempty"`
+	Enrich  string        `json:"enrich,omitempty"`  // one of `problemDetails`, `topology` or `ownerTags`
+	TagKeys []string      `json:"tagKeys,omitempty"` // the tag keys considered by `ownerTags`, `owner` if not specified
+}
+
+// RouteConfig dispatches the events matching the filter to the handlers with the given names.
+// Routes are evaluated in the given order, the first matching route wins unless it is configured to continue.
+type RouteConfig struct {
+	Name     string        `json:"name,omitempty"`
+	Filter   *FilterConfig `json:"filter,omitempty"` // a route without filter matches every event
+	Handlers []string      `json:"handlers"`
+	Continue bool          `json:"continue,omitempty"`
+}
+
+// Route dispatches the events accepted by its filter to its handlers
+type Route struct {
+	Name     string
+	Filter   Filter
+	Handlers []Handler
+	Continue bool
+
+	names []string // the names of the handlers, identifying them across the attempts of a queued delivery
+}
+
+// Router is a Handler dispatching problem events to different handlers
+type Router struct {
+	Routes   []*Route
+	Fallback []Handler
+
+	fallbackNames []string
+}
+
+// Handle passes the event to the handlers of the matching routes.
+// Handlers which already received the event with a previous attempt of a queued delivery are skipped.
+func (router *Router) Handle(event *ProblemEvent) error {
+	matched := false
+	errs := []string{}
+	for _, route := range router.Routes {
+		if route.Filter != nil && !route.Filter.Accept(event) {
+			continue
+		}
+		matched = true
+		for idx, handler := range route.Handlers {
+			if err := event.deliver("route/"+route.Name+"/"+handlerName(route.names, idx), handler); err != nil {
+				errs = append(errs, fmt.Sprintf("route '%s': %s", route.Name, err.Error()))
+			}
+		}
+		if !route.Continue {
+			break
+		}
+	}
+	if !matched {
+		for idx, handler := range router.Fallback {
+			if err := event.deliver("fallback/"+handlerName(router.fallbackNames, idx), handler); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// handlerName returns the name of the handler at the given position, handlers of routes created in code are numbered
+func handlerName(names []string, idx int) string {
+	if idx < len(names) {
+		return names[idx]
+	}
+	return fmt.Sprintf("#%d", idx+1)
+}
+
+// Pipeline is a Handler filtering and enriching problem events before passing them to a Router
+type Pipeline struct {
+	steps   []*step
+	router  *Router
+	verbose bool
+}
+
+type step struct {
+	filter   Filter
+	enricher Enricher
+	name     string
+}
+
+// NewPipeline creates the pipeline configured in the pipeline section of the config.
+// The routes refer to the given handlers and the sinks of the config by their names.
+// Without pipeline section every event gets passed to the handler named `default`.
+func NewPipeline(config *Config, handlers map[string]Handler) (*Pipeline, error) {
+	named := map[string]Handler{}
+	for name, sinkConfig := range config.Sinks {
+		sink, err := NewSink(name, sinkConfig, config)
+		if err != nil {
+			return nil, err
+		}
+		named[name] = sink
+	}
+	for name, handler := range handlers {
+		if _, found := named[name]; found {
+			return nil, fmt.Errorf("handler '%s' conflicts with the sink of the same name", name)
+		}
+		named[name] = handler
+	}
+	handlers = named
+
+	pipelineConfig := config.Pipeline
+	if pipelineConfig == nil {
+		pipelineConfig = &PipelineConfig{}
+	}
+	var restConfig rest.Config
+	restConfig.Insecure = config.Insecure
+	restConfig.NoProxy = config.NoProxy
+
+	pipeline := &Pipeline{router: &Router{}, verbose: config.Verbose}
+	for idx, stepConfig := range pipelineConfig.Steps {
+		switch {
+		case stepConfig.Filter != nil && stepConfig.Enrich != "":
+			return nil, fmt.Errorf("step #%d is both a filter and an enricher", idx+1)
+		case stepConfig.Filter != nil:
+			pipeline.steps = append(pipeline.steps, &step{filter: NewFilter(stepConfig.Filter), name: fmt.Sprintf("filter #%d", idx+1)})
+		case stepConfig.Enrich != "":
+			enricher, err := newEnricher(config, &restConfig, stepConfig)
+			if err != nil {
+				return nil, err
+			}
+			pipeline.steps = append(pipeline.steps, &step{enricher: enricher, name: stepConfig.Enrich})
+		default:
+			return nil, fmt.Errorf("step #%d is neither a filter nor an enricher", idx+1)
+		}
+	}
+	for idx, routeConfig := range pipelineConfig.Routes {
+		route := &Route{Name: routeConfig.Name, Continue: routeConfig.Continue, names: routeConfig.Handlers}
+		if route.Name == "" {
+			route.Name = fmt.Sprintf("#%d", idx+1)
+		}
+		if routeConfig.Filter != nil {
+			route.Filter = NewFilter(routeConfig.Filter)
+		}
+		var err error
+		if route.Handlers, err = resolveHandlers(handlers, routeConfig.Handlers); err != nil {
+			return nil, fmt.Errorf("route '%s': %s", route.Name, err.Error())
+		}
+		pipeline.router.Routes = append(pipeline.router.Routes, route)
+	}
+	fallback := pipelineConfig.Fallback
+	if _, found := handlers["default"]; fallback == nil && found {
+		fallback = []string{"default"}
+	}
+	var err error
+	if pipeline.router.Fallback, err = resolveHandlers(handlers, fallback); err != nil {
+		return nil, fmt.Errorf("fallback: %s", err.Error())
+	}
+	pipeline.router.fallbackNames = fallback
+	return pipeline, nil
+}
+
+func resolveHandlers(handlers map[string]Handler, names []string) ([]Handler, error) {
+	resolved := []Handler{}
+	for _, name := range names {
+		handler, found := handlers[name]
+		if !found {
+			return nil, fmt.Errorf("unknown handler '%s'", name)
+		}
+		resolved = append(resolved, handler)
+	}
+	return resolved, nil
+}
+
+// Handle passes the event through the steps of the pipeline and routes it if no filter dropped it.
+// Failing enrichers don't stop the event, it gets routed with the information available.
+func (pipeline *Pipeline) Handle(event *ProblemEvent) error {
+	_, err := pipeline.handle(event)
+	return err
+}
+
+// handle additionally reports whether the event got routed
+func (pipeline *Pipeline) handle(event *ProblemEvent) (bool, error) {
+	for _, step := range pipeline.steps {
+		if step.filter != nil && !step.filter.Accept(event) {
+			if pipeline.verbose {
+				log.Info(fmt.Sprintf("problem notification %s dropped by %s", pidOf(event), step.name))
+			}
+			return false, nil
+		}
+		if step.enricher != nil {
+			if err := step.enricher.Enrich(event); err != nil {
+				log.Warn(fmt.Sprintf("enricher '%s' failed for problem notification %s: %s", step.name, pidOf(event), err.Error()))
+			}
+		}
+	}
+	return true, pipeline.router.Handle(event)
+}
+
+func pidOf(event *ProblemEvent) string {
+	if event.Notification != nil {
+		return event.Notification.PID
+	}
+	if event.Problem != nil {
+		return event.Problem.ID
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+type recorder struct {
+	events []*notification.ProblemEvent
+}
+
+func (recorder *recorder) Handle(event *notification.ProblemEvent) error {
+	recorder.events = append(recorder.events, event)
+	return nil
+}
+
+func TestPipeline(t *testing.T) {
+	data := []byte(`{
+		"pipeline": {
+			"steps": [
+				{ "filter": { "states": [ "resolved" ], "exclude": true } },
+				{ "enrich": "ownerTags", "tagKeys": [ "team" ] }
+			],
+			"routes": [
+				{ "name": "critical", "filter": { "severities": [ "AVAILABILITY" ] }, "handlers": [ "pager" ], "continue": true },
+				{ "name": "payments", "filter": { "tags": [ "team:payments" ] }, "handlers": [ "chat" ] }
+			]
+		}
+	}`)
+	var config notification.Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	pager, chat, fallback := &recorder{}, &recorder{}, &recorder{}
+	pipeline, err := notification.NewPipeline(&config, map[string]notification.Handler{"pager": pager, "chat": chat, "default": fallback})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []*notification.Default{
+		{PID: "1", State: "OPEN", Severity: "AVAILABILITY", Tags: "team:payments, env:prod"},
+		{PID: "2", State: "OPEN", Severity: "PERFORMANCE", Tags: "team:payments"},
+		{PID: "3", State: "OPEN", Severity: "ERROR", Tags: "team:search"},
+		{PID: "4", State: "RESOLVED", Severity: "AVAILABILITY", Tags: "team:payments"},
+	}
+	for _, n := range events {
+		if err := pipeline.Handle(&notification.ProblemEvent{Notification: n}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect := func(name string, recorder *recorder, pids ...string) {
+		if len(recorder.events) != len(pids) {
+			t.Errorf("%s: expected %d events, got %d", name, len(pids), len(recorder.events))
+			return
+		}
+		for i, pid := range pids {
+			if recorder.events[i].Notification.PID != pid {
+				t.Errorf("%s: expected PID %s, got %s", name, pid, recorder.events[i].Notification.PID)
+			}
+		}
+	}
+	expect("pager", pager, "1")
+	expect("chat", chat, "1", "2")
+	expect("default", fallback, "3")
+	if owners := chat.events[0].Owners; len(owners) != 1 || owners[0] != "payments" {
+		t.Errorf("expected owner 'payments', got %v", owners)
+	}
+
+	config.Pipeline.Routes[0].Handlers = []string{"unknown"}
+	if _, err := notification.NewPipeline(&config, map[string]notification.Handler{}); err == nil {
+		t.Error("expected an error for an unknown handler")
+	}
+}
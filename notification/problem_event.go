@@ -7,4 +7,26 @@ type ProblemEvent struct {
 	URI          string            `json:"-"`
 	Notification *Default          `json:"notification,omitempty"`
 	Problem      *problems.Problem `json:"details,omitempty"`
+	Entities     []*Entity         `json:"entities,omitempty"`  // the impacted entities, resolved by the topology enricher
+	Owners       []string          `json:"owners,omitempty"`    // the owners of the impacted entities, resolved by the owner tags enricher
+	Lifecycle    *Lifecycle        `json:"lifecycle,omitempty"` // the lifecycle of the problem, if state tracking is configured
+
+	// delivered tracks the handlers which received the event, the queue keeps it across the attempts of a delivery
+	delivered map[string]bool
+	// untracked is the lifecycle as it was before the notification got tracked, nil for the first notification of a problem
+	untracked *Lifecycle
+}
+
+// deliver passes the event to the handler with the given key, unless it received the event with a previous attempt already
+func (event *ProblemEvent) deliver(key string, handler Handler) error {
+	if event.delivered[key] {
+		return nil
+	}
+	if err := handler.Handle(event); err != nil {
+		return err
+	}
+	if event.delivered != nil {
+		event.delivered[key] = true
+	}
+	return nil
 }
@@ -0,0 +1,363 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dtcookie/dynatrace/log"
+)
+
+// QueueConfig configures the durable delivery queue within the config file.
+// Received problem notifications are persisted in the directory before they are passed to the handlers,
+// deliveries failing for MaxAttempts times end up as dead letters in the subdirectory `dead`.
+type QueueConfig struct {
+	Directory      string `json:"directory"`
+	Workers        int    `json:"workers,omitempty"`        // the maximum number of concurrent deliveries, 4 if not specified
+	MaxAttempts    int    `json:"maxAttempts,omitempty"`    // 10 if not specified
+	InitialBackoff string `json:"initialBackoff,omitempty"` // the delay after the first failed attempt, e.g. `1s` (the default)
+	MaxBackoff     string `json:"maxBackoff,omitempty"`     // the delay doubles with every failed attempt up to this limit, `5m` if not specified
+}
+
+// Delivery is a problem event persisted by the queue
+type Delivery struct {
+	ID          string        `json:"id"`
+	URI         string        `json:"uri,omitempty"`
+	Event       *ProblemEvent `json:"event"`
+	Received    time.Time     `json:"received"`
+	Attempts    int           `json:"attempts"`
+	NextAttempt time.Time     `json:"nextAttempt,omitempty"`
+	LastError   string        `json:"lastError,omitempty"`
+	// Delivered lists the handlers which already received the event, they're skipped when the delivery is retried
+	Delivered []string `json:"delivered,omitempty"`
+}
+
+// Queue is a Handler persisting problem events on disk and passing them on to another Handler in the background.
+// Failed deliveries are retried with exponential backoff.
+type Queue struct {
+	handler        Handler
+	pending        string
+	dead           string
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	slots    chan struct{}
+	wake     chan struct{}
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+	mu       sync.Mutex
+	inFlight map[string]bool
+	wg       sync.WaitGroup
+}
+
+var sequence uint64
+
+// NewQueue creates a queue delivering the events to the given handler.
+// Deliveries already pending in the directory are picked up once the queue is started.
+func NewQueue(config *QueueConfig, handler Handler) (*Queue, error) {
+	if config.Directory == "" {
+		return nil, fmt.Errorf("no queue directory specified")
+	}
+	queue := &Queue{
+		handler:        handler,
+		pending:        filepath.Join(config.Directory, "pending"),
+		dead:           filepath.Join(config.Directory, "dead"),
+		maxAttempts:    config.MaxAttempts,
+		initialBackoff: time.Second,
+		maxBackoff:     5 * time.Minute,
+		wake:           make(chan struct{}, 1),
+		stop:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+		inFlight:       map[string]bool{},
+	}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	queue.slots = make(chan struct{}, workers)
+	if queue.maxAttempts <= 0 {
+		queue.maxAttempts = 10
+	}
+	var err error
+	if config.InitialBackoff != "" {
+		if queue.initialBackoff, err = time.ParseDuration(config.InitialBackoff); err != nil {
+			return nil, fmt.Errorf("invalid initial backoff: %s", err.Error())
+		}
+	}
+	if config.MaxBackoff != "" {
+		if queue.maxBackoff, err = time.ParseDuration(config.MaxBackoff); err != nil {
+			return nil, fmt.Errorf("invalid max backoff: %s", err.Error())
+		}
+	}
+	for _, dir := range []string{queue.pending, queue.dead} {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return queue, nil
+}
+
+// Handle persists the event. It returns as soon as the event is safely stored, not when it has been delivered.
+func (queue *Queue) Handle(event *ProblemEvent) error {
+	id := fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), atomic.AddUint64(&sequence, 1)%1000000)
+	if pid := pidOf(event); pid != "" {
+		id = id + "-" + sanitize(pid)
+	}
+	delivery := &Delivery{ID: id, URI: event.URI, Event: event, Received: time.Now().UTC()}
+	if err := writeDelivery(queue.pending, delivery); err != nil {
+		return err
+	}
+	queue.signal()
+	return nil
+}
+
+// Start delivers the pending events in the background until Stop is called
+func (queue *Queue) Start() {
+	go queue.run()
+}
+
+// Stop stops picking up pending events and waits for the deliveries in flight to complete
+func (queue *Queue) Stop() {
+	queue.stopOnce.Do(func() {
+		close(queue.stop)
+		<-queue.stopped
+	})
+	queue.wg.Wait()
+}
+
+func (queue *Queue) signal() {
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (queue *Queue) run() {
+	defer close(queue.stopped)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		queue.dispatch()
+		select {
+		case <-queue.stop:
+			return
+		case <-queue.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch starts a worker for every delivery which is due, as long as workers are available
+func (queue *Queue) dispatch() {
+	deliveries, err := readDeliveries(queue.pending)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	now := time.Now()
+	for _, delivery := range deliveries {
+		if delivery.NextAttempt.After(now) {
+			continue
+		}
+		queue.mu.Lock()
+		if queue.inFlight[delivery.ID] {
+			queue.mu.Unlock()
+			continue
+		}
+		select {
+		case queue.slots <- struct{}{}:
+		default:
+			queue.mu.Unlock()
+			return
+		}
+		queue.inFlight[delivery.ID] = true
+		queue.mu.Unlock()
+		queue.wg.Add(1)
+		go queue.deliver(delivery)
+	}
+}
+
+func (queue *Queue) deliver(delivery *Delivery) {
+	defer func() {
+		queue.mu.Lock()
+		delete(queue.inFlight, delivery.ID)
+		queue.mu.Unlock()
+		<-queue.slots
+		queue.wg.Done()
+		queue.signal()
+	}()
+
+	delivery.Event.URI = delivery.URI
+	delivery.Event.delivered = map[string]bool{}
+	for _, key := range delivery.Delivered {
+		delivery.Event.delivered[key] = true
+	}
+	err := queue.handler.Handle(delivery.Event)
+	delivery.Delivered = []string{}
+	for key := range delivery.Event.delivered {
+		delivery.Delivered = append(delivery.Delivered, key)
+	}
+	sort.Strings(delivery.Delivered)
+	if err == nil {
+		if err = os.Remove(filepath.Join(queue.pending, delivery.ID+".json")); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= queue.maxAttempts {
+		log.Warn(fmt.Sprintf("delivery %s failed %d times, moving it to the dead letters: %s", delivery.ID, delivery.Attempts, err.Error()))
+		if err = moveDelivery(queue.pending, queue.dead, delivery); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	backoff := queue.initialBackoff
+	for i := 1; i < delivery.Attempts && backoff < queue.maxBackoff; i++ {
+		backoff = backoff * 2
+	}
+	if backoff > queue.maxBackoff {
+		backoff = queue.maxBackoff
+	}
+	delivery.NextAttempt = time.Now().Add(backoff).UTC()
+	log.Warn(fmt.Sprintf("delivery %s failed (attempt %d of %d), retrying in %v: %s", delivery.ID, delivery.Attempts, queue.maxAttempts, backoff, err.Error()))
+	if err = writeDelivery(queue.pending, delivery); err != nil {
+		log.Error(err)
+	}
+}
+
+// DeadLetters lists the deliveries which failed permanently within the given queue directory
+func DeadLetters(directory string) ([]*Delivery, error) {
+	return readDeliveries(filepath.Join(directory, "dead"))
+}
+
+// Replay moves dead letters back into the pending deliveries of the given queue directory, resetting their attempts.
+// Handlers which received an event already don't receive it again. Without IDs all dead letters are replayed. A running listener picks them up within a second.
+func Replay(directory string, ids ...string) (int, error) {
+	pending, dead := filepath.Join(directory, "pending"), filepath.Join(directory, "dead")
+	deliveries, err := readDeliveries(dead)
+	if err != nil {
+		return 0, err
+	}
+	selected := map[string]bool{}
+	for _, id := range ids {
+		selected[id] = true
+	}
+	replayed := 0
+	for _, delivery := range deliveries {
+		if len(ids) > 0 && !selected[delivery.ID] {
+			continue
+		}
+		delete(selected, delivery.ID)
+		delivery.Attempts = 0
+		delivery.NextAttempt = time.Time{}
+		delivery.LastError = ""
+		if err = moveDelivery(dead, pending, delivery); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	if len(selected) > 0 {
+		unknown := []string{}
+		for id := range selected {
+			unknown = append(unknown, id)
+		}
+		sort.Strings(unknown)
+		return replayed, fmt.Errorf("unknown dead letters: %s", strings.Join(unknown, ", "))
+	}
+	return replayed, nil
+}
+
+// readDeliveries reads the deliveries stored in a directory, oldest first
+func readDeliveries(dir string) ([]*Delivery, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []*Delivery{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var delivery Delivery
+		if err = json.Unmarshal(data, &delivery); err != nil || delivery.Event == nil {
+			log.Warn(fmt.Sprintf("setting aside unreadable delivery %s", filepath.Join(dir, file.Name())))
+			os.Rename(filepath.Join(dir, file.Name()), filepath.Join(dir, file.Name()+".invalid"))
+			continue
+		}
+		delivery.ID = strings.TrimSuffix(file.Name(), ".json")
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, nil
+}
+
+// writeDelivery stores a delivery atomically, readers never see partially written files.
+// The file and the directory are synced, a stored delivery survives a crash.
+func writeDelivery(dir string, delivery *Delivery) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(dir, delivery.ID+".json")
+	f, err := os.OpenFile(file+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file + ".tmp")
+		return err
+	}
+	if err = os.Rename(file+".tmp", file); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir makes the creation, renaming or removal of files within a directory durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func moveDelivery(from string, to string, delivery *Delivery) error {
+	if err := writeDelivery(to, delivery); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(from, delivery.ID+".json"))
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
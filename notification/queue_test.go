@@ -0,0 +1,133 @@
+package notification_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func TestQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	delivered := map[string]bool{}
+	broken := true
+	handler := notification.HandlerFunc(func(event *notification.ProblemEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		pid := event.Notification.PID
+		attempts[pid]++
+		if (pid == "flaky" && attempts[pid] < 3) || (pid == "broken" && broken) {
+			return errors.New("downstream unavailable")
+		}
+		delivered[pid] = true
+		return nil
+	})
+	config := &notification.QueueConfig{Directory: dir, Workers: 2, MaxAttempts: 3, InitialBackoff: "10ms", MaxBackoff: "20ms"}
+	queue, err := notification.NewQueue(config, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue.Start()
+	defer queue.Stop()
+
+	for _, pid := range []string{"ok", "flaky", "broken"} {
+		if err := queue.Handle(&notification.ProblemEvent{Notification: &notification.Default{PID: pid}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, func() bool {
+		deadLetters, _ := notification.DeadLetters(dir)
+		mu.Lock()
+		defer mu.Unlock()
+		return delivered["ok"] && delivered["flaky"] && len(deadLetters) == 1
+	})
+	mu.Lock()
+	if attempts["flaky"] != 3 || attempts["broken"] != 3 {
+		t.Errorf("expected 3 attempts each, got %v", attempts)
+	}
+	broken = false
+	mu.Unlock()
+
+	if replayed, err := notification.Replay(dir); err != nil || replayed != 1 {
+		t.Fatalf("expected 1 replayed dead letter, got %d (%v)", replayed, err)
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return delivered["broken"]
+	})
+	if _, err := notification.Replay(dir, "unknown"); err == nil {
+		t.Error("expected an error for an unknown dead letter")
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestQueueSkipsDeliveredHandlers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	counting := func(name string, failures int) notification.Handler {
+		return notification.HandlerFunc(func(event *notification.ProblemEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[name]++
+			if calls[name] <= failures {
+				return errors.New(name + " unavailable")
+			}
+			return nil
+		})
+	}
+	config := &notification.Config{Pipeline: &notification.PipelineConfig{Routes: []*notification.RouteConfig{
+		{Name: "all", Handlers: []string{"tickets", "bsm"}},
+	}}}
+	pipeline, err := notification.NewPipeline(config, map[string]notification.Handler{"tickets": counting("tickets", 0), "bsm": counting("bsm", 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue, err := notification.NewQueue(&notification.QueueConfig{Directory: dir, MaxAttempts: 5, InitialBackoff: "10ms", MaxBackoff: "20ms"}, pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue.Start()
+	defer queue.Stop()
+
+	if err := queue.Handle(&notification.ProblemEvent{Notification: &notification.Default{PID: "42"}}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls["bsm"] == 3
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["tickets"] != 1 {
+		t.Errorf("expected tickets to receive the event once, got %d", calls["tickets"])
+	}
+}
@@ -0,0 +1,223 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/dtcookie/dynatrace/apis/cluster"
+	"github.com/dtcookie/dynatrace/log"
+)
+
+// Server receives problem notifications via HTTP and passes them through the configured pipeline.
+// Besides the notifications it serves `/healthz`, `/readyz`, `/metrics` in the Prometheus text format
+// and `/history` if a history and a credential to protect it with are configured, see AuthConfig.HistoryToken.
+// Without such a credential `/history` is refused with 403.
+type Server struct {
+	config          *Config
+	listener        *listener
+	mux             *http.ServeMux
+	http            *http.Server
+	shutdownTimeout time.Duration
+	ready           int32
+}
+
+// NewServer creates a server passing the received problem notifications through the pipeline configured in the config.
+// The routes of the pipeline refer to the handlers by their names.
+func NewServer(config *Config, handlers map[string]Handler) (*Server, error) {
+	pipeline, err := NewPipeline(config, handlers)
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{
+		config:          config,
+		listener:        newListener(config, pipeline),
+		mux:             http.NewServeMux(),
+		shutdownTimeout: 30 * time.Second,
+	}
+	if config.ShutdownTimeout != "" {
+		if server.shutdownTimeout, err = time.ParseDuration(config.ShutdownTimeout); err != nil {
+			return nil, fmt.Errorf("invalid shutdown timeout: %s", err.Error())
+		}
+	}
+	if server.listener.auth, err = newAuthenticator(config.Auth, server.listener.metrics); err != nil {
+		return nil, err
+	}
+	server.http = &http.Server{Addr: net.JoinHostPort(config.BindAddress, strconv.Itoa(config.ListenPort)), Handler: server.mux}
+	if config.TLS != nil {
+		if server.http.TLSConfig, err = newTLSConfig(config.TLS); err != nil {
+			return nil, err
+		}
+	}
+	if config.State != nil {
+		if server.listener.states, err = NewStateStore(config.State); err != nil {
+			return nil, err
+		}
+	}
+	if config.History != nil {
+		if server.listener.history, err = NewHistory(config.History); err != nil {
+			return nil, err
+		}
+	}
+	if config.Queue != nil {
+		if server.listener.queue, err = NewQueue(config.Queue, HandlerFunc(server.listener.process)); err != nil {
+			return nil, err
+		}
+	}
+
+	server.mux.HandleFunc("/", server.listener.handleHTTP)
+	server.mux.HandleFunc("/healthz", func(w http.ResponseWriter, request *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	server.mux.HandleFunc("/readyz", func(w http.ResponseWriter, request *http.Request) {
+		if atomic.LoadInt32(&server.ready) == 0 {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	server.mux.HandleFunc("/metrics", func(w http.ResponseWriter, request *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		server.listener.metrics.write(w)
+	})
+	server.mux.HandleFunc("/history", server.handleHistory)
+	if config.History != nil && !server.listener.auth.servesHistory() {
+		log.Warn("the history is not served via /history, as no credential to protect it with is configured")
+	}
+	return server, nil
+}
+
+// handleHistory answers queries of the history, see ParseQuery for the parameters and AuthConfig.HistoryToken for the authentication
+func (server *Server) handleHistory(w http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if reason, ok := server.listener.auth.authorizeHistory(request); !ok {
+		server.listener.auth.reject(w, request, reason)
+		return
+	}
+	if server.listener.history == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound)+": no history configured", http.StatusNotFound)
+		return
+	}
+	query, err := ParseQuery(request.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest)+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	records, err := server.listener.history.Query(query)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError)+": "+err.Error(), http.StatusInternalServerError)
+		log.Error(err)
+		return
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError)+": "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// Handler returns the handler serving the problem notifications and the operational endpoints
+func (server *Server) Handler() http.Handler {
+	return server.mux
+}
+
+// ListenAndServe listens on the configured bind address and port until the server gets shut down
+func (server *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", server.http.Addr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(ln)
+}
+
+// Serve accepts problem notifications on the given listener until the server gets shut down
+func (server *Server) Serve(ln net.Listener) error {
+	var err error
+
+	config := server.config
+	if len(config.APIBaseURL) > 0 && config.Credentials != nil && config.Credentials.Configured() {
+		var clusterVersion string
+		clusterAPI := cluster.NewAPI(server.listener.restConfig, config.APIBaseURL, config.Credentials)
+		if clusterVersion, err = clusterAPI.Get(); err != nil {
+			return err
+		}
+		log.Info("Dynatrace Cluster Version: " + clusterVersion)
+	}
+	if server.listener.queue != nil {
+		server.listener.queue.Start()
+		log.Info("Delivering problem notifications via the queue in " + config.Queue.Directory)
+	}
+
+	atomic.StoreInt32(&server.ready, 1)
+	log.Info(fmt.Sprintf("Listening on %s for incoming problem notifications.", ln.Addr().String()))
+	if config.TLS != nil {
+		err = server.http.ServeTLS(ln, config.TLS.CertFile, config.TLS.KeyFile)
+	} else {
+		err = server.http.Serve(ln)
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting problem notifications and waits for the notifications in flight to get delivered,
+// or the context to expire. Deliveries still pending in the queue are picked up again after a restart.
+func (server *Server) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&server.ready, 0)
+	err := server.http.Shutdown(ctx)
+	drained := make(chan struct{})
+	go func() {
+		server.listener.inFlight.Wait()
+		if server.listener.queue != nil {
+			server.listener.queue.Stop()
+		}
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// Run serves problem notifications until the process receives SIGTERM or an interrupt, then shuts down gracefully
+func (server *Server) Run() error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-errs:
+		return err
+	case sig := <-signals:
+		log.Info(fmt.Sprintf("received %s, shutting down", sig.String()))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), server.shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		return err
+	}
+	return <-errs
+}
@@ -0,0 +1,167 @@
+package notification_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func TestServer(t *testing.T) {
+	var delivered int32
+	handler := notification.HandlerFunc(func(event *notification.ProblemEvent) error {
+		time.Sleep(200 * time.Millisecond)
+		atomic.AddInt32(&delivered, 1)
+		return nil
+	})
+	config := &notification.Config{Auth: &notification.AuthConfig{HeaderName: "X-Secret", HeaderSecret: "secret"}}
+	server, err := notification.NewServer(config, map[string]notification.Handler{"default": handler})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := "http://" + ln.Addr().String()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(ln)
+	}()
+
+	get := func(path string) (int, string) {
+		response, err := client.Get(baseURL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		body, _ := ioutil.ReadAll(response.Body)
+		return response.StatusCode, string(body)
+	}
+	post := func(secret string) int {
+		request, _ := http.NewRequest(http.MethodPost, baseURL+"/", strings.NewReader(`{"PID":"42","State":"OPEN"}`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Secret", secret)
+		response, err := client.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	waitFor(t, func() bool {
+		status, _ := get("/readyz")
+		return status == http.StatusOK
+	})
+	if status, _ := get("/healthz"); status != http.StatusOK {
+		t.Errorf("expected /healthz to respond with 200, got %d", status)
+	}
+	if status := post("wrong"); status != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a wrong secret, got %d", status)
+	}
+	if status := post("secret"); status != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&delivered) != 1 {
+		t.Error("expected the notification in flight to be delivered before shutdown completed")
+	}
+
+	recorder := &responseRecorder{header: http.Header{}}
+	request, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	server.Handler().ServeHTTP(recorder, request)
+	for _, expected := range []string{
+		"dynatrace_notifications_received_total 1",
+		`dynatrace_notifications_rejected_total{reason="invalid_secret"} 1`,
+		"dynatrace_notifications_delivered_total 1",
+		"dynatrace_notifications_failed_total 0",
+		"dynatrace_notifications_delivery_latency_seconds_count 1",
+	} {
+		if !strings.Contains(recorder.body.String(), expected) {
+			t.Errorf("expected metrics to contain '%s', got\n%s", expected, recorder.body.String())
+		}
+	}
+}
+
+func TestServerRetryAfterFailedEnqueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config := &notification.Config{
+		State: &notification.StateConfig{Deduplicate: true},
+		Queue: &notification.QueueConfig{Directory: dir},
+	}
+	noop := notification.HandlerFunc(func(event *notification.ProblemEvent) error { return nil })
+	server, err := notification.NewServer(config, map[string]notification.Handler{"default": noop})
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := func() int {
+		recorder := &responseRecorder{header: http.Header{}}
+		request, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"PID":"42","State":"OPEN"}`))
+		request.Header.Set("Content-Type", "application/json")
+		server.Handler().ServeHTTP(recorder, request)
+		return recorder.status
+	}
+
+	pending := filepath.Join(dir, "pending")
+	if err := os.RemoveAll(pending); err != nil {
+		t.Fatal(err)
+	}
+	if status := post(); status != http.StatusInternalServerError {
+		t.Fatalf("expected 500 while the queue can't persist, got %d", status)
+	}
+	if err := os.Mkdir(pending, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if status := post(); status != http.StatusNoContent {
+		t.Fatalf("expected 204 for the retry, got %d", status)
+	}
+	if files, _ := ioutil.ReadDir(pending); len(files) != 1 {
+		t.Errorf("expected the retry to be enqueued instead of dropped as duplicate, got %d pending deliveries", len(files))
+	}
+	if status := post(); status != http.StatusNoContent {
+		t.Fatalf("expected 204 for the duplicate, got %d", status)
+	}
+	if files, _ := ioutil.ReadDir(pending); len(files) != 1 {
+		t.Errorf("expected the duplicate to be dropped, got %d pending deliveries", len(files))
+	}
+}
+
+type responseRecorder struct {
+	header http.Header
+	body   strings.Builder
+	status int
+}
+
+func (recorder *responseRecorder) Header() http.Header {
+	return recorder.header
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	return recorder.body.Write(data)
+}
+
+func (recorder *responseRecorder) WriteHeader(status int) {
+	recorder.status = status
+}
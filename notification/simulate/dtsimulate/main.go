@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+	"github.com/dtcookie/dynatrace/notification/simulate"
+)
+
+type values []string
+
+func (me *values) String() string {
+	return strings.Join(*me, ",")
+}
+
+func (me *values) Set(value string) error {
+	*me = append(*me, value)
+	return nil
+}
+
+func main() {
+	var listenerURL, sample, templateFile, historyDir, from, to string
+	var username, password, signatureSecret, signatureHeader string
+	var requests, concurrency int
+	var rate float64
+	var list bool
+	var vars, headers values
+
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&listenerURL, "url", "", "")
+	flagSet.StringVar(&sample, "sample", "", "")
+	flagSet.StringVar(&templateFile, "template", "", "")
+	flagSet.StringVar(&historyDir, "history", "", "")
+	flagSet.StringVar(&from, "from", "", "")
+	flagSet.StringVar(&to, "to", "", "")
+	flagSet.Var(&vars, "var", "")
+	flagSet.IntVar(&requests, "n", 1, "")
+	flagSet.IntVar(&concurrency, "concurrency", 1, "")
+	flagSet.Float64Var(&rate, "rate", 0, "")
+	flagSet.Var(&headers, "header", "")
+	flagSet.StringVar(&username, "user", "", "")
+	flagSet.StringVar(&password, "password", "", "")
+	flagSet.StringVar(&signatureSecret, "signature-secret", "", "")
+	flagSet.StringVar(&signatureHeader, "signature-header", "", "")
+	flagSet.BoolVar(&list, "list", false, "")
+	flagSet.Usage = func() {}
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err.Error())
+		usage()
+		os.Exit(2)
+	}
+	if list {
+		for _, name := range simulate.SampleNames() {
+			fmt.Println(name)
+		}
+		return
+	}
+	sources := 0
+	for _, s := range []string{sample, templateFile, historyDir} {
+		if s != "" {
+			sources++
+		}
+	}
+	if listenerURL == "" || sources > 1 || len(flagSet.Args()) > 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	variables := map[string]string{}
+	for _, v := range vars {
+		idx := strings.Index(v, "=")
+		if idx < 0 {
+			fmt.Printf("invalid variable '%s', expected <name>=<value>\n", v)
+			os.Exit(2)
+		}
+		variables[v[:idx]] = v[idx+1:]
+	}
+	options := &simulate.Options{URL: listenerURL, Requests: requests, Rate: rate, Concurrency: concurrency, Headers: map[string]string{}, Username: username, Password: password, SignatureSecret: signatureSecret, SignatureHeader: signatureHeader}
+	for _, header := range headers {
+		idx := strings.Index(header, ":")
+		if idx < 0 {
+			fmt.Printf("invalid header '%s', expected '<name>: <value>'\n", header)
+			os.Exit(2)
+		}
+		options.Headers[strings.TrimSpace(header[:idx])] = strings.TrimSpace(header[idx+1:])
+	}
+
+	source, err := newSource(sample, templateFile, historyDir, from, to, variables)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	report, err := simulate.Run(source, options)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(report.String())
+}
+
+func newSource(sample string, templateFile string, historyDir string, from string, to string, variables map[string]string) (simulate.Source, error) {
+	switch {
+	case templateFile != "":
+		data, err := ioutil.ReadFile(templateFile)
+		if err != nil {
+			return nil, err
+		}
+		return simulate.NewTemplateSource(templateFile, string(data), variables)
+	case historyDir != "":
+		params := url.Values{}
+		if from != "" {
+			params.Set("from", from)
+		}
+		if to != "" {
+			params.Set("to", to)
+		}
+		query, err := notification.ParseQuery(params, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		history, err := notification.NewHistory(&notification.HistoryConfig{Directory: historyDir})
+		if err != nil {
+			return nil, err
+		}
+		records, err := history.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		return simulate.NewHistorySource(records)
+	}
+	if sample == "" {
+		sample = "availability"
+	}
+	return simulate.NewSampleSource(sample, variables)
+}
+
+func usage() {
+	fmt.Println()
+	fmt.Println("USAGE: dtsimulate -url <listener-url> [-sample <name> | -template <file> | -history <history-directory> [-from <time>] [-to <time>]] [-var <name>=<value>]...")
+	fmt.Println("                  [-n <requests>] [-rate <requests-per-second>] [-concurrency <n>]")
+	fmt.Println("                  [-header '<name>: <value>']... [-user <user> -password <password>] [-signature-secret <secret> [-signature-header <name>]]")
+	fmt.Println("       dtsimulate -list")
+	fmt.Println("  Posts problem notifications to a listener and reports the response codes and latencies.")
+	fmt.Println("  -sample    one of the bundled samples, see -list, `availability` if no other source is given")
+	fmt.Println("  -template  a text/template producing the payload, with .PID, .ProblemID, .Seq, .Now, .Start and {{ var \"name\" \"default\" }}")
+	fmt.Println("  -history   replays the notifications recorded by a listener, optionally limited to -from and -to")
+	fmt.Println("  -var       sets a variable of the sample or template, e.g. -var entity=checkout-service")
+	fmt.Println("  -rate      limits the requests per second, by default they are sent as fast as -concurrency allows")
+	fmt.Println("  -signature-header  the header the listener expects the signature in, `X-Signature` by default")
+}
@@ -0,0 +1,179 @@
+package simulate
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options configure how payloads are posted to a listener
+type Options struct {
+	URL         string
+	Requests    int     // the number of requests, 1 if not specified
+	Rate        float64 // requests per second, as fast as the concurrency allows if 0
+	Concurrency int     // the maximum number of requests in flight, 1 if not specified
+	Headers     map[string]string
+	Username    string
+	Password    string
+	// SignatureSecret signs the payloads with HMAC-SHA256 within SignatureHeader, `X-Signature` if not specified,
+	// matching the signature verification of the listener
+	SignatureSecret string
+	SignatureHeader string
+	Timeout         time.Duration // 30s if not specified
+}
+
+// Report summarizes the responses of the listener
+type Report struct {
+	Requests    int            `json:"requests"`
+	StatusCodes map[int]int    `json:"statusCodes"`
+	Errors      map[string]int `json:"errors"`
+	Duration    time.Duration  `json:"duration"`
+	Min         time.Duration  `json:"min"`
+	Mean        time.Duration  `json:"mean"`
+	P50         time.Duration  `json:"p50"`
+	P90         time.Duration  `json:"p90"`
+	P99         time.Duration  `json:"p99"`
+	Max         time.Duration  `json:"max"`
+}
+
+// Run posts the payloads produced by the source to the listener and reports the outcome
+func Run(source Source, options *Options) (*Report, error) {
+	requests, concurrency := options.Requests, options.Concurrency
+	if requests <= 0 {
+		requests = 1
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	var interval time.Duration
+	if options.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / options.Rate)
+	}
+
+	report := &Report{StatusCodes: map[int]int{}, Errors: map[string]int{}}
+	latencies := []time.Duration{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+
+	start := time.Now()
+	for seq := 0; seq < requests; seq++ {
+		if interval > 0 {
+			if wait := time.Until(start.Add(time.Duration(seq) * interval)); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		payload, err := source.Next(seq)
+		if err != nil {
+			wg.Wait()
+			return nil, err
+		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(payload []byte) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			requestStart := time.Now()
+			status, err := post(client, options, payload)
+			latency := time.Since(requestStart)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Requests++
+			if err != nil {
+				report.Errors[err.Error()]++
+				return
+			}
+			report.StatusCodes[status]++
+			latencies = append(latencies, latency)
+		}(payload)
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		var sum time.Duration
+		for _, latency := range latencies {
+			sum += latency
+		}
+		percentile := func(p float64) time.Duration {
+			return latencies[int(p*float64(len(latencies)-1))]
+		}
+		report.Min, report.Max = latencies[0], latencies[len(latencies)-1]
+		report.Mean = sum / time.Duration(len(latencies))
+		report.P50, report.P90, report.P99 = percentile(0.5), percentile(0.9), percentile(0.99)
+	}
+	return report, nil
+}
+
+func post(client *http.Client, options *Options, payload []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, options.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range options.Headers {
+		request.Header.Set(key, value)
+	}
+	if options.Username != "" || options.Password != "" {
+		request.SetBasicAuth(options.Username, options.Password)
+	}
+	if options.SignatureSecret != "" {
+		mac := hmac.New(sha256.New, []byte(options.SignatureSecret))
+		mac.Write(payload)
+		header := options.SignatureHeader
+		if header == "" {
+			header = "X-Signature"
+		}
+		request.Header.Set(header, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, response.Body)
+	return response.StatusCode, nil
+}
+
+func (me *Report) String() string {
+	lines := []string{fmt.Sprintf("%d requests in %v (%.1f/s)", me.Requests, me.Duration.Round(time.Millisecond), float64(me.Requests)/me.Duration.Seconds())}
+	codes := []int{}
+	for code := range me.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		lines = append(lines, fmt.Sprintf("  %d %s: %d", code, http.StatusText(code), me.StatusCodes[code]))
+	}
+	errs := []string{}
+	for err := range me.Errors {
+		errs = append(errs, err)
+	}
+	sort.Strings(errs)
+	for _, err := range errs {
+		lines = append(lines, fmt.Sprintf("  error %s: %d", err, me.Errors[err]))
+	}
+	lines = append(lines, fmt.Sprintf("latency min %v, mean %v, p50 %v, p90 %v, p99 %v, max %v",
+		me.Min.Round(time.Microsecond), me.Mean.Round(time.Microsecond), me.P50.Round(time.Microsecond),
+		me.P90.Round(time.Microsecond), me.P99.Round(time.Microsecond), me.Max.Round(time.Microsecond)))
+	return strings.Join(lines, "\n")
+}
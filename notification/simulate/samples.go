@@ -0,0 +1,210 @@
+package simulate
+
+// samples are realistic problem notifications as sent by the default custom integration of Dynatrace,
+// written as templates, see NewTemplateSource for the available data
+var samples = map[string]string{
+	"availability": `{
+  "PID": "{{ .PID }}",
+  "ProblemID": "{{ .ProblemID }}",
+  "State": "OPEN",
+  "ProblemTitle": "{{ var "title" "Service unavailable" }}",
+  "ProblemURL": "https://{{ var "tenant" "abc12345.live.dynatrace.com" }}/#problems/problemdetails;pid={{ .PID }}",
+  "ProblemImpact": "SERVICE",
+  "ProblemSeverity": "AVAILABILITY",
+  "ImpactedEntity": "{{ var "entity" "checkout-service" }}",
+  "Tags": "{{ var "tags" "team:payments, env:production" }}",
+  "ProblemDetailsText": "Service unavailable on {{ var "entity" "checkout-service" }}",
+  "ProblemDetailsJSON": {
+    "id": "{{ .PID }}",
+    "startTime": {{ .Start }},
+    "endTime": -1,
+    "displayName": "{{ .ProblemID }}",
+    "impactLevel": "SERVICE",
+    "status": "OPEN",
+    "severityLevel": "AVAILABILITY",
+    "commentCount": 0,
+    "tagsOfAffectedEntities": [
+      { "context": "CONTEXTLESS", "key": "team", "value": "payments" },
+      { "context": "CONTEXTLESS", "key": "env", "value": "production" }
+    ],
+    "rankedEvents": [
+      {
+        "entityId": "{{ var "entityId" "SERVICE-5C0E0D7A3E4C1F2B" }}",
+        "entityName": "{{ var "entity" "checkout-service" }}",
+        "startTime": {{ .Start }},
+        "endTime": -1,
+        "severityLevel": "AVAILABILITY",
+        "impactLevel": "SERVICE",
+        "eventType": "SERVICE_UNAVAILABLE",
+        "status": "OPEN",
+        "isRootCause": true,
+        "isClusterWide": false
+      }
+    ],
+    "rankedImpacts": [
+      {
+        "entityId": "{{ var "entityId" "SERVICE-5C0E0D7A3E4C1F2B" }}",
+        "entityName": "{{ var "entity" "checkout-service" }}",
+        "severityLevel": "AVAILABILITY",
+        "impactLevel": "SERVICE",
+        "eventType": "SERVICE_UNAVAILABLE"
+      }
+    ],
+    "affectedCounts": { "INFRASTRUCTURE": 0, "SERVICE": 1, "APPLICATION": 0, "ENVIRONMENT": 0 },
+    "recoveredCounts": { "INFRASTRUCTURE": 0, "SERVICE": 0, "APPLICATION": 0, "ENVIRONMENT": 0 },
+    "hasRootCause": true
+  }
+}`,
+	"performance": `{
+  "PID": "{{ .PID }}",
+  "ProblemID": "{{ .ProblemID }}",
+  "State": "OPEN",
+  "ProblemTitle": "{{ var "title" "Response time degradation" }}",
+  "ProblemURL": "https://{{ var "tenant" "abc12345.live.dynatrace.com" }}/#problems/problemdetails;pid={{ .PID }}",
+  "ProblemImpact": "APPLICATION",
+  "ProblemSeverity": "PERFORMANCE",
+  "ImpactedEntity": "{{ var "entity" "www.example.com" }}",
+  "Tags": "{{ var "tags" "team:web, env:production" }}",
+  "ProblemDetailsText": "Response time degradation on {{ var "entity" "www.example.com" }}",
+  "ProblemDetailsJSON": {
+    "id": "{{ .PID }}",
+    "startTime": {{ .Start }},
+    "endTime": -1,
+    "displayName": "{{ .ProblemID }}",
+    "impactLevel": "APPLICATION",
+    "status": "OPEN",
+    "severityLevel": "PERFORMANCE",
+    "commentCount": 0,
+    "tagsOfAffectedEntities": [
+      { "context": "CONTEXTLESS", "key": "team", "value": "web" }
+    ],
+    "rankedEvents": [
+      {
+        "entityId": "{{ var "entityId" "APPLICATION-EA7C4B59F27D43EB" }}",
+        "entityName": "{{ var "entity" "www.example.com" }}",
+        "startTime": {{ .Start }},
+        "endTime": -1,
+        "severityLevel": "PERFORMANCE",
+        "impactLevel": "APPLICATION",
+        "eventType": "APPLICATION_SLOWDOWN",
+        "status": "OPEN",
+        "isRootCause": false,
+        "isClusterWide": false
+      }
+    ],
+    "rankedImpacts": [
+      {
+        "entityId": "{{ var "entityId" "APPLICATION-EA7C4B59F27D43EB" }}",
+        "entityName": "{{ var "entity" "www.example.com" }}",
+        "severityLevel": "PERFORMANCE",
+        "impactLevel": "APPLICATION",
+        "eventType": "APPLICATION_SLOWDOWN"
+      }
+    ],
+    "affectedCounts": { "INFRASTRUCTURE": 0, "SERVICE": 0, "APPLICATION": 1, "ENVIRONMENT": 0 },
+    "recoveredCounts": { "INFRASTRUCTURE": 0, "SERVICE": 0, "APPLICATION": 0, "ENVIRONMENT": 0 },
+    "hasRootCause": false
+  }
+}`,
+	"resource": `{
+  "PID": "{{ .PID }}",
+  "ProblemID": "{{ .ProblemID }}",
+  "State": "{{ var "state" "OPEN" }}",
+  "ProblemTitle": "{{ var "title" "CPU saturation" }}",
+  "ProblemURL": "https://{{ var "tenant" "abc12345.live.dynatrace.com" }}/#problems/problemdetails;pid={{ .PID }}",
+  "ProblemImpact": "INFRASTRUCTURE",
+  "ProblemSeverity": "RESOURCE_CONTENTION",
+  "ImpactedEntity": "{{ var "entity" "db-host-01" }}",
+  "Tags": "{{ var "tags" "team:platform" }}",
+  "ProblemDetailsText": "CPU saturation on {{ var "entity" "db-host-01" }}",
+  "ProblemDetailsJSON": {
+    "id": "{{ .PID }}",
+    "startTime": {{ .Start }},
+    "endTime": -1,
+    "displayName": "{{ .ProblemID }}",
+    "impactLevel": "INFRASTRUCTURE",
+    "status": "OPEN",
+    "severityLevel": "RESOURCE_CONTENTION",
+    "commentCount": 0,
+    "tagsOfAffectedEntities": [
+      { "context": "CONTEXTLESS", "key": "team", "value": "platform" }
+    ],
+    "rankedEvents": [
+      {
+        "entityId": "{{ var "entityId" "HOST-9F1B2C3D4E5F6A7B" }}",
+        "entityName": "{{ var "entity" "db-host-01" }}",
+        "startTime": {{ .Start }},
+        "endTime": -1,
+        "severityLevel": "RESOURCE_CONTENTION",
+        "impactLevel": "INFRASTRUCTURE",
+        "eventType": "CPU_SATURATED",
+        "status": "OPEN",
+        "isRootCause": true,
+        "isClusterWide": false
+      }
+    ],
+    "rankedImpacts": [
+      {
+        "entityId": "{{ var "entityId" "HOST-9F1B2C3D4E5F6A7B" }}",
+        "entityName": "{{ var "entity" "db-host-01" }}",
+        "severityLevel": "RESOURCE_CONTENTION",
+        "impactLevel": "INFRASTRUCTURE",
+        "eventType": "CPU_SATURATED"
+      }
+    ],
+    "affectedCounts": { "INFRASTRUCTURE": 1, "SERVICE": 0, "APPLICATION": 0, "ENVIRONMENT": 0 },
+    "recoveredCounts": { "INFRASTRUCTURE": 0, "SERVICE": 0, "APPLICATION": 0, "ENVIRONMENT": 0 },
+    "hasRootCause": true
+  }
+}`,
+	"resolved": `{
+  "PID": "{{ .PID }}",
+  "ProblemID": "{{ .ProblemID }}",
+  "State": "RESOLVED",
+  "ProblemTitle": "{{ var "title" "Service unavailable" }}",
+  "ProblemURL": "https://{{ var "tenant" "abc12345.live.dynatrace.com" }}/#problems/problemdetails;pid={{ .PID }}",
+  "ProblemImpact": "SERVICE",
+  "ProblemSeverity": "AVAILABILITY",
+  "ImpactedEntity": "{{ var "entity" "checkout-service" }}",
+  "Tags": "{{ var "tags" "team:payments, env:production" }}",
+  "ProblemDetailsText": "Service unavailable on {{ var "entity" "checkout-service" }} has been resolved",
+  "ProblemDetailsJSON": {
+    "id": "{{ .PID }}",
+    "startTime": {{ .Start }},
+    "endTime": {{ .Now }},
+    "displayName": "{{ .ProblemID }}",
+    "impactLevel": "SERVICE",
+    "status": "CLOSED",
+    "severityLevel": "AVAILABILITY",
+    "commentCount": 0,
+    "tagsOfAffectedEntities": [
+      { "context": "CONTEXTLESS", "key": "team", "value": "payments" }
+    ],
+    "rankedEvents": [],
+    "rankedImpacts": [
+      {
+        "entityId": "{{ var "entityId" "SERVICE-5C0E0D7A3E4C1F2B" }}",
+        "entityName": "{{ var "entity" "checkout-service" }}",
+        "severityLevel": "AVAILABILITY",
+        "impactLevel": "SERVICE",
+        "eventType": "SERVICE_UNAVAILABLE"
+      }
+    ],
+    "affectedCounts": { "INFRASTRUCTURE": 0, "SERVICE": 0, "APPLICATION": 0, "ENVIRONMENT": 0 },
+    "recoveredCounts": { "INFRASTRUCTURE": 0, "SERVICE": 1, "APPLICATION": 0, "ENVIRONMENT": 0 },
+    "hasRootCause": true
+  }
+}`,
+	"test": `{
+  "PID": "999999",
+  "ProblemID": "999999",
+  "State": "OPEN",
+  "ProblemTitle": "Dynatrace problem notification test run",
+  "ProblemURL": "https://{{ var "tenant" "abc12345.live.dynatrace.com" }}/#problems",
+  "ProblemImpact": "INFRASTRUCTURE",
+  "ProblemSeverity": "ERROR",
+  "ImpactedEntity": "Dynatrace problem notification test run",
+  "Tags": "",
+  "ProblemDetailsText": "Dynatrace problem notification test run details"
+}`,
+}
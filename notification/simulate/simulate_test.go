@@ -0,0 +1,124 @@
+package simulate_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+	"github.com/dtcookie/dynatrace/notification/simulate"
+)
+
+func TestSamples(t *testing.T) {
+	for _, name := range simulate.SampleNames() {
+		source, err := simulate.NewSampleSource(name, map[string]string{"entity": "payment-service"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload, err := source.Next(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n notification.Default
+		if err := json.Unmarshal(payload, &n); err != nil {
+			t.Errorf("sample '%s' is not a valid notification: %s", name, err.Error())
+			continue
+		}
+		if n.PID == "" || n.State == "" {
+			t.Errorf("sample '%s' lacks PID or state", name)
+		}
+		if name != "test" && (n.ImpactedEntity != "payment-service" || n.ProblemDetailsJSON == nil || len(n.ProblemDetailsJSON.RankedImpacts) == 0) {
+			t.Errorf("sample '%s' lacks the impacted entity or problem details", name)
+		}
+	}
+}
+
+func TestSampleVariablesEscaped(t *testing.T) {
+	title := "disk \"/var\" full\nat C:\\data <100%>"
+	for _, name := range simulate.SampleNames() {
+		source, err := simulate.NewSampleSource(name, map[string]string{"title": title, "entity": title})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload, err := source.Next(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n notification.Default
+		if err := json.Unmarshal(payload, &n); err != nil {
+			t.Errorf("sample '%s' is not valid JSON: %s\n%s", name, err.Error(), string(payload))
+			continue
+		}
+		if name != "test" && (n.Title != title || n.ImpactedEntity != title) {
+			t.Errorf("sample '%s': expected the variables to be kept verbatim, got '%s' and '%s'", name, n.Title, n.ImpactedEntity)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	var received int32
+	handler := notification.HandlerFunc(func(event *notification.ProblemEvent) error {
+		atomic.AddInt32(&received, 1)
+		return nil
+	})
+	server, err := notification.NewServer(&notification.Config{}, map[string]notification.Handler{"default": handler})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := httptest.NewServer(server.Handler())
+	defer listener.Close()
+
+	source, err := simulate.NewSampleSource("availability", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := simulate.Run(source, &simulate.Options{URL: listener.URL, Requests: 20, Concurrency: 4, Rate: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Requests != 20 || report.StatusCodes[http.StatusNoContent] != 20 {
+		t.Errorf("expected 20 requests answered with 204, got\n%s", report.String())
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&received) < 20 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if received := atomic.LoadInt32(&received); received != 20 {
+		t.Errorf("expected the handler to receive 20 notifications, got %d", received)
+	}
+}
+
+func TestRunSigned(t *testing.T) {
+	handler := notification.HandlerFunc(func(event *notification.ProblemEvent) error { return nil })
+	config := &notification.Config{Auth: &notification.AuthConfig{SignatureHeader: "X-Hub-Signature-256", SignatureSecret: "secret"}}
+	server, err := notification.NewServer(config, map[string]notification.Handler{"default": handler})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := httptest.NewServer(server.Handler())
+	defer listener.Close()
+
+	for _, test := range []struct {
+		header   string
+		expected int
+	}{
+		{"X-Hub-Signature-256", http.StatusNoContent},
+		{"", http.StatusUnauthorized},
+	} {
+		source, err := simulate.NewSampleSource("availability", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		options := &simulate.Options{URL: listener.URL, Requests: 2, SignatureSecret: "secret", SignatureHeader: test.header}
+		report, err := simulate.Run(source, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.StatusCodes[test.expected] != 2 {
+			t.Errorf("signature header '%s': expected 2 requests answered with %d, got\n%s", test.header, test.expected, report.String())
+		}
+	}
+}
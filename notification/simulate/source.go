@@ -0,0 +1,135 @@
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+// Source produces the payloads to post, seq counts the requests starting with 0
+type Source interface {
+	Next(seq int) ([]byte, error)
+}
+
+// Data is what templates are executed with
+type Data struct {
+	Seq       int    // the number of the request, starting with 0
+	PID       string // a random PID, unique per request
+	ProblemID string // a ProblemID, unique per request
+	Now       int64  // the current time in UTC milliseconds
+	Start     int64  // five minutes ago in UTC milliseconds, the start of the problem
+}
+
+// SampleNames lists the bundled samples
+func SampleNames() []string {
+	names := []string{}
+	for name := range samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSampleSource produces the bundled sample with the given name, customized by the variables
+func NewSampleSource(name string, vars map[string]string) (Source, error) {
+	text, found := samples[name]
+	if !found {
+		return nil, fmt.Errorf("unknown sample '%s'", name)
+	}
+	return NewTemplateSource(name, text, vars)
+}
+
+// NewTemplateSource produces payloads from a text/template executed with Data.
+// The variables are available via `{{ var "name" "default" }}`, escaped for use within a JSON string.
+func NewTemplateSource(name string, text string, vars map[string]string) (Source, error) {
+	funcs := template.FuncMap{
+		"var": func(key string, fallback string) (string, error) {
+			value, found := vars[key]
+			if !found {
+				value = fallback
+			}
+			return jsonEscape(value)
+		},
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &templateSource{template: tmpl, random: rand.New(rand.NewSource(time.Now().UnixNano()))}, nil
+}
+
+// jsonEscape returns the value encoded as JSON string, without the surrounding quotes
+func jsonEscape(value string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	s := strings.TrimSuffix(buf.String(), "\n")
+	return s[1 : len(s)-1], nil
+}
+
+type templateSource struct {
+	template *template.Template
+	mu       sync.Mutex
+	random   *rand.Rand
+}
+
+func (source *templateSource) Next(seq int) ([]byte, error) {
+	source.mu.Lock()
+	pid := strconv.FormatInt(-source.random.Int63n(1<<62), 10) + "_" + strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10) + "V2"
+	source.mu.Unlock()
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	data := &Data{Seq: seq, PID: pid, ProblemID: strconv.Itoa(1000 + seq), Now: now, Start: now - 5*60*1000}
+	var buf bytes.Buffer
+	if err := source.template.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewHistorySource replays the notifications recorded by the history of a listener, in the order they were received.
+// Once all notifications have been replayed it starts over.
+func NewHistorySource(records []*notification.Record) (Source, error) {
+	if len(records) == 0 {
+		return nil, errors.New("no recorded notifications to replay")
+	}
+	payloads := [][]byte{}
+	for _, record := range records {
+		if record.Event.Notification == nil {
+			continue
+		}
+		n := *record.Event.Notification
+		if n.ProblemDetailsJSON == nil {
+			n.ProblemDetailsJSON = record.Event.Problem
+		}
+		data, err := json.Marshal(&n)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, data)
+	}
+	if len(payloads) == 0 {
+		return nil, errors.New("no recorded notifications to replay")
+	}
+	return &historySource{payloads: payloads}, nil
+}
+
+type historySource struct {
+	payloads [][]byte
+}
+
+func (source *historySource) Next(seq int) ([]byte, error) {
+	return source.payloads[seq%len(source.payloads)], nil
+}
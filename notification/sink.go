@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// SinkConfig configures an HTTP sink within the sinks section of the config file.
+// The routes of the pipeline refer to sinks by their names, just like to handlers registered in code.
+type SinkConfig struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"` // `POST` if not specified
+	Headers map[string]string `json:"headers,omitempty"`
+	// Username and Password enable HTTP basic authentication
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// BearerToken is sent within the Authorization header
+	BearerToken string `json:"bearerToken,omitempty"`
+	// Template or TemplateFile define the payload, see Template for the available helpers.
+	// Without template the whole problem event is sent as JSON.
+	Template     string            `json:"template,omitempty"`
+	TemplateFile string            `json:"templateFile,omitempty"`
+	ContentType  string            `json:"contentType,omitempty"` // `application/json` if not specified
+	Severities   map[string]string `json:"severities,omitempty"`  // the severity mapping available to the template
+	Timeout      string            `json:"timeout,omitempty"`     // `30s` if not specified
+}
+
+// Sink is a Handler sending problem events to an HTTP endpoint.
+// Responses with a status code other than 2xx are reported as errors, which makes the delivery queue retry them.
+type Sink struct {
+	config   *SinkConfig
+	template *Template
+	client   *http.Client
+}
+
+// NewSink creates an HTTP sink. Insecure and NoProxy of the listener config apply to sinks as well.
+func NewSink(name string, config *SinkConfig, listenerConfig *Config) (*Sink, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("sink '%s': no url specified", name)
+	}
+	sink := &Sink{config: config}
+	text := config.Template
+	if config.TemplateFile != "" {
+		data, err := ioutil.ReadFile(config.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("sink '%s': %s", name, err.Error())
+		}
+		text = string(data)
+	}
+	if text != "" {
+		var err error
+		if sink.template, err = NewTemplate(name, text, config.Severities); err != nil {
+			return nil, fmt.Errorf("sink '%s': %s", name, err.Error())
+		}
+	}
+	timeout := 30 * time.Second
+	if config.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(config.Timeout); err != nil {
+			return nil, fmt.Errorf("sink '%s': invalid timeout: %s", name, err.Error())
+		}
+	}
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if listenerConfig.NoProxy {
+		transport.Proxy = nil
+	}
+	if listenerConfig.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	sink.client = &http.Client{Transport: transport, Timeout: timeout}
+	return sink, nil
+}
+
+// Handle renders the payload and sends it
+func (sink *Sink) Handle(event *ProblemEvent) error {
+	var payload []byte
+	var err error
+	if sink.template != nil {
+		payload, err = sink.template.Render(event)
+	} else {
+		payload, err = json.Marshal(event)
+	}
+	if err != nil {
+		return err
+	}
+	return sink.Send(payload)
+}
+
+// Send sends a payload rendered elsewhere, using the method, headers and authentication of the sink
+func (sink *Sink) Send(payload []byte) error {
+	method := sink.config.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	request, err := http.NewRequest(method, sink.config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	contentType := sink.config.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	request.Header.Set("Content-Type", contentType)
+	for key, value := range sink.config.Headers {
+		request.Header.Set(key, value)
+	}
+	if sink.config.Username != "" || sink.config.Password != "" {
+		request.SetBasicAuth(sink.config.Username, sink.config.Password)
+	}
+	if sink.config.BearerToken != "" {
+		request.Header.Set("Authorization", "Bearer "+sink.config.BearerToken)
+	}
+
+	response, err := sink.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s %s responded with %d: %s", method, sink.config.URL, response.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dtcookie/dynatrace/apis/problems"
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func TestSink(t *testing.T) {
+	var body, authorization, custom string
+	status := http.StatusCreated
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		data, _ := ioutil.ReadAll(request.Body)
+		body, authorization, custom = string(data), request.Header.Get("Authorization"), request.Header.Get("X-Source")
+		w.WriteHeader(status)
+	}))
+	defer target.Close()
+
+	var config notification.Config
+	if err := json.Unmarshal([]byte(`{
+		"sinks": {
+			"tickets": {
+				"url": "`+target.URL+`",
+				"method": "PUT",
+				"headers": { "X-Source": "dynatrace" },
+				"bearerToken": "abc",
+				"severities": { "AVAILABILITY": "critical" },
+				"template": "{\"id\":{{ json .Notification.PID }},\"severity\":\"{{ severity . }}\",\"owner\":\"{{ tag . \"owner\" | default \"nobody\" }}\",\"started\":\"{{ formatTime \"2006-01-02\" .Problem.StartTime }}\"}"
+			}
+		},
+		"pipeline": { "routes": [ { "handlers": [ "tickets" ] } ] }
+	}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	pipeline, err := notification.NewPipeline(&config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &notification.ProblemEvent{
+		Notification: &notification.Default{PID: "42", Severity: "AVAILABILITY", Tags: "owner:payments"},
+		Problem:      &problems.Problem{StartTime: 1593000000000},
+	}
+	if err := pipeline.Handle(event); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"id":"42","severity":"critical","owner":"payments","started":"2020-06-24"}`
+	if body != expected {
+		t.Errorf("expected payload %s, got %s", expected, body)
+	}
+	if authorization != "Bearer abc" || custom != "dynatrace" {
+		t.Errorf("expected the configured headers, got '%s' and '%s'", authorization, custom)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := pipeline.Handle(event); err == nil {
+		t.Error("expected an error for a failing target")
+	}
+	if _, err := notification.NewPipeline(&config, map[string]notification.Handler{"tickets": pipeline}); err == nil {
+		t.Error("expected an error for a handler conflicting with a sink")
+	}
+}
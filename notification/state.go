@@ -0,0 +1,257 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dtcookie/dynatrace/apis/problems"
+)
+
+// ProblemState is the state of a problem as reported by the notifications
+type ProblemState string
+
+// ProblemStates offers the known enum values
+var ProblemStates = struct {
+	Open     ProblemState
+	Resolved ProblemState
+	Merged   ProblemState
+}{
+	"OPEN",
+	"RESOLVED",
+	"MERGED",
+}
+
+// StateConfig configures the tracking of the problem lifecycles within the config file
+type StateConfig struct {
+	File string `json:"file,omitempty"` // persists the lifecycles across restarts, kept in memory only if not specified
+	// Retention defines how long resolved and merged problems are remembered, `24h` if not specified
+	Retention string `json:"retention,omitempty"`
+	// OpenRetention defines how long open problems are remembered after their last notification, `168h` (7 days) if not specified.
+	// It covers problems which never get resolved, e.g. because the notification got lost.
+	OpenRetention string `json:"openRetention,omitempty"`
+	// Deduplicate drops notifications repeating the known state of a problem,
+	// otherwise they are passed on with Lifecycle.Duplicate set
+	Deduplicate bool `json:"deduplicate,omitempty"`
+}
+
+// Transition is a change of the state of a problem
+type Transition struct {
+	State ProblemState `json:"state"`
+	Time  time.Time    `json:"time"`
+}
+
+// Lifecycle is what the notifications received so far tell about a problem
+type Lifecycle struct {
+	Key           string        `json:"key"` // the PID, or the ProblemID for notifications without PID
+	State         ProblemState  `json:"state"`
+	PreviousState ProblemState  `json:"previousState,omitempty"` // the state known before the current notification, empty for the first one
+	Duplicate     bool          `json:"duplicate,omitempty"`     // whether the current notification repeated the known state
+	Opened        time.Time     `json:"opened"`
+	Closed        *time.Time    `json:"closed,omitempty"` // when the problem got resolved or merged
+	Duration      time.Duration `json:"duration"`         // from opened until closed, or until the current notification for open problems
+	Notifications int           `json:"notifications"`
+	Updated       time.Time     `json:"updated"` // when the latest notification got received
+	Transitions   []*Transition `json:"transitions"`
+}
+
+// StateStore tracks the lifecycles of problems across notifications
+type StateStore struct {
+	file          string
+	retention     time.Duration
+	openRetention time.Duration
+
+	mu         sync.Mutex
+	lifecycles map[string]*Lifecycle
+}
+
+// NewStateStore creates a state store, loading the lifecycles persisted in the configured file
+func NewStateStore(config *StateConfig) (*StateStore, error) {
+	store := &StateStore{file: config.File, retention: 24 * time.Hour, openRetention: 7 * 24 * time.Hour, lifecycles: map[string]*Lifecycle{}}
+	var err error
+	if config.Retention != "" {
+		if store.retention, err = time.ParseDuration(config.Retention); err != nil {
+			return nil, fmt.Errorf("invalid retention: %s", err.Error())
+		}
+	}
+	if config.OpenRetention != "" {
+		if store.openRetention, err = time.ParseDuration(config.OpenRetention); err != nil {
+			return nil, fmt.Errorf("invalid open retention: %s", err.Error())
+		}
+	}
+	if store.file != "" {
+		data, err := ioutil.ReadFile(store.file)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			if err = json.Unmarshal(data, &store.lifecycles); err != nil {
+				return nil, fmt.Errorf("invalid state file '%s': %s", store.file, err.Error())
+			}
+		}
+	}
+	return store, nil
+}
+
+// Track records the state reported by the notification of the event, received at the given time, and sets event.Lifecycle.
+// Events without PID and ProblemID, or without state, are not tracked and get no lifecycle.
+func (store *StateStore) Track(event *ProblemEvent, now time.Time) (*Lifecycle, error) {
+	key, state := keyOf(event), ProblemState(strings.ToUpper(stateOf(event)))
+	if state == "CLOSED" {
+		state = ProblemStates.Resolved
+	}
+	if key == "" || state == "" {
+		return nil, nil
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.expire(now)
+	lifecycle, found := store.lifecycles[key]
+	event.untracked = nil
+	if found {
+		event.untracked = copyOf(lifecycle)
+	} else {
+		lifecycle = &Lifecycle{Key: key, Opened: now, Transitions: []*Transition{}}
+		if problem := problemOf(event); problem != nil && problem.StartTime > 0 {
+			lifecycle.Opened = millis(problem.StartTime)
+		}
+		store.lifecycles[key] = lifecycle
+	}
+	lifecycle.PreviousState = lifecycle.State
+	lifecycle.Duplicate = found && lifecycle.State == state
+	lifecycle.Notifications++
+	lifecycle.Updated = now
+	if !lifecycle.Duplicate {
+		lifecycle.State = state
+		lifecycle.Transitions = append(lifecycle.Transitions, &Transition{State: state, Time: now})
+		if state == ProblemStates.Open {
+			lifecycle.Closed = nil
+		} else {
+			closed := now
+			if problem := problemOf(event); problem != nil && problem.EndTime > 0 {
+				closed = millis(problem.EndTime)
+			}
+			lifecycle.Closed = &closed
+		}
+	}
+	if lifecycle.Closed != nil {
+		lifecycle.Duration = lifecycle.Closed.Sub(lifecycle.Opened)
+	} else {
+		lifecycle.Duration = now.Sub(lifecycle.Opened)
+	}
+
+	if err := store.save(); err != nil {
+		return nil, err
+	}
+	event.Lifecycle = copyOf(lifecycle)
+	return event.Lifecycle, nil
+}
+
+// Untrack reverts the lifecycle of the problem to what it was before the notification of the event got tracked,
+// unless further notifications for the problem got tracked meanwhile.
+// The listener does so if it fails to accept the event, so the notification retried by Dynatrace isn't taken as a duplicate.
+func (store *StateStore) Untrack(event *ProblemEvent) error {
+	if event.Lifecycle == nil {
+		return nil
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	key := event.Lifecycle.Key
+	lifecycle, found := store.lifecycles[key]
+	if !found || lifecycle.Notifications != event.Lifecycle.Notifications {
+		return nil
+	}
+	if event.untracked == nil {
+		delete(store.lifecycles, key)
+	} else {
+		store.lifecycles[key] = event.untracked
+	}
+	event.Lifecycle, event.untracked = nil, nil
+	return store.save()
+}
+
+// Get returns the lifecycle of the problem with the given PID or ProblemID, nil if unknown
+func (store *StateStore) Get(key string) *Lifecycle {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	lifecycle, found := store.lifecycles[key]
+	if !found {
+		return nil
+	}
+	return copyOf(lifecycle)
+}
+
+// expire forgets the problems closed longer than the retention ago,
+// and the open problems without notification for longer than the open retention
+func (store *StateStore) expire(now time.Time) {
+	for key, lifecycle := range store.lifecycles {
+		if lifecycle.Closed != nil {
+			if now.Sub(*lifecycle.Closed) > store.retention {
+				delete(store.lifecycles, key)
+			}
+			continue
+		}
+		updated := lifecycle.Updated
+		if updated.IsZero() {
+			// persisted before the time of the latest notification got recorded
+			updated = lifecycle.Opened
+			if n := len(lifecycle.Transitions); n > 0 {
+				updated = lifecycle.Transitions[n-1].Time
+			}
+		}
+		if now.Sub(updated) > store.openRetention {
+			delete(store.lifecycles, key)
+		}
+	}
+}
+
+func copyOf(lifecycle *Lifecycle) *Lifecycle {
+	snapshot := *lifecycle
+	snapshot.Transitions = append([]*Transition{}, lifecycle.Transitions...)
+	return &snapshot
+}
+
+func (store *StateStore) save() error {
+	if store.file == "" {
+		return nil
+	}
+	data, err := json.Marshal(store.lifecycles)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(store.file+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(store.file+".tmp", store.file)
+}
+
+func keyOf(event *ProblemEvent) string {
+	if event.Notification != nil {
+		if event.Notification.PID != "" {
+			return event.Notification.PID
+		}
+		return event.Notification.ProblemID
+	}
+	if event.Problem != nil {
+		return event.Problem.ID
+	}
+	return ""
+}
+
+// problemOf returns the problem details, which at the time of receipt are known only if contained in the notification
+func problemOf(event *ProblemEvent) *problems.Problem {
+	if event.Problem != nil {
+		return event.Problem
+	}
+	if event.Notification != nil {
+		return event.Notification.ProblemDetailsJSON
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package notification_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func TestStateStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config := &notification.StateConfig{File: filepath.Join(dir, "state.json"), Retention: "1h"}
+
+	store, err := notification.NewStateStore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	track := func(store *notification.StateStore, state string, minutes int) *notification.Lifecycle {
+		event := &notification.ProblemEvent{Notification: &notification.Default{PID: "123", State: state}}
+		lifecycle, err := store.Track(event, start.Add(time.Duration(minutes)*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Lifecycle != lifecycle {
+			t.Error("expected the lifecycle to be exposed via the event")
+		}
+		return lifecycle
+	}
+
+	if lifecycle := track(store, "OPEN", 0); lifecycle.Duplicate || lifecycle.PreviousState != "" {
+		t.Errorf("unexpected first lifecycle %+v", lifecycle)
+	}
+	if lifecycle := track(store, "OPEN", 5); !lifecycle.Duplicate || lifecycle.PreviousState != notification.ProblemStates.Open {
+		t.Errorf("expected a duplicate, got %+v", lifecycle)
+	}
+
+	// a restart in between
+	if store, err = notification.NewStateStore(config); err != nil {
+		t.Fatal(err)
+	}
+	lifecycle := track(store, "RESOLVED", 30)
+	if lifecycle.Duplicate || lifecycle.PreviousState != notification.ProblemStates.Open || lifecycle.Duration != 30*time.Minute {
+		t.Errorf("unexpected resolved lifecycle %+v", lifecycle)
+	}
+	if lifecycle.Notifications != 3 || len(lifecycle.Transitions) != 2 {
+		t.Errorf("expected 3 notifications and 2 transitions, got %d and %d", lifecycle.Notifications, len(lifecycle.Transitions))
+	}
+
+	// resolved problems are forgotten after the retention
+	if lifecycle := track(store, "RESOLVED", 120); lifecycle.Duplicate || lifecycle.PreviousState != "" {
+		t.Errorf("expected the problem to be forgotten, got %+v", lifecycle)
+	}
+}
+
+func TestStateStoreUntrack(t *testing.T) {
+	store, err := notification.NewStateStore(&notification.StateConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	track := func(state string, minutes int) *notification.ProblemEvent {
+		event := &notification.ProblemEvent{Notification: &notification.Default{PID: "123", State: state}}
+		if _, err := store.Track(event, start.Add(time.Duration(minutes)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+
+	if err := store.Untrack(track("OPEN", 0)); err != nil {
+		t.Fatal(err)
+	}
+	if lifecycle := store.Get("123"); lifecycle != nil {
+		t.Errorf("expected the first notification to be forgotten, got %+v", lifecycle)
+	}
+	if event := track("OPEN", 1); event.Lifecycle.Duplicate {
+		t.Error("expected the retried notification not to be a duplicate")
+	}
+	if err := store.Untrack(track("RESOLVED", 10)); err != nil {
+		t.Fatal(err)
+	}
+	if lifecycle := store.Get("123"); lifecycle.State != notification.ProblemStates.Open || lifecycle.Notifications != 1 || len(lifecycle.Transitions) != 1 {
+		t.Errorf("expected the lifecycle to be open again, got %+v", lifecycle)
+	}
+
+	// notifications tracked meanwhile are not reverted
+	resolved := track("RESOLVED", 20)
+	track("OPEN", 21)
+	if err := store.Untrack(resolved); err != nil {
+		t.Fatal(err)
+	}
+	if lifecycle := store.Get("123"); lifecycle.State != notification.ProblemStates.Open || lifecycle.Notifications != 3 {
+		t.Errorf("expected the later notification to be kept, got %+v", lifecycle)
+	}
+}
+
+func TestStateStoreOpenRetention(t *testing.T) {
+	store, err := notification.NewStateStore(&notification.StateConfig{OpenRetention: "2h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	track := func(pid string, minutes int) {
+		event := &notification.ProblemEvent{Notification: &notification.Default{PID: pid, State: "OPEN"}}
+		if _, err := store.Track(event, start.Add(time.Duration(minutes)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	track("1", 0)
+	track("2", 0)
+	track("2", 90) // a repeated notification keeps the problem
+	track("3", 180)
+	if lifecycle := store.Get("1"); lifecycle != nil {
+		t.Errorf("expected the open problem without notification for 3h to be forgotten, got %+v", lifecycle)
+	}
+	if lifecycle := store.Get("2"); lifecycle == nil {
+		t.Error("expected the open problem notified 90m ago to be kept")
+	}
+
+	if _, err := notification.NewStateStore(&notification.StateConfig{OpenRetention: "a week"}); err == nil {
+		t.Error("expected an error for an invalid open retention")
+	}
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template renders problem events into outbound payloads using Go's text/template.
+// The template is executed with the ProblemEvent as data, e.g. `{{ .Notification.Title }}`.
+// Besides the built-in functions the following helpers are available:
+//
+//	tags .                  the tags of the notification, the problem details and the impacted entities
+//	tag . "key"             the value of the first tag with the given key, empty if there is no such tag
+//	entities .              the names of the impacted entities
+//	severity .              the severity of the problem, translated by the severity mapping
+//	state .                 the state of the problem, e.g. `OPEN`
+//	millis 1593000000000    converts a timestamp in UTC milliseconds, as used by the problem details, into a time
+//	formatTime "2006-01-02T15:04:05Z07:00" t   formats a time or a timestamp in UTC milliseconds
+//	now                     the current time
+//	json v                  v encoded as JSON
+//	xml "s"                 s escaped for the use within XML
+//	join list ", "          joins a list of strings
+//	upper "s", lower "s"    changes the case of s
+//	default "fallback" v    v, or the fallback if v is empty
+type Template struct {
+	template *template.Template
+}
+
+// NewTemplate parses a template.
+// The severity mapping translates the severities of problems, e.g. `AVAILABILITY`, into the ones of the target system.
+func NewTemplate(name string, text string, severities map[string]string) (*Template, error) {
+	funcs := template.FuncMap{
+		"tags": func(event *ProblemEvent) []string {
+			tags := []string{}
+			for _, tag := range TagsOf(event) {
+				tags = append(tags, tag.String())
+			}
+			return tags
+		},
+		"tag": func(event *ProblemEvent, key string) string {
+			for _, tag := range TagsOf(event) {
+				if tag.Key == key && tag.Value != nil {
+					return *tag.Value
+				}
+			}
+			return ""
+		},
+		"entities": EntityNamesOf,
+		"severity": func(event *ProblemEvent) string {
+			severity := severityOf(event)
+			if mapped, found := severities[severity]; found {
+				return mapped
+			}
+			return severity
+		},
+		"state":  stateOf,
+		"millis": millis,
+		"formatTime": func(layout string, t interface{}) (string, error) {
+			switch v := t.(type) {
+			case time.Time:
+				return v.Format(layout), nil
+			case *time.Time:
+				if v == nil {
+					return "", nil
+				}
+				return v.Format(layout), nil
+			case int64:
+				return millis(v).Format(layout), nil
+			case int:
+				return millis(int64(v)).Format(layout), nil
+			case float64:
+				// numbers decoded from JSON
+				return millis(int64(v)).Format(layout), nil
+			}
+			return "", fmt.Errorf("formatTime: unsupported value %v", t)
+		},
+		"now": func() time.Time {
+			return time.Now().UTC()
+		},
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"xml": func(s string) (string, error) {
+			var buf bytes.Buffer
+			err := xml.EscapeText(&buf, []byte(s))
+			return buf.String(), err
+		},
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"default": func(fallback string, v string) string {
+			if v == "" {
+				return fallback
+			}
+			return v
+		},
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{template: tmpl}, nil
+}
+
+// Render executes the template for the given event
+func (tmpl *Template) Render(event *ProblemEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.template.Execute(&buf, event); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func millis(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC()
+}
+
+// EntityNamesOf returns the names of the impacted entities, as far as they are known
+func EntityNamesOf(event *ProblemEvent) []string {
+	names := []string{}
+	known := map[string]bool{}
+	add := func(name string) {
+		if name != "" && !known[name] {
+			known[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, entity := range event.Entities {
+		add(entity.Name)
+	}
+	if event.Problem != nil {
+		for _, impact := range event.Problem.RankedImpacts {
+			add(impact.EntityName)
+		}
+	}
+	if len(names) == 0 && event.Notification != nil {
+		add(event.Notification.ImpactedEntity)
+	}
+	return names
+}
@@ -0,0 +1,42 @@
+package notification_test
+
+import (
+	"testing"
+
+	"github.com/dtcookie/dynatrace/notification"
+)
+
+func TestFormatTime(t *testing.T) {
+	event := &notification.ProblemEvent{Notification: &notification.Default{PID: "4242", State: "OPEN"}}
+	for _, test := range []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"int", `{{ formatTime "2006-01-02 15:04" 1593000000000 }}`, "2020-06-24 12:00"},
+		{"float64", `{{ formatTime "2006-01-02 15:04" 1593000000000.0 }}`, "2020-06-24 12:00"},
+		{"time", `{{ formatTime "2006-01-02 15:04" (millis 1593000000000) }}`, "2020-06-24 12:00"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := notification.NewTemplate(test.name, test.text, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := tmpl.Render(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, string(data))
+			}
+		})
+	}
+
+	tmpl, err := notification.NewTemplate("string", `{{ formatTime "2006-01-02" "yesterday" }}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Render(event); err == nil {
+		t.Error("expected strings to be rejected")
+	}
+}